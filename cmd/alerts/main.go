@@ -0,0 +1,123 @@
+// Command alerts is the periodic job evaluating saved searches. Each run searches every saved
+// query over the documents crawled since it last ran and delivers the new matches through the
+// webhook, email or file notifiers of its alert channels. It runs once, or every interval
+// until interrupted.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/elasticsearch"
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/embedding"
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/notification"
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/services"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/di"
+	"github.com/mohamedshehata15/intelli-index/pkg/config"
+)
+
+func main() {
+	configPath := flag.String("config", "", "Path to configuration file (if not specified, environment variables will be used)")
+	envFile := flag.String("env", ".env", "Path to .env file for environment variables")
+	backend := flag.String("backend", "database", "Storage holding the documents and saved searches: database or elasticsearch")
+	interval := flag.Duration("interval", 0, "Time between evaluations; evaluate once when zero")
+	flag.Parse()
+
+	_, _, _ = config.LoadEnvFile(*envFile)
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	service, err := newSavedSearchService(*backend, cfg)
+	if err != nil {
+		log.Fatalf("Failed to create saved search service: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if *interval <= 0 {
+		if !evaluate(ctx, service) {
+			stop()
+			os.Exit(1)
+		}
+		return
+	}
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		evaluate(ctx, service)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// evaluate runs the saved searches once and logs a summary of the run. It reports whether every
+// saved search was evaluated and alerted.
+func evaluate(ctx context.Context, service incoming.SavedSearchService) bool {
+	report, err := service.EvaluateSavedSearches(ctx, time.Now())
+	if err != nil {
+		log.Printf("Failed to evaluate saved searches: %v", err)
+		return false
+	}
+	for _, failure := range report.Failures {
+		log.Printf("Failed to evaluate saved search %s (%s): %s", failure.Name, failure.SavedSearchID, failure.Error)
+	}
+	log.Printf("Evaluated %d saved searches: %d alerted, %d failed",
+		report.Evaluated, report.Alerted, len(report.Failures))
+	return len(report.Failures) == 0
+}
+
+// newSavedSearchService registers the adapters of the backend and creates the saved search
+// service searching them
+func newSavedSearchService(backend string, cfg *config.Config) (incoming.SavedSearchService, error) {
+	container := di.Bootstrap()
+	err := di.BatchRegister(container,
+		embedding.NewEmbeddingAdapterFactory(),
+		notification.NewNotificationAdapterFactory(&cfg.Notification),
+	)
+	if err != nil {
+		return nil, err
+	}
+	options := []services.SearchServiceOption{services.WithEmbedder(embedding.GetEmbedder(container))}
+	notifiers := notification.GetAlertNotifiers(container)
+
+	switch backend {
+	case "database":
+		if err := di.BatchRegister(container, storage.NewStorageAdapterFactory(&cfg.Database)); err != nil {
+			return nil, err
+		}
+		search := services.NewSearchService(storage.GetDocumentRepository(container), storage.GetIndexRepository(container), options...)
+		return services.NewSavedSearchService(storage.GetSavedSearchRepository(container), search, notifiers...), nil
+	case "elasticsearch":
+		if err := di.BatchRegister(container, elasticsearch.NewElasticsearchAdapterFactory(&cfg.Elastic)); err != nil {
+			return nil, err
+		}
+		search := services.NewSearchService(elasticsearch.GetDocumentRepository(container), elasticsearch.GetIndexRepository(container), options...)
+		return services.NewSavedSearchService(elasticsearch.GetSavedSearchRepository(container), search, notifiers...), nil
+	}
+	return nil, fmt.Errorf("unknown backend %q", backend)
+}
+
+// loadConfig loads configuration from file or environment variables
+func loadConfig(configPath string) (*config.Config, error) {
+	if configPath != "" {
+		return config.LoadFromFile(configPath)
+	}
+	if foundConfigPath := config.FindConfigFile(""); foundConfigPath != "" {
+		return config.LoadFromFile(foundConfigPath)
+	}
+	return config.Load()
+}
@@ -13,7 +13,11 @@ import (
 
 	httpAdapter "github.com/mohamedshehata15/intelli-index/internal/adapters/incoming/http"
 	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/elasticsearch"
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/notification"
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/percolation"
 	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/services"
 	"github.com/mohamedshehata15/intelli-index/internal/pkg/di"
 	"github.com/mohamedshehata15/intelli-index/pkg/config"
 )
@@ -41,10 +45,16 @@ func main() {
 	err = di.BatchRegister(container,
 		elasticsearch.NewElasticsearchAdapterFactory(&cfg.Elastic),
 		storage.NewStorageAdapterFactory(&cfg.Database),
+		notification.NewNotificationAdapterFactory(&cfg.Notification),
+		// Documents are written through the percolating repository, so that standing queries
+		// are matched against every saved document
+		percolation.NewPercolationAdapterFactory(elasticsearch.GetDocumentRepository, newPercolatorService),
 	)
 	if err != nil {
 		log.Fatalf("Failed to register adapters: %v", err)
 	}
+	documents := percolation.GetDocumentRepository(container)
+	defer documents.Close()
 
 	// Database Migration via flags or config
 	if *runMigrations || cfg.Database.AutoMigrate {
@@ -64,8 +74,12 @@ func main() {
 		}
 	}
 
-	// Create HTTP server
-	server := httpAdapter.NewServer()
+	// Create HTTP server, authenticating callers when it runs behind an authenticating proxy
+	var authenticator httpAdapter.Authenticator
+	if cfg.Server.TrustUserHeaders {
+		authenticator = httpAdapter.UserFromHeaders
+	}
+	server := httpAdapter.NewServer(authenticator)
 	serverAddr := fmt.Sprintf(":%d", cfg.Server.Port)
 	srv := &http.Server{
 		Addr:    serverAddr,
@@ -93,6 +107,12 @@ func main() {
 
 }
 
+// newPercolatorService creates the percolator service matching documents with the standing
+// queries of Elasticsearch and publishing the matches to the configured subscribers
+func newPercolatorService(container *di.Container) incoming.PercolatorService {
+	return services.NewPercolatorService(elasticsearch.GetPercolator(container), notification.GetMatchSubscribers(container)...)
+}
+
 // loadConfig loads configuration from file or environment variables
 func loadConfig(configPath, envFile string) (*config.Config, error) {
 	loaded, loadedPath, _ := config.LoadEnvFile(envFile)
@@ -0,0 +1,111 @@
+// Command ltrtrain trains the learning-to-rank model used to re-rank the top results of searches.
+// Training data comes either from a judgment list, whose queries are run against a SQLite database
+// seeded with a corpus, or from the searches and result clicks logged in a SQLite database. The
+// trained linear or gradient-boosted-tree model is written as JSON, ready for releval -model.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/services"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/relevancedata"
+)
+
+func main() {
+	judgmentsPath := flag.String("judgments", "", "Path to a judgment file to train from; trains from logged clicks when empty")
+	corpusPath := flag.String("corpus", "", "Path to a corpus to seed, one JSON document per line")
+	database := flag.String("db", ":memory:", "SQLite database to seed and search, holding the query log when training from clicks")
+	days := flag.Int("days", 30, "Number of days of logged clicks to train from")
+	limit := flag.Int("limit", 1000, "Maximum number of clicked searches to train from")
+	candidates := flag.Int("candidates", 50, "Number of candidates retrieved per query")
+	modelType := flag.String("type", string(domain.LinearModel), "Model type: linear or gbdt")
+	features := flag.String("features", "", "Comma-separated features to use; all features when empty")
+	iterations := flag.Int("iterations", 0, "Training epochs of a linear model, or trees of a gbdt model; the default when zero")
+	learningRate := flag.Float64("learning-rate", 0, "Learning rate; the default when zero")
+	depth := flag.Int("depth", 0, "Maximum depth of the trees of a gbdt model; the default when zero")
+	output := flag.String("out", "", "Path to write the model to; standard output when empty")
+	flag.Parse()
+
+	if *judgmentsPath == "" && *database == ":memory:" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	options := domain.DefaultRankingTrainingOptions(domain.RankingModelType(*modelType))
+	if *features != "" {
+		options.Features = strings.Split(*features, ",")
+	}
+	if *iterations > 0 {
+		options.Iterations = *iterations
+	}
+	if *learningRate > 0 {
+		options.LearningRate = *learningRate
+	}
+	if *depth > 0 {
+		options.MaxDepth = *depth
+	}
+
+	var documents []*domain.Document
+	if *corpusPath != "" {
+		var err error
+		if documents, err = relevancedata.LoadCorpus(*corpusPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	db, err := relevancedata.OpenDatabase(ctx, *database, documents)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	queryLog := db.Adapter.QueryLogRepository()
+	trainer := services.NewRankingTrainingService(db.Documents, services.NewFeatureExtractor(queryLog), queryLog)
+
+	var samples []domain.RankingSample
+	if *judgmentsPath != "" {
+		judgments, err := relevancedata.LoadJudgments(*judgmentsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		samples, err = trainer.SamplesFromJudgments(ctx, judgments, *candidates)
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		now := time.Now()
+		window := domain.AnalyticsWindow{From: now.AddDate(0, 0, -*days), To: now}
+		samples, err = trainer.SamplesFromClicks(ctx, window, *limit, *candidates)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	log.Printf("training a %s model on %d samples", options.Type, len(samples))
+
+	model, err := trainer.Train(ctx, samples, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	data = append(data, '\n')
+
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		log.Fatal(fmt.Errorf("failed to write model: %w", err))
+	}
+}
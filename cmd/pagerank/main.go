@@ -0,0 +1,113 @@
+// Command pagerank is the batch job ranking documents by their links. For each index it builds the
+// link graph of its documents, runs PageRank and writes the result back as the importance rank of
+// every document, along with its incoming and outgoing link counts.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/elasticsearch"
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/internal/core/services"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/di"
+	"github.com/mohamedshehata15/intelli-index/pkg/config"
+)
+
+func main() {
+	configPath := flag.String("config", "", "Path to configuration file (if not specified, environment variables will be used)")
+	envFile := flag.String("env", ".env", "Path to .env file for environment variables")
+	backend := flag.String("backend", "database", "Storage holding the documents: database or elasticsearch")
+	indexName := flag.String("index", "", "Name of the index to rank; every index when empty")
+	defaults := domain.DefaultPageRankOptions()
+	damping := flag.Float64("damping", defaults.Damping, "Probability of following a link rather than jumping to a random document")
+	iterations := flag.Int("iterations", defaults.MaxIterations, "Maximum number of iterations")
+	tolerance := flag.Float64("tolerance", defaults.Tolerance, "L1 change of the ranks below which they have converged")
+	flag.Parse()
+
+	_, _, _ = config.LoadEnvFile(*envFile)
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	indexRepo, links, err := newRepositories(*backend, cfg)
+	if err != nil {
+		log.Fatalf("Failed to create repositories: %v", err)
+	}
+	options := domain.PageRankOptions{Damping: *damping, MaxIterations: *iterations, Tolerance: *tolerance}
+	service := services.NewLinkAnalysisService(indexRepo, links)
+
+	ctx := context.Background()
+	var indexes []*domain.Index
+	if *indexName != "" {
+		index, err := indexRepo.GetByName(ctx, *indexName)
+		if err != nil {
+			log.Fatalf("Failed to get index %s: %v", *indexName, err)
+		}
+		indexes = []*domain.Index{index}
+	} else if indexes, err = indexRepo.List(ctx); err != nil {
+		log.Fatalf("Failed to list indexes: %v", err)
+	}
+
+	failed := false
+	for _, index := range indexes {
+		if err := rankIndex(ctx, service, index, options); err != nil {
+			log.Printf("Failed to rank index %s: %v", index.Name, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// rankIndex ranks the documents of an index and logs a summary of the run
+func rankIndex(ctx context.Context, service incoming.LinkAnalysisService, index *domain.Index, options domain.PageRankOptions) error {
+	report, err := service.RankIndex(ctx, index.ID, options)
+	if err != nil {
+		return err
+	}
+	status := "converged"
+	if !report.Converged {
+		status = "did not converge"
+	}
+	log.Printf("Ranked index %s: %d documents, %d links, %d dangling documents, %s after %d iterations",
+		index.Name, report.Documents, report.Links, report.DanglingDocuments, status, report.Iterations)
+	return nil
+}
+
+// newRepositories registers the adapters of the backend and resolves the repositories the job needs
+func newRepositories(backend string, cfg *config.Config) (outgoing.IndexRepository, outgoing.LinkGraphRepository, error) {
+	container := di.Bootstrap()
+	switch backend {
+	case "database":
+		if err := di.BatchRegister(container, storage.NewStorageAdapterFactory(&cfg.Database)); err != nil {
+			return nil, nil, err
+		}
+		return storage.GetIndexRepository(container), storage.GetLinkGraphRepository(container), nil
+	case "elasticsearch":
+		if err := di.BatchRegister(container, elasticsearch.NewElasticsearchAdapterFactory(&cfg.Elastic)); err != nil {
+			return nil, nil, err
+		}
+		return elasticsearch.GetIndexRepository(container), elasticsearch.GetLinkGraphRepository(container), nil
+	}
+	return nil, nil, fmt.Errorf("unknown backend %q", backend)
+}
+
+// loadConfig loads configuration from file or environment variables
+func loadConfig(configPath string) (*config.Config, error) {
+	if configPath != "" {
+		return config.LoadFromFile(configPath)
+	}
+	if foundConfigPath := config.FindConfigFile(""); foundConfigPath != "" {
+		return config.LoadFromFile(foundConfigPath)
+	}
+	return config.Load()
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// rankingConfigFile is the JSON form of a ranking configuration
+type rankingConfigFile struct {
+	Name                string             `json:"name"`
+	Type                string             `json:"type"`
+	SearchFields        map[string]float32 `json:"search_fields"`
+	MinimumShouldMatch  string             `json:"minimum_should_match"`
+	SkipDiversification bool               `json:"skip_diversification"`
+	SkipReranking       bool               `json:"skip_reranking"`
+	SkipFreshness       bool               `json:"skip_freshness"`
+	Decay               []decayFile        `json:"decay"`
+}
+
+// decayFile is the JSON form of a decay function, with durations such as "720h"
+type decayFile struct {
+	Function string  `json:"function"`
+	Field    string  `json:"field"`
+	Scale    string  `json:"scale"`
+	Offset   string  `json:"offset"`
+	Decay    float64 `json:"decay"`
+	Weight   float64 `json:"weight"`
+}
+
+// toDomain converts the decay function, parsing its durations
+func (f decayFile) toDomain() (domain.DecayFunction, error) {
+	function := domain.DecayFunction{
+		Function: domain.DecayFunctionType(f.Function),
+		Field:    domain.DecayField(f.Field),
+		Decay:    f.Decay,
+		Weight:   f.Weight,
+	}
+	var err error
+	if function.Scale, err = time.ParseDuration(f.Scale); err != nil {
+		return domain.DecayFunction{}, fmt.Errorf("invalid decay scale %q: %w", f.Scale, err)
+	}
+	if f.Offset != "" {
+		if function.Offset, err = time.ParseDuration(f.Offset); err != nil {
+			return domain.DecayFunction{}, fmt.Errorf("invalid decay offset %q: %w", f.Offset, err)
+		}
+	}
+	return function, function.Validate()
+}
+
+// loadRankingConfig reads a ranking configuration, or returns the default search options when
+// no path is given
+func loadRankingConfig(path, defaultName string) (domain.RankingConfig, error) {
+	if path == "" {
+		return domain.RankingConfig{Name: defaultName}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return domain.RankingConfig{}, fmt.Errorf("failed to read ranking configuration: %w", err)
+	}
+	var file rankingConfigFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return domain.RankingConfig{}, fmt.Errorf("invalid ranking configuration %s: %w", path, err)
+	}
+	decay := make([]domain.DecayFunction, 0, len(file.Decay))
+	for _, item := range file.Decay {
+		function, err := item.toDomain()
+		if err != nil {
+			return domain.RankingConfig{}, fmt.Errorf("invalid ranking configuration %s: %w", path, err)
+		}
+		decay = append(decay, function)
+	}
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return domain.RankingConfig{
+		Name:                file.Name,
+		Type:                domain.SearchType(file.Type),
+		SearchFields:        file.SearchFields,
+		MinimumShouldMatch:  file.MinimumShouldMatch,
+		SkipDiversification: file.SkipDiversification,
+		SkipReranking:       file.SkipReranking,
+		SkipFreshness:       file.SkipFreshness,
+		DecayFunctions:      decay,
+	}, nil
+}
@@ -0,0 +1,166 @@
+// Command releval measures the ranking of the search service against a judgment list. It seeds a
+// SQLite database with a corpus, runs every judged query through SearchService.Search and reports
+// NDCG@k, MRR, precision@k and recall@k, optionally diffing a candidate ranking configuration
+// against a baseline. A trained ranking model re-ranks the configurations that do not skip
+// re-ranking. It needs no running services, so it can gate ranking changes in CI.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/services"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/relevancedata"
+)
+
+func main() {
+	judgmentsPath := flag.String("judgments", "", "Path to the judgment file (query, document URL or ID, grade as CSV, or TSV with a .tsv extension)")
+	corpusPath := flag.String("corpus", "", "Path to the corpus to seed, one JSON document per line")
+	database := flag.String("db", ":memory:", "SQLite database to seed and search")
+	baselinePath := flag.String("baseline", "", "Path to the baseline ranking configuration (JSON); default search options when empty")
+	candidatePath := flag.String("candidate", "", "Path to a candidate ranking configuration (JSON) to diff against the baseline")
+	modelPath := flag.String("model", "", "Path to a trained ranking model (JSON) re-ranking the top results")
+	k := flag.Int("k", 10, "Rank cutoff of the metrics")
+	jsonOutput := flag.Bool("json", false, "Print the report as JSON")
+	minNDCG := flag.Float64("min-ndcg", 0, "Exit with status 1 when the mean NDCG of the evaluated configuration is below this value")
+	flag.Parse()
+
+	if *judgmentsPath == "" || *corpusPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	judgments, err := relevancedata.LoadJudgments(*judgmentsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	documents, err := relevancedata.LoadCorpus(*corpusPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	baseline, err := loadRankingConfig(*baselinePath, "baseline")
+	if err != nil {
+		log.Fatal(err)
+	}
+	var model *domain.RankingModel
+	if *modelPath != "" {
+		if model, err = relevancedata.LoadRankingModel(*modelPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// The judgments cover the whole corpus, so the evaluation reads every document whatever its
+	// access control lists
+	ctx := domain.ContextWithAccessScope(context.Background(), &domain.AccessScope{Unrestricted: true})
+	evaluator, closeDB, err := newEvaluator(ctx, *database, documents, model)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeDB()
+
+	var mean domain.RelevanceMetrics
+	if *candidatePath == "" {
+		report, err := evaluator.Evaluate(ctx, judgments, baseline, *k)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printOutput(os.Stdout, report, *jsonOutput, printReport)
+		mean = report.Mean
+	} else {
+		candidate, err := loadRankingConfig(*candidatePath, "candidate")
+		if err != nil {
+			log.Fatal(err)
+		}
+		comparison, err := evaluator.Compare(ctx, judgments, baseline, candidate, *k)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printOutput(os.Stdout, comparison, *jsonOutput, printComparison)
+		mean = comparison.Candidate.Mean
+	}
+
+	if mean.NDCG < *minNDCG {
+		fmt.Fprintf(os.Stderr, "mean NDCG@%d %.4f is below %.4f\n", *k, mean.NDCG, *minNDCG)
+		closeDB()
+		os.Exit(1)
+	}
+}
+
+// newEvaluator seeds the SQLite database and creates an evaluator searching it, re-ranking with
+// the model when one is given
+func newEvaluator(ctx context.Context, database string, documents []*domain.Document, model *domain.RankingModel) (incoming.RelevanceEvaluationService, func(), error) {
+	db, err := relevancedata.OpenDatabase(ctx, database, documents)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options := []services.SearchServiceOption{services.WithEmbedder(db.Embedder)}
+	if model != nil {
+		reranker, err := services.NewLTRReranker(model, services.NewFeatureExtractor(db.Adapter.QueryLogRepository()))
+		if err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		options = append(options, services.WithReranker(reranker, 0))
+	}
+	search := services.NewSearchService(db.Documents, db.Adapter.IndexRepository(), options...)
+	return services.NewRelevanceEvaluationService(search), db.Close, nil
+}
+
+// printOutput prints a value as indented JSON, or with the given text printer
+func printOutput[T any](w io.Writer, value T, asJSON bool, printText func(io.Writer, T)) {
+	if !asJSON {
+		printText(w, value)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// printReport prints the metrics of each query followed by their mean
+func printReport(w io.Writer, report *domain.EvaluationReport) {
+	fmt.Fprintf(w, "configuration %s, k=%d\n\n", report.Config, report.K)
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(table, "QUERY\tNDCG@%d\tMRR\tP@%d\tR@%d\n", report.K, report.K, report.K)
+	for _, evaluation := range report.Queries {
+		printMetricsRow(table, evaluation.Query, evaluation.Metrics)
+	}
+	printMetricsRow(table, "MEAN", report.Mean)
+	_ = table.Flush()
+}
+
+// printComparison prints the metrics of each query under both configurations and their change
+func printComparison(w io.Writer, comparison *domain.EvaluationComparison) {
+	k := comparison.Baseline.K
+	fmt.Fprintf(w, "baseline %s vs candidate %s, k=%d\n\n", comparison.Baseline.Config, comparison.Candidate.Config, k)
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(table, "QUERY\tNDCG@%d\tΔ\tMRR\tΔ\tP@%d\tΔ\tR@%d\tΔ\n", k, k, k)
+	for _, query := range comparison.Queries {
+		printComparisonRow(table, query.Query, query.Candidate, query.Delta)
+	}
+	printComparisonRow(table, "MEAN", comparison.Candidate.Mean, comparison.Delta)
+	_ = table.Flush()
+}
+
+// printMetricsRow prints a row of metrics
+func printMetricsRow(w io.Writer, label string, metrics domain.RelevanceMetrics) {
+	fmt.Fprintf(w, "%s\t%.4f\t%.4f\t%.4f\t%.4f\n", label, metrics.NDCG, metrics.MRR, metrics.Precision, metrics.Recall)
+}
+
+// printComparisonRow prints a row of candidate metrics, each followed by its change from the baseline
+func printComparisonRow(w io.Writer, label string, metrics, delta domain.RelevanceMetrics) {
+	fmt.Fprintf(w, "%s\t%.4f\t%+.4f\t%.4f\t%+.4f\t%.4f\t%+.4f\t%.4f\t%+.4f\n", label,
+		metrics.NDCG, delta.NDCG, metrics.MRR, delta.MRR,
+		metrics.Precision, delta.Precision, metrics.Recall, delta.Recall)
+}
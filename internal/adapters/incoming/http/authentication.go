@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// Headers identifying the user of a request, set by an authenticating reverse proxy
+const (
+	userIDHeader          = "X-User-ID"
+	usernameHeader        = "X-User-Name"
+	userGroupsHeader      = "X-User-Groups"
+	userRolesHeader       = "X-User-Roles"
+	userPermissionsHeader = "X-User-Permissions"
+)
+
+// Authenticator resolves the user making a request. It returns a nil user for anonymous
+// requests and an error for requests whose credentials are invalid.
+type Authenticator func(r *http.Request) (*domain.User, error)
+
+// UserFromHeaders authenticates requests with the identity headers of an authenticating
+// reverse proxy; groups, roles and permissions are comma separated. The headers are trusted
+// as is, so the server must only be reachable through a proxy overwriting them.
+func UserFromHeaders(r *http.Request) (*domain.User, error) {
+	id := strings.TrimSpace(r.Header.Get(userIDHeader))
+	if id == "" {
+		return nil, nil
+	}
+	return &domain.User{
+		ID:          id,
+		Username:    strings.TrimSpace(r.Header.Get(usernameHeader)),
+		Groups:      headerList(r, userGroupsHeader),
+		Roles:       headerList(r, userRolesHeader),
+		Permissions: headerList(r, userPermissionsHeader),
+	}, nil
+}
+
+// authenticate sets the user of every request on its context, so that the services only
+// return the documents it can read. Requests failing authentication are rejected.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := s.authenticator(r)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if user != nil {
+			r = r.WithContext(domain.ContextWithUser(r.Context(), user))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// headerList returns the non-empty comma separated values of a header
+func headerList(r *http.Request, header string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(r.Header.Get(header), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
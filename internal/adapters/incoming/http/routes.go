@@ -6,11 +6,21 @@ import (
 )
 
 type Server struct {
-	router chi.Router
+	router        chi.Router
+	authenticator Authenticator
 }
 
-func NewServer() *Server {
-	return &Server{}
+// NewServer creates the HTTP server. Requests are authenticated with the authenticator, or
+// served anonymously when it is nil.
+func NewServer(authenticator Authenticator) *Server {
+	s := &Server{
+		router:        chi.NewRouter(),
+		authenticator: authenticator,
+	}
+	s.setupMiddleware()
+	s.setupRoutes()
+	s.setupSwagger()
+	return s
 }
 
 func (s *Server) Handler() http.Handler {
@@ -18,7 +28,9 @@ func (s *Server) Handler() http.Handler {
 }
 
 func (s *Server) setupMiddleware() {
-	// TODO: Implement me.
+	if s.authenticator != nil {
+		s.router.Use(s.authenticate)
+	}
 }
 
 func (s *Server) setupRoutes() {
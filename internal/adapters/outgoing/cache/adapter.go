@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/di"
+)
+
+// CacheAdapterFactory implements the di.AdapterRegistrar interface
+type CacheAdapterFactory struct {
+	options Options
+}
+
+var _ di.AdapterRegistrar = (*CacheAdapterFactory)(nil)
+
+// NewCacheAdapterFactory creates a new factory for cache adapters
+func NewCacheAdapterFactory(options Options) *CacheAdapterFactory {
+	return &CacheAdapterFactory{
+		options: options,
+	}
+}
+
+// Register implements the AdapterRegistrar interface
+func (c *CacheAdapterFactory) Register(container *di.Container) error {
+	return RegisterCacheAdapters(container, c.options)
+}
+
+// RegisterCacheAdapters registers all cache implementations with the DI container
+func RegisterCacheAdapters(container *di.Container, options Options) error {
+	// Register search cache implementation
+	container.Register("searchCache", func() (interface{}, error) {
+		return NewSearchCache(options), nil
+	})
+	return nil
+}
+
+// GetSearchCache retrieves the search cache from the container
+func GetSearchCache(container *di.Container) outgoing.SearchCache {
+	return container.MustResolve("searchCache").(outgoing.SearchCache)
+}
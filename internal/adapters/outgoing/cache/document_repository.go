@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// DocumentRepository decorates a document repository so that saving, updating or deleting a
+// document drops the cached search results of its index
+type DocumentRepository struct {
+	outgoing.DocumentRepository
+	cache outgoing.SearchCache
+}
+
+var _ outgoing.DocumentRepository = (*DocumentRepository)(nil)
+
+// NewDocumentRepository wraps a document repository with the search cache it invalidates
+func NewDocumentRepository(repository outgoing.DocumentRepository, cache outgoing.SearchCache) *DocumentRepository {
+	return &DocumentRepository{
+		DocumentRepository: repository,
+		cache:              cache,
+	}
+}
+
+// Save saves the document and invalidates the cached results of its index
+func (r *DocumentRepository) Save(ctx context.Context, document *domain.Document) error {
+	if err := r.DocumentRepository.Save(ctx, document); err != nil {
+		return err
+	}
+	r.cache.InvalidateIndex(document.IndexID)
+	return nil
+}
+
+// Update updates the document and invalidates the cached results of its previous and new index
+func (r *DocumentRepository) Update(ctx context.Context, document *domain.Document) error {
+	var previous *domain.Document
+	if document != nil && document.ID != "" {
+		// The repository reports a missing document itself
+		previous, _ = r.DocumentRepository.GetByID(unrestricted(ctx), document.ID)
+	}
+	if err := r.DocumentRepository.Update(ctx, document); err != nil {
+		return err
+	}
+	if previous != nil && previous.IndexID != document.IndexID {
+		r.cache.InvalidateIndex(previous.IndexID)
+	}
+	r.cache.InvalidateIndex(document.IndexID)
+	return nil
+}
+
+// Delete deletes the document and invalidates the cached results of its index
+func (r *DocumentRepository) Delete(ctx context.Context, id string) error {
+	document, err := r.DocumentRepository.GetByID(unrestricted(ctx), id)
+	if err != nil {
+		return fmt.Errorf("failed to get document: %w", err)
+	}
+	if err := r.DocumentRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if document != nil {
+		r.cache.InvalidateIndex(document.IndexID)
+	}
+	return nil
+}
+
+// unrestricted returns a context reading every document, so that the index of a document hidden
+// from the caller is still invalidated
+func unrestricted(ctx context.Context) context.Context {
+	return domain.ContextWithAccessScope(ctx, &domain.AccessScope{Unrestricted: true})
+}
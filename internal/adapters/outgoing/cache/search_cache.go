@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	// DefaultTTL is how long a search result stays cached when no TTL is set
+	DefaultTTL = 5 * time.Minute
+	// DefaultMaxBytes is the memory budget of the cache when none is set
+	DefaultMaxBytes = 64 << 20
+	// documentOverhead approximates the memory of a cached document besides its text
+	documentOverhead = 256
+)
+
+// Options configures the search result cache
+type Options struct {
+	TTL      time.Duration
+	MaxBytes int64
+}
+
+// entry is a cached search result
+type entry struct {
+	key       string
+	indexIDs  []string
+	result    *domain.SearchResult
+	size      int64
+	expiresAt time.Time
+}
+
+// SearchCache implements the outgoing.SearchCache interface with an in-memory LRU cache whose
+// entries expire after a TTL. The least recently used entries are evicted to stay within the
+// byte budget.
+type SearchCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	maxBytes int64
+	bytes    int64
+	order    *list.List
+	entries  map[string]*list.Element
+	stats    domain.CacheStats
+	now      func() time.Time
+}
+
+var _ outgoing.SearchCache = (*SearchCache)(nil)
+
+// NewSearchCache creates an empty search result cache, using the defaults for unset options
+func NewSearchCache(options Options) *SearchCache {
+	if options.TTL <= 0 {
+		options.TTL = DefaultTTL
+	}
+	if options.MaxBytes <= 0 {
+		options.MaxBytes = DefaultMaxBytes
+	}
+	return &SearchCache{
+		ttl:      options.TTL,
+		maxBytes: options.MaxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+// Get returns a copy of the cached result of a query key, if it has not expired
+func (c *SearchCache) Get(key string) (*domain.SearchResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	cached := element.Value.(*entry)
+	if c.now().After(cached.expiresAt) {
+		c.remove(element)
+		c.stats.Misses++
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	c.stats.Hits++
+	return cached.result.Clone(), true
+}
+
+// Put caches a copy of the result of a query key. Results larger than the whole budget are
+// not cached.
+func (c *SearchCache) Put(key string, indexIDs []string, result *domain.SearchResult) {
+	if key == "" || result == nil {
+		return
+	}
+	size := resultSize(key, result)
+	if size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	cached := &entry{
+		key:       key,
+		indexIDs:  append([]string(nil), indexIDs...),
+		result:    result.Clone(),
+		size:      size,
+		expiresAt: c.now().Add(c.ttl),
+	}
+	c.entries[key] = c.order.PushFront(cached)
+	c.bytes += size
+	for c.bytes > c.maxBytes {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// InvalidateIndex drops the entries of searches on the index and of searches on every index
+func (c *SearchCache) InvalidateIndex(indexID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		if cached := element.Value.(*entry); cached.covers(indexID) {
+			c.remove(element)
+			c.stats.Invalidations++
+		}
+		element = next
+	}
+}
+
+// Stats returns the hit, miss, eviction and invalidation counts and the current size of the cache
+func (c *SearchCache) Stats() domain.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// remove drops an entry from the cache
+func (c *SearchCache) remove(element *list.Element) {
+	cached := c.order.Remove(element).(*entry)
+	delete(c.entries, cached.key)
+	c.bytes -= cached.size
+}
+
+// covers reports whether the result of the entry may contain documents of the index
+func (e *entry) covers(indexID string) bool {
+	if len(e.indexIDs) == 0 {
+		return true
+	}
+	for _, id := range e.indexIDs {
+		if id == indexID {
+			return true
+		}
+	}
+	return false
+}
+
+// resultSize approximates the memory held by a cached result
+func resultSize(key string, result *domain.SearchResult) int64 {
+	size := int64(len(key) + documentOverhead)
+	for _, doc := range result.Documents {
+		size += documentOverhead + int64(len(doc.ID)+len(doc.URL)+len(doc.Title)+len(doc.Content)+
+			len(doc.MetaDesc)+len(doc.IndexID)+4*len(doc.Embedding))
+		for _, keyword := range doc.MetaKeywords {
+			size += int64(len(keyword))
+		}
+		for _, link := range doc.Links {
+			size += int64(len(link))
+		}
+	}
+	for _, facet := range result.Facets {
+		size += documentOverhead * int64(len(facet.Buckets)+1)
+	}
+	return size
+}
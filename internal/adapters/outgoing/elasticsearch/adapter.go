@@ -43,6 +43,56 @@ func RegisterElasticsearchAdapters(container *di.Container, cfg *config.ElasticC
 		return NewIndexRepository(client), nil
 	})
 
+	// Register query history repository
+	container.Register("queryHistoryRepository", func() (interface{}, error) {
+		return NewQueryHistoryRepository(client), nil
+	})
+
+	// Register suggestion completer
+	container.Register("suggestionCompleter", func() (interface{}, error) {
+		return NewSuggestionCompleter(client), nil
+	})
+
+	// Register suggestion feedback repository
+	container.Register("suggestionFeedbackRepository", func() (interface{}, error) {
+		return NewSuggestionFeedbackRepository(client), nil
+	})
+
+	// Register spelling corrector
+	container.Register("spellingCorrector", func() (interface{}, error) {
+		return NewSpellingCorrector(client), nil
+	})
+
+	// Register text analyzer
+	container.Register("textAnalyzer", func() (interface{}, error) {
+		return NewTextAnalyzer(client), nil
+	})
+
+	// Register query log repository
+	container.Register("queryLogRepository", func() (interface{}, error) {
+		return NewQueryLogRepository(client), nil
+	})
+
+	// Register link graph repository
+	container.Register("linkGraphRepository", func() (interface{}, error) {
+		return NewLinkGraphRepository(client), nil
+	})
+
+	// Register saved search repository
+	container.Register("savedSearchRepository", func() (interface{}, error) {
+		return NewSavedSearchRepository(client), nil
+	})
+
+	// Register percolator
+	container.Register("percolator", func() (interface{}, error) {
+		return NewPercolator(client), nil
+	})
+
+	// Register query rule repository
+	container.Register("queryRuleRepository", func() (interface{}, error) {
+		return NewQueryRuleRepository(client), nil
+	})
+
 	return nil
 }
 
@@ -55,3 +105,53 @@ func GetDocumentRepository(container *di.Container) outgoing.DocumentRepository
 func GetIndexRepository(container *di.Container) outgoing.IndexRepository {
 	return container.MustResolve("indexRepository").(outgoing.IndexRepository)
 }
+
+// GetQueryHistoryRepository retrieves the query history repository from the container
+func GetQueryHistoryRepository(container *di.Container) outgoing.QueryHistoryRepository {
+	return container.MustResolve("queryHistoryRepository").(outgoing.QueryHistoryRepository)
+}
+
+// GetSuggestionCompleter retrieves the suggestion completer from the container
+func GetSuggestionCompleter(container *di.Container) outgoing.SuggestionCompleter {
+	return container.MustResolve("suggestionCompleter").(outgoing.SuggestionCompleter)
+}
+
+// GetSuggestionFeedbackRepository retrieves the suggestion feedback repository from the container
+func GetSuggestionFeedbackRepository(container *di.Container) outgoing.SuggestionFeedbackRepository {
+	return container.MustResolve("suggestionFeedbackRepository").(outgoing.SuggestionFeedbackRepository)
+}
+
+// GetSpellingCorrector retrieves the spelling corrector from the container
+func GetSpellingCorrector(container *di.Container) outgoing.SpellingCorrector {
+	return container.MustResolve("spellingCorrector").(outgoing.SpellingCorrector)
+}
+
+// GetTextAnalyzer retrieves the text analyzer from the container
+func GetTextAnalyzer(container *di.Container) outgoing.TextAnalyzer {
+	return container.MustResolve("textAnalyzer").(outgoing.TextAnalyzer)
+}
+
+// GetQueryLogRepository retrieves the query log repository from the container
+func GetQueryLogRepository(container *di.Container) outgoing.QueryLogRepository {
+	return container.MustResolve("queryLogRepository").(outgoing.QueryLogRepository)
+}
+
+// GetLinkGraphRepository retrieves the link graph repository from the container
+func GetLinkGraphRepository(container *di.Container) outgoing.LinkGraphRepository {
+	return container.MustResolve("linkGraphRepository").(outgoing.LinkGraphRepository)
+}
+
+// GetSavedSearchRepository retrieves the saved search repository from the container
+func GetSavedSearchRepository(container *di.Container) outgoing.SavedSearchRepository {
+	return container.MustResolve("savedSearchRepository").(outgoing.SavedSearchRepository)
+}
+
+// GetPercolator retrieves the percolator from the container
+func GetPercolator(container *di.Container) outgoing.Percolator {
+	return container.MustResolve("percolator").(outgoing.Percolator)
+}
+
+// GetQueryRuleRepository retrieves the query rule repository from the container
+func GetQueryRuleRepository(container *di.Container) outgoing.QueryRuleRepository {
+	return container.MustResolve("queryRuleRepository").(outgoing.QueryRuleRepository)
+}
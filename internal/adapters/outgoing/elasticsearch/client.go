@@ -19,6 +19,10 @@ type Client struct {
 	indexPrefix  string
 	retryBackoff time.Duration
 	maxRetries   int
+	mappings     mappingGuard
+	analyzers    analyzerRegistry
+	// snippetSize is the default length of highlight snippets in characters
+	snippetSize int
 }
 
 // ClientOption is a function that configures a Client
@@ -44,6 +48,7 @@ func initializeClient(cfg *config.ElasticConfig) *Client {
 		indexPrefix:  cfg.IndexPrefix,
 		retryBackoff: 200 * time.Millisecond,
 		maxRetries:   3,
+		snippetSize:  cfg.SnippetSize,
 	}
 }
 
@@ -0,0 +1,52 @@
+package elasticsearch
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// accessFields are the fields holding the access control lists of a document
+var accessFields = []string{"allowed_users", "allowed_groups", "allowed_roles"}
+
+// accessFilter returns the filter clause restricting a search to the documents the scope can
+// read: the public documents, without access control lists, and those listing one of its
+// principals. Unrestricted scopes return nil.
+func accessFilter(scope *domain.AccessScope) map[string]interface{} {
+	if scope == nil || scope.Unrestricted {
+		return nil
+	}
+	public := make([]interface{}, 0, len(accessFields))
+	for _, field := range accessFields {
+		public = append(public, map[string]interface{}{"exists": map[string]interface{}{"field": field}})
+	}
+	should := []interface{}{
+		map[string]interface{}{"bool": map[string]interface{}{"must_not": public}},
+	}
+	for i, principals := range [][]string{scope.Users, scope.Groups, scope.Roles} {
+		if len(principals) > 0 {
+			should = append(should, map[string]interface{}{"terms": map[string]interface{}{accessFields[i]: principals}})
+		}
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should":               should,
+			"minimum_should_match": 1,
+		},
+	}
+}
+
+// restrictAccess restricts the query of a search body to the documents the access scope of the
+// context can read. The restriction is a filter, so it leaves the scores unchanged.
+func restrictAccess(ctx context.Context, body map[string]interface{}) {
+	filter := accessFilter(domain.AccessScopeFromContext(ctx))
+	if filter == nil {
+		return
+	}
+	body["query"] = map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":   body["query"],
+			"filter": filter,
+		},
+	}
+}
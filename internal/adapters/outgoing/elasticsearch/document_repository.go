@@ -19,6 +19,12 @@ const (
 	DocumentIndex = "documents"
 )
 
+const (
+	// knnCandidateFactor and knnMinCandidates size the candidate list examined per shard by kNN search
+	knnCandidateFactor = 4
+	knnMinCandidates   = 100
+)
+
 // DocumentRepository implements the outgoing.DocumentRepository interface using Elasticsearch
 type DocumentRepository struct {
 	client *Client
@@ -42,6 +48,9 @@ func (d DocumentRepository) Save(ctx context.Context, document *domain.Document)
 	if document.ID == "" {
 		document.ID = uuid.NewString()
 	}
+	if err := d.client.EnsureFieldMappings(ctx, DocumentIndex, documentFieldMappings); err != nil {
+		return fmt.Errorf("error preparing document index: %w", err)
+	}
 	modelDocument := models.FromDomain(document)
 	if err := d.client.IndexDocument(ctx, DocumentIndex, document.ID, modelDocument); err != nil {
 		return fmt.Errorf("error indexing document: %w", err)
@@ -60,7 +69,11 @@ func (d DocumentRepository) GetByID(ctx context.Context, id string) (*domain.Doc
 		}
 		return nil, fmt.Errorf("error getting document by ID: %w", err)
 	}
-	return modelDocument.ToDomain(), nil
+	document := modelDocument.ToDomain()
+	if !domain.AccessScopeFromContext(ctx).CanRead(document) {
+		return nil, nil
+	}
+	return document, nil
 }
 
 func (d DocumentRepository) GetByURL(ctx context.Context, url string) (*domain.Document, error) {
@@ -74,6 +87,7 @@ func (d DocumentRepository) GetByURL(ctx context.Context, url string) (*domain.D
 			},
 		},
 	}
+	restrictAccess(ctx, query)
 	res, err := d.client.PerformRequest(ctx, &esapi.SearchRequest{
 		Index: []string{d.client.IndexNameWithPrefix(DocumentIndex)},
 		Body:  bytes.NewReader(mustMarshalJSON(query)),
@@ -81,19 +95,14 @@ func (d DocumentRepository) GetByURL(ctx context.Context, url string) (*domain.D
 	if err != nil {
 		return nil, fmt.Errorf("error parsing for document by URL: %w", err)
 	}
-	var searchResult map[string]interface{}
-	if err := parseResponse(res.Body, &searchResult); err != nil {
-		return nil, fmt.Errorf("error parsing search response: %w", err)
+	response, err := parseSearchResponse(res.Body)
+	if err != nil {
+		return nil, err
 	}
-	hitsObj := searchResult["hits"].(map[string]interface{})
-	hitsTotal := int(hitsObj["total"].(map[string]interface{})["value"].(float64))
-	if hitsTotal == 0 {
+	if len(response.Hits.Hits) == 0 {
 		return nil, nil
 	}
-	hits := hitsObj["hits"].([]interface{})
-	hit := hits[0].(map[string]interface{})
-	doc := hit["_source"].(*models.Document)
-	return doc.ToDomain(), nil
+	return response.Hits.Hits[0].toDomain(), nil
 }
 
 func (d DocumentRepository) Delete(ctx context.Context, id string) error {
@@ -123,6 +132,9 @@ func (d DocumentRepository) Update(ctx context.Context, document *domain.Documen
 	if !exists {
 		return fmt.Errorf("document with ID %s does not exist", document.ID)
 	}
+	if err := d.client.EnsureFieldMappings(ctx, DocumentIndex, documentFieldMappings); err != nil {
+		return fmt.Errorf("error preparing document index: %w", err)
+	}
 	modelDocument := models.FromDomain(document)
 	if err := d.client.UpdateDocument(ctx, DocumentIndex, document.ID, modelDocument); err != nil {
 		return fmt.Errorf("error updating document: %w", err)
@@ -151,6 +163,7 @@ func (d DocumentRepository) List(ctx context.Context, page, pageSize int) ([]*do
 			{"last_crawled": map[string]interface{}{"order": "desc"}},
 		},
 	}
+	restrictAccess(ctx, query)
 	res, err := d.client.PerformRequest(ctx, &esapi.SearchRequest{
 		Index: []string{d.client.IndexNameWithPrefix(DocumentIndex)},
 		Body:  bytes.NewReader(mustMarshalJSON(query)),
@@ -158,30 +171,238 @@ func (d DocumentRepository) List(ctx context.Context, page, pageSize int) ([]*do
 	if err != nil {
 		return nil, 0, fmt.Errorf("error parsing for documents: %w", err)
 	}
-	var searchResult map[string]interface{}
-	if err := parseResponse(res.Body, &searchResult); err != nil {
-		return nil, 0, fmt.Errorf("error parsing search response: %w", err)
-	}
-	hitsObj := searchResult["hits"].(map[string]interface{})
-	total := int(hitsObj["total"].(map[string]interface{})["value"].(float64))
-	hits := hitsObj["hits"].([]interface{})
-
-	documents := make([]*domain.Document, 0, len(hits))
-	for _, hit := range hits {
-		hitMap := hit.(map[string]interface{})
-		doc := hitMap["_source"].(*models.Document)
-		doc.ID = hitMap["_id"].(string)
-		if score, ok := hitMap["_score"].(float64); ok && score > 0 {
-			doc.Score = score
-		}
-		documents = append(documents, doc.ToDomain())
+	response, err := parseSearchResponse(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return response.documents(), response.Hits.Total.Value, nil
+}
+
+func (d DocumentRepository) ListAfter(ctx context.Context, cursor string, pageSize int) ([]*domain.Document, string, error) {
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	body := map[string]interface{}{
+		"query": map[string]interface{}{
+			"match_all": map[string]interface{}{},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{"last_crawled": map[string]interface{}{"order": "desc"}},
+		},
+	}
+	restrictAccess(ctx, body)
+	documents, _, nextCursor, err := d.searchAfter(ctx, body, cursor, pageSize)
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing documents: %w", err)
 	}
-	return documents, total, nil
+	return documents, nextCursor, nil
 }
 
 func (d DocumentRepository) Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.Document, int, error) {
-	//TODO implement me
-	panic("implement me")
+	if query == nil {
+		return nil, 0, errors.New("search query cannot be nil")
+	}
+	if err := query.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("invalid search query: %w", err)
+	}
+	body := buildSearchBody(query, d.client.snippetSize)
+	d.useSearchAnalyzer(ctx, query, body["query"])
+	restrictAccess(ctx, body)
+	body["from"] = query.Offset()
+	body["size"] = query.Limit()
+
+	res, err := d.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Index: []string{d.client.IndexNameWithPrefix(DocumentIndex)},
+		Body:  bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("error searching documents: %w", err)
+	}
+	response, err := parseSearchResponse(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return response.documents(), response.Hits.Total.Value, nil
+}
+
+func (d DocumentRepository) SearchAfter(ctx context.Context, query *domain.SearchQuery) ([]*domain.Document, int, string, error) {
+	if query == nil {
+		return nil, 0, "", errors.New("search query cannot be nil")
+	}
+	if err := query.Validate(); err != nil {
+		return nil, 0, "", fmt.Errorf("invalid search query: %w", err)
+	}
+	body := buildSearchBody(query, d.client.snippetSize)
+	d.useSearchAnalyzer(ctx, query, body["query"])
+	restrictAccess(ctx, body)
+	documents, total, nextCursor, err := d.searchAfter(ctx, body, query.Cursor, query.Limit())
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("error searching documents: %w", err)
+	}
+	return documents, total, nextCursor, nil
+}
+
+func (d DocumentRepository) Facets(ctx context.Context, query *domain.SearchQuery) ([]domain.FacetResult, error) {
+	if query == nil {
+		return nil, errors.New("search query cannot be nil")
+	}
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid search query: %w", err)
+	}
+	if len(query.Facets) == 0 {
+		return []domain.FacetResult{}, nil
+	}
+	body := map[string]interface{}{
+		"query": buildBoolQuery(query),
+		"size":  0,
+		"aggs":  buildAggregations(query.Facets),
+	}
+	d.useSearchAnalyzer(ctx, query, body["query"])
+	restrictAccess(ctx, body)
+	res, err := d.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Index: []string{d.client.IndexNameWithPrefix(DocumentIndex)},
+		Body:  bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error computing facets: %w", err)
+	}
+	var response aggregationResponse
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing aggregation response: %w", err)
+	}
+	return response.toFacetResults(query.Facets), nil
+}
+
+// useSearchAnalyzer analyzes the text clauses of a query restricted to one index with the
+// search analyzer of that index, expanding its synonyms and dropping its stopwords
+func (d DocumentRepository) useSearchAnalyzer(ctx context.Context, query *domain.SearchQuery, clause interface{}) {
+	indexID, _ := query.Filters["index_id"].(string)
+	if analyzer := d.client.searchAnalyzer(ctx, indexID); analyzer != "" {
+		applySearchAnalyzer(clause, analyzer)
+	}
+}
+
+// SearchByVector returns the k documents whose embeddings are most similar to the query vector
+// using approximate kNN search. Scores are converted back from the Elasticsearch cosine score to the cosine similarity.
+func (d DocumentRepository) SearchByVector(ctx context.Context, query *domain.SearchQuery, k int) ([]*domain.Document, error) {
+	if query == nil {
+		return nil, errors.New("search query cannot be nil")
+	}
+	if len(query.Vector) == 0 {
+		return nil, errors.New("search query has no vector")
+	}
+	if k <= 0 {
+		return nil, errors.New("k must be positive")
+	}
+
+	filter := buildFilterClauses(query)
+	if clause := accessFilter(domain.AccessScopeFromContext(ctx)); clause != nil {
+		filter = append(filter, clause)
+	}
+	body := map[string]interface{}{
+		"knn": map[string]interface{}{
+			"field":          "embedding",
+			"query_vector":   query.Vector,
+			"k":              k,
+			"num_candidates": max(k*knnCandidateFactor, knnMinCandidates),
+			"filter":         filter,
+		},
+		"size": k,
+	}
+	if source := buildSourceFilter(query); source != nil {
+		body["_source"] = source
+	}
+	res, err := d.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Index: []string{d.client.IndexNameWithPrefix(DocumentIndex)},
+		Body:  bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error searching documents by vector: %w", err)
+	}
+	response, err := parseSearchResponse(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing vector search response: %w", err)
+	}
+
+	documents := response.documents()
+	for _, doc := range documents {
+		doc.Score = 2*doc.Score - 1
+		if query.Explain {
+			doc.Explanation = domain.NewScoreExplanation(doc.Score, "cosine similarity of the query and document embeddings")
+		}
+	}
+	return documents, nil
+}
+
+// searchAfter fetches one page of a point in time backed search.
+// An empty cursor opens a new point in time; the returned cursor is empty once all hits were read.
+func (d DocumentRepository) searchAfter(ctx context.Context, body map[string]interface{}, cursor string, size int) ([]*domain.Document, int, string, error) {
+	var pageCursor *domain.PageCursor
+	if cursor == "" {
+		pitID, err := d.client.OpenPointInTime(ctx, DocumentIndex)
+		if err != nil {
+			return nil, 0, "", fmt.Errorf("error opening point in time: %w", err)
+		}
+		pageCursor = &domain.PageCursor{PointInTime: pitID}
+	} else {
+		decoded, err := domain.DecodePageCursor(cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		if decoded.PointInTime == "" {
+			return nil, 0, "", errors.New("cursor does not reference a point in time")
+		}
+		pageCursor = decoded
+	}
+
+	sortClauses, _ := body["sort"].([]interface{})
+	body["sort"] = append(sortClauses, map[string]interface{}{
+		"_shard_doc": map[string]interface{}{"order": "asc"},
+	})
+	body["size"] = size
+	body["pit"] = map[string]interface{}{
+		"id":         pageCursor.PointInTime,
+		"keep_alive": pointInTimeKeepAlive,
+	}
+	if len(pageCursor.SearchAfter) > 0 {
+		body["search_after"] = pageCursor.SearchAfter
+	}
+
+	// Searches against a point in time must not name an index
+	res, err := d.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Body: bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return nil, 0, "", err
+	}
+	response, err := parseSearchResponse(res.Body)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	hits := response.Hits.Hits
+	if len(hits) < size {
+		pitID := pageCursor.PointInTime
+		if response.PitID != "" {
+			pitID = response.PitID
+		}
+		if err := d.client.ClosePointInTime(ctx, pitID); err != nil {
+			fmt.Printf("Warning: failed to close point in time: %v\n", err)
+		}
+		return response.documents(), response.Hits.Total.Value, "", nil
+	}
+
+	next := &domain.PageCursor{
+		PointInTime: pageCursor.PointInTime,
+		SearchAfter: hits[len(hits)-1].sortValues(),
+	}
+	if response.PitID != "" {
+		next.PointInTime = response.PitID
+	}
+	return response.documents(), response.Hits.Total.Value, next.Encode(), nil
 }
 
 func (d DocumentRepository) CountByIndexID(ctx context.Context, indexID string) (int, error) {
@@ -0,0 +1,124 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// facetFields maps the facet fields to the document fields aggregated in Elasticsearch
+var facetFields = map[string]string{
+	domain.FacetFieldContentType:   "content_type.keyword",
+	domain.FacetFieldLang:          "lang.keyword",
+	domain.FacetFieldCategory:      "parsed_content.category.keyword",
+	domain.FacetFieldKeywords:      "meta_keywords.keyword",
+	domain.FacetFieldDomain:        "domain.keyword",
+	domain.FacetFieldLastCrawled:   "last_crawled",
+	domain.FacetFieldContentLength: "content_length",
+}
+
+// histogramFormats maps the date histogram intervals to the format of their bucket keys
+var histogramFormats = map[string]string{
+	domain.IntervalDay:   "yyyy-MM-dd",
+	domain.IntervalMonth: "yyyy-MM",
+	domain.IntervalYear:  "yyyy",
+}
+
+// aggregationBucket represents a bucket of a terms, date histogram or range aggregation
+type aggregationBucket struct {
+	Key         json.RawMessage `json:"key"`
+	KeyAsString string          `json:"key_as_string"`
+	DocCount    int             `json:"doc_count"`
+	From        *float64        `json:"from"`
+	To          *float64        `json:"to"`
+}
+
+// aggregationResponse represents the aggregations section of a search response
+type aggregationResponse struct {
+	Aggregations map[string]struct {
+		Buckets []aggregationBucket `json:"buckets"`
+	} `json:"aggregations"`
+}
+
+// buildAggregations converts facet requests into Elasticsearch aggregations
+func buildAggregations(facets []domain.FacetRequest) map[string]interface{} {
+	aggregations := make(map[string]interface{}, len(facets))
+	for _, facet := range facets {
+		field := facetFields[facet.Field]
+		switch facet.Type {
+		case domain.TermsFacet:
+			aggregations[facet.Name] = map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": field,
+					"size":  facet.BucketSize(),
+				},
+			}
+		case domain.DateHistogramFacet:
+			aggregations[facet.Name] = map[string]interface{}{
+				"date_histogram": map[string]interface{}{
+					"field":             field,
+					"calendar_interval": facet.Interval,
+					"format":            histogramFormats[facet.Interval],
+					"min_doc_count":     1,
+				},
+			}
+		case domain.RangeFacet:
+			ranges := make([]map[string]interface{}, 0, len(facet.Ranges))
+			for _, r := range facet.Ranges {
+				bucket := map[string]interface{}{}
+				if r.Key != "" {
+					bucket["key"] = r.Key
+				}
+				if r.From != nil {
+					bucket["from"] = *r.From
+				}
+				if r.To != nil {
+					bucket["to"] = *r.To
+				}
+				ranges = append(ranges, bucket)
+			}
+			aggregations[facet.Name] = map[string]interface{}{
+				"range": map[string]interface{}{
+					"field":  field,
+					"ranges": ranges,
+				},
+			}
+		}
+	}
+	return aggregations
+}
+
+// toFacetResults converts the aggregations of a response into facet results, in request order
+func (r *aggregationResponse) toFacetResults(facets []domain.FacetRequest) []domain.FacetResult {
+	results := make([]domain.FacetResult, 0, len(facets))
+	for _, facet := range facets {
+		result := domain.FacetResult{
+			Name:    facet.Name,
+			Field:   facet.Field,
+			Type:    facet.Type,
+			Buckets: make([]domain.FacetBucket, 0),
+		}
+		for _, bucket := range r.Aggregations[facet.Name].Buckets {
+			result.Buckets = append(result.Buckets, domain.FacetBucket{
+				Key:   bucket.keyString(),
+				Count: bucket.DocCount,
+				From:  bucket.From,
+				To:    bucket.To,
+			})
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// keyString returns the display key of a bucket
+func (b aggregationBucket) keyString() string {
+	if b.KeyAsString != "" {
+		return b.KeyAsString
+	}
+	var key string
+	if err := json.Unmarshal(b.Key, &key); err == nil {
+		return key
+	}
+	return string(b.Key)
+}
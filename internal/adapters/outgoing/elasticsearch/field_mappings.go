@@ -0,0 +1,81 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// documentFieldMappings are the fields of the documents index whose type cannot be inferred by dynamic
+// mapping, and the access control lists, which are matched exactly
+var documentFieldMappings = map[string]interface{}{
+	"suggest": map[string]interface{}{
+		"type": "completion",
+	},
+	"embedding": map[string]interface{}{
+		"type":       "dense_vector",
+		"dims":       domain.EmbeddingDimensions,
+		"index":      true,
+		"similarity": "cosine",
+	},
+	"allowed_users":  map[string]interface{}{"type": "keyword"},
+	"allowed_groups": map[string]interface{}{"type": "keyword"},
+	"allowed_roles":  map[string]interface{}{"type": "keyword"},
+}
+
+// mappingGuard applies a set of explicit field mappings at most once per process
+type mappingGuard struct {
+	mu      sync.Mutex
+	applied map[string]bool
+}
+
+// EnsureFieldMappings creates the index with the given field mappings, or adds them to the
+// existing index. Successful calls are remembered so later calls for the same index are free.
+func (c *Client) EnsureFieldMappings(ctx context.Context, indexName string, properties map[string]interface{}) error {
+	c.mappings.mu.Lock()
+	defer c.mappings.mu.Unlock()
+	if c.mappings.applied[indexName] {
+		return nil
+	}
+
+	fullIndexName := c.IndexNameWithPrefix(indexName)
+	existsRes, err := (&esapi.IndicesExistsRequest{Index: []string{fullIndexName}}).Do(ctx, c.es)
+	if err != nil {
+		return fmt.Errorf("error checking index %s: %w", fullIndexName, err)
+	}
+	closeBody(existsRes.Body)
+
+	mappings := map[string]interface{}{
+		"properties": properties,
+	}
+	var req esapi.Request
+	if existsRes.StatusCode == http.StatusNotFound {
+		req = &esapi.IndicesCreateRequest{
+			Index: fullIndexName,
+			Body:  bytes.NewReader(mustMarshalJSON(map[string]interface{}{"mappings": mappings})),
+		}
+	} else {
+		req = &esapi.IndicesPutMappingRequest{
+			Index: []string{fullIndexName},
+			Body:  bytes.NewReader(mustMarshalJSON(mappings)),
+		}
+	}
+
+	res, err := c.PerformRequest(ctx, req)
+	if err != nil {
+		return fmt.Errorf("error applying field mappings to %s: %w", fullIndexName, err)
+	}
+	closeBody(res.Body)
+
+	if c.mappings.applied == nil {
+		c.mappings.applied = make(map[string]bool)
+	}
+	c.mappings.applied[indexName] = true
+	return nil
+}
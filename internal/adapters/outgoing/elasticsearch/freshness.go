@@ -0,0 +1,50 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// applyFreshness wraps a query in a function_score multiplying its relevance by one plus the
+// weighted decays of the document dates, as the SQL backend does
+func applyFreshness(query map[string]interface{}, functions []domain.DecayFunction) map[string]interface{} {
+	if len(functions) == 0 {
+		return query
+	}
+	scoreFunctions := make([]interface{}, 0, len(functions)+1)
+	for _, function := range functions {
+		field := string(function.Field)
+		scoreFunctions = append(scoreFunctions, map[string]interface{}{
+			"filter": map[string]interface{}{
+				"exists": map[string]interface{}{"field": field},
+			},
+			string(function.Function): map[string]interface{}{
+				field: map[string]interface{}{
+					"origin": "now",
+					"scale":  durationParam(function.Scale),
+					"offset": durationParam(function.Offset),
+					"decay":  function.DecayOrDefault(),
+				},
+			},
+			"weight": function.Weight,
+		})
+	}
+	// The constant function keeps the relevance of documents without recent dates
+	scoreFunctions = append(scoreFunctions, map[string]interface{}{"weight": 1})
+
+	return map[string]interface{}{
+		"function_score": map[string]interface{}{
+			"query":      query,
+			"functions":  scoreFunctions,
+			"score_mode": "sum",
+			"boost_mode": "multiply",
+		},
+	}
+}
+
+// durationParam formats a duration as an Elasticsearch time value
+func durationParam(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
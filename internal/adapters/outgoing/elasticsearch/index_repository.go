@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,12 +25,15 @@ type IndexRepository struct {
 	client         *Client
 	refreshTickers map[string]*time.Ticker
 	refreshMutex   sync.RWMutex
+	// lastScheduledRefresh holds the time of the last automatic refresh of each index
+	lastScheduledRefresh map[string]time.Time
+	analyzer             *TextAnalyzer
 }
 
 // Ensure IndexRepository implements the outgoing.IndexRepository interface
 var _ outgoing.IndexRepository = (*IndexRepository)(nil)
 
-func (i IndexRepository) Create(ctx context.Context, index *domain.Index) error {
+func (i *IndexRepository) Create(ctx context.Context, index *domain.Index) error {
 	if index == nil {
 		return errors.New("index cannot be nil")
 	}
@@ -77,7 +82,7 @@ func (i IndexRepository) Create(ctx context.Context, index *domain.Index) error
 	return nil
 }
 
-func (i IndexRepository) GetByID(ctx context.Context, id string) (*domain.Index, error) {
+func (i *IndexRepository) GetByID(ctx context.Context, id string) (*domain.Index, error) {
 	if id == "" {
 		return nil, errors.New("index ID cannot be empty")
 	}
@@ -96,7 +101,7 @@ func (i IndexRepository) GetByID(ctx context.Context, id string) (*domain.Index,
 	return index, nil
 }
 
-func (i IndexRepository) GetByName(ctx context.Context, name string) (*domain.Index, error) {
+func (i *IndexRepository) GetByName(ctx context.Context, name string) (*domain.Index, error) {
 	if name == "" {
 		return nil, fmt.Errorf("index name cannot be empty")
 	}
@@ -132,7 +137,7 @@ func (i IndexRepository) GetByName(ctx context.Context, name string) (*domain.In
 	return i.GetByID(ctx, id)
 }
 
-func (i IndexRepository) List(ctx context.Context) ([]*domain.Index, error) {
+func (i *IndexRepository) List(ctx context.Context) ([]*domain.Index, error) {
 	query := map[string]interface{}{
 		"query": map[string]interface{}{
 			"match_all": map[string]interface{}{},
@@ -182,7 +187,7 @@ func (i IndexRepository) List(ctx context.Context) ([]*domain.Index, error) {
 	return indices, nil
 }
 
-func (i IndexRepository) Delete(ctx context.Context, id string) error {
+func (i *IndexRepository) Delete(ctx context.Context, id string) error {
 	if id == "" {
 		return errors.New("index ID cannot be empty")
 	}
@@ -218,7 +223,7 @@ func (i IndexRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (i IndexRepository) Update(ctx context.Context, index *domain.Index) error {
+func (i *IndexRepository) Update(ctx context.Context, index *domain.Index) error {
 	if index == nil {
 		return errors.New("index cannot be nil")
 	}
@@ -242,14 +247,140 @@ func (i IndexRepository) Update(ctx context.Context, index *domain.Index) error
 	return nil
 }
 
-func (i IndexRepository) UpdateSettings(ctx context.Context, id string, settings domain.IndexSettings) error {
-	//TODO implement me
-	panic("implement me")
+// UpdateSettings replaces the settings of an index. Replicas and the refresh interval are applied
+// live and the automatic refresh is re-armed with the new interval. Documents are searched in the
+// shared documents index, so changed stopwords and synonyms are applied to the search analyzer of
+// the index there. The number of shards, languages and analyzer settings cannot change without a
+// reindex; zero shards keep the current number.
+func (i *IndexRepository) UpdateSettings(ctx context.Context, id string, settings domain.IndexSettings) error {
+	if id == "" {
+		return errors.New("index ID cannot be empty")
+	}
+	index, err := i.getIndexMetadata(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error getting index metadata: %w", err)
+	}
+	if index == nil {
+		return fmt.Errorf("index with ID %s does not exist", id)
+	}
+	if settings.Shards == 0 {
+		settings.Shards = index.Settings.Shards
+	}
+	if settings.Shards != index.Settings.Shards {
+		return fmt.Errorf("number of shards of index %s cannot change without a reindex", id)
+	}
+	if staticAnalysisChanged(index.Settings, settings) {
+		return fmt.Errorf("languages and analyzer settings of index %s cannot change without a reindex", id)
+	}
+	if settings.RefreshInterval != "" && settings.RefreshInterval != "-1" {
+		if _, err := time.ParseDuration(settings.RefreshInterval); err != nil {
+			return fmt.Errorf("invalid refresh interval %q: %w", settings.RefreshInterval, err)
+		}
+	}
+
+	updated := *index
+	updated.Settings = settings
+	if !slices.Equal(settings.Stopwords, index.Settings.Stopwords) || !reflect.DeepEqual(settings.Synonyms, index.Settings.Synonyms) {
+		if err := i.analyzer.ApplyAnalysis(ctx, &updated); err != nil {
+			return fmt.Errorf("error applying analysis of index %s: %w", id, err)
+		}
+	}
+
+	// A null refresh interval restores the default of Elasticsearch
+	var refreshInterval interface{}
+	if settings.RefreshInterval != "" {
+		refreshInterval = settings.RefreshInterval
+	}
+	if err := i.putIndexSettings(ctx, id, map[string]interface{}{
+		"number_of_replicas": settings.Replicas,
+		"refresh_interval":   refreshInterval,
+	}); err != nil {
+		return err
+	}
+
+	updated.LastUpdated = time.Now()
+	if err := i.saveIndexMetadata(ctx, &updated); err != nil {
+		return fmt.Errorf("error saving index metadata: %w", err)
+	}
+	i.StopAutoRefresh(id)
+	if err := i.SetupAutoRefresh(context.Background(), id); err != nil {
+		return fmt.Errorf("error setting up automatic refresh: %w", err)
+	}
+	return nil
+}
+
+// GetStats returns the statistics of an index in the shape of the SQL repository's, with the
+// store size, segment count, indexing, search and refresh totals of its Elasticsearch index.
+// Documents of every index share the documents index, so their count, average size and latest
+// date are aggregated from it. Elasticsearch only reports the number and total time of refreshes,
+// so the last refresh time is that of the last automatic refresh run by this repository.
+func (i *IndexRepository) GetStats(ctx context.Context, id string) (map[string]interface{}, error) {
+	if id == "" {
+		return nil, errors.New("index ID cannot be empty")
+	}
+	index, err := i.getIndexMetadata(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting index metadata: %w", err)
+	}
+	if index == nil {
+		return nil, fmt.Errorf("index with ID %s does not exist", id)
+	}
+
+	documents, err := i.documentStats(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	total, err := i.indexStats(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	i.refreshMutex.RLock()
+	lastRefresh := i.lastScheduledRefresh[id]
+	i.refreshMutex.RUnlock()
+
+	stats := map[string]interface{}{
+		"document_count":     documents.count,
+		"avg_document_size":  documents.avgSize,
+		"created_at":         index.CreatedAt,
+		"updated_at":         index.LastUpdated,
+		"last_document_date": documents.lastDate,
+		"store_size_bytes":   total.Store.SizeInBytes,
+		"segment_count":      total.Segments.Count,
+		"indexing_total":     total.Indexing.IndexTotal,
+		"indexing_time_ms":   total.Indexing.IndexTimeInMillis,
+		"search_total":       total.Search.QueryTotal,
+		"search_time_ms":     total.Search.QueryTimeInMillis,
+		"refresh_total":      total.Refresh.Total,
+		"refresh_time_ms":    total.Refresh.TotalTimeInMillis,
+		"last_refresh_at":    lastRefresh,
+	}
+	return stats, nil
 }
 
-func (i IndexRepository) GetStats(ctx context.Context, id string) (map[string]interface{}, error) {
-	//TODO implement me
-	panic("implement me")
+// staticAnalysisChanged reports whether the languages or analyzer settings differ, which only
+// apply to documents indexed with them
+func staticAnalysisChanged(current, next domain.IndexSettings) bool {
+	if !slices.Equal(current.Languages, next.Languages) {
+		return true
+	}
+	if len(current.AnalyzerSettings) == 0 && len(next.AnalyzerSettings) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(current.AnalyzerSettings, next.AnalyzerSettings)
+}
+
+// putIndexSettings updates dynamic settings of the Elasticsearch index of an index
+func (i *IndexRepository) putIndexSettings(ctx context.Context, id string, settings map[string]interface{}) error {
+	indexName := i.client.IndexNameWithPrefix(id)
+	res, err := i.client.PerformRequest(ctx, &esapi.IndicesPutSettingsRequest{
+		Index: []string{indexName},
+		Body:  bytes.NewReader(mustMarshalJSON(map[string]interface{}{"index": settings})),
+	})
+	if err != nil {
+		return fmt.Errorf("error updating settings of index %s: %w", indexName, err)
+	}
+	closeBody(res.Body)
+	return nil
 }
 
 func (i *IndexRepository) RefreshIndex(ctx context.Context, id string) error {
@@ -282,15 +413,16 @@ func (i *IndexRepository) RefreshIndex(ctx context.Context, id string) error {
 	if res.StatusCode >= 400 {
 		return fmt.Errorf("error refreshing index: unexpected status code %d", res.StatusCode)
 	}
-
 	return nil
 }
 
 // NewIndexRepository creates a new Elasticsearch index repository
 func NewIndexRepository(client *Client) *IndexRepository {
 	return &IndexRepository{
-		client:         client,
-		refreshTickers: make(map[string]*time.Ticker),
+		client:               client,
+		refreshTickers:       make(map[string]*time.Ticker),
+		lastScheduledRefresh: make(map[string]time.Time),
+		analyzer:             NewTextAnalyzer(client),
 	}
 }
 
@@ -354,6 +486,10 @@ func (i *IndexRepository) SetupAutoRefresh(ctx context.Context, id string) error
 				refreshCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 				if err := i.RefreshIndex(refreshCtx, id); err != nil {
 					fmt.Printf("Error auto-refreshing index %s: %v\n", id, err)
+				} else {
+					i.refreshMutex.Lock()
+					i.lastScheduledRefresh[id] = time.Now()
+					i.refreshMutex.Unlock()
 				}
 				cancel()
 			}
@@ -457,6 +593,34 @@ func (i *IndexRepository) mapToIndex(source map[string]interface{}) (*domain.Ind
 			}
 		}
 
+		// Parse synonym rules
+		if synonyms, ok := settingsMap["Synonyms"].([]interface{}); ok {
+			for _, item := range synonyms {
+				if rule, ok := item.(map[string]interface{}); ok {
+					index.Settings.Synonyms = append(index.Settings.Synonyms, domain.SynonymRule{
+						Terms:        getStringSliceFromMap(rule, "Terms"),
+						Replacements: getStringSliceFromMap(rule, "Replacements"),
+					})
+				}
+			}
+		}
+
+		// Parse freshness decay functions
+		if functions, ok := settingsMap["Decay"].([]interface{}); ok {
+			for _, item := range functions {
+				if function, ok := item.(map[string]interface{}); ok {
+					index.Settings.Decay = append(index.Settings.Decay, domain.DecayFunction{
+						Function: domain.DecayFunctionType(getStringFromMap(function, "Function")),
+						Field:    domain.DecayField(getStringFromMap(function, "Field")),
+						Scale:    time.Duration(getFloatFromMap(function, "Scale")),
+						Offset:   time.Duration(getFloatFromMap(function, "Offset")),
+						Decay:    getFloatFromMap(function, "Decay"),
+						Weight:   getFloatFromMap(function, "Weight"),
+					})
+				}
+			}
+		}
+
 		// Parse languages
 		if languages, ok := settingsMap["Languages"].([]interface{}); ok {
 			for _, lang := range languages {
@@ -498,10 +662,18 @@ func (i *IndexRepository) indexToMap(index *domain.Index) (map[string]interface{
 		settings["Stopwords"] = index.Settings.Stopwords
 	}
 
+	if len(index.Settings.Synonyms) > 0 {
+		settings["Synonyms"] = index.Settings.Synonyms
+	}
+
 	if len(index.Settings.Languages) > 0 {
 		settings["Languages"] = index.Settings.Languages
 	}
 
+	if len(index.Settings.Decay) > 0 {
+		settings["Decay"] = index.Settings.Decay
+	}
+
 	indexMap["Settings"] = settings
 
 	if len(index.DocumentMapping) > 0 {
@@ -739,3 +911,31 @@ func getStringFromMap(m map[string]interface{}, key string) string {
 	}
 	return ""
 }
+
+// getFloatFromMap safely extracts a float64 value from a map
+func getFloatFromMap(m map[string]interface{}, key string) float64 {
+	switch v := m[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+// getStringSliceFromMap safely extracts a string slice from a map
+func getStringSliceFromMap(m map[string]interface{}, key string) []string {
+	items, ok := m[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,113 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// indexStatsMetrics are the statistics read from the _stats API
+var indexStatsMetrics = []string{"store", "segments", "indexing", "search", "refresh"}
+
+// indexStatsTotal is the part of a _stats response totalling the primary and replica shards
+type indexStatsTotal struct {
+	Store struct {
+		SizeInBytes int64 `json:"size_in_bytes"`
+	} `json:"store"`
+	Segments struct {
+		Count int `json:"count"`
+	} `json:"segments"`
+	Indexing struct {
+		IndexTotal        int64 `json:"index_total"`
+		IndexTimeInMillis int64 `json:"index_time_in_millis"`
+	} `json:"indexing"`
+	Search struct {
+		QueryTotal        int64 `json:"query_total"`
+		QueryTimeInMillis int64 `json:"query_time_in_millis"`
+	} `json:"search"`
+	Refresh struct {
+		Total             int64 `json:"total"`
+		TotalTimeInMillis int64 `json:"total_time_in_millis"`
+	} `json:"refresh"`
+}
+
+// indexStats returns the statistics of the Elasticsearch index of an index, over all its shards
+func (i *IndexRepository) indexStats(ctx context.Context, id string) (*indexStatsTotal, error) {
+	indexName := i.client.IndexNameWithPrefix(id)
+	res, err := i.client.PerformRequest(ctx, &esapi.IndicesStatsRequest{
+		Index:  []string{indexName},
+		Metric: indexStatsMetrics,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting stats of index %s: %w", indexName, err)
+	}
+	var response struct {
+		All struct {
+			Total indexStatsTotal `json:"total"`
+		} `json:"_all"`
+	}
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing index stats response: %w", err)
+	}
+	return &response.All.Total, nil
+}
+
+// documentStats are the count, average content length and latest crawl date of the documents of an index
+type documentStats struct {
+	count    int64
+	avgSize  float64
+	lastDate time.Time
+}
+
+// documentStats aggregates the documents of an index in the documents index
+func (i *IndexRepository) documentStats(ctx context.Context, id string) (*documentStats, error) {
+	body := map[string]interface{}{
+		"size":             0,
+		"track_total_hits": true,
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{"index_id.keyword": id},
+		},
+		"aggs": map[string]interface{}{
+			"avg_size":  map[string]interface{}{"avg": map[string]interface{}{"field": "content_length"}},
+			"last_date": map[string]interface{}{"max": map[string]interface{}{"field": "last_crawled"}},
+		},
+	}
+	res, err := i.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Index:             []string{i.client.IndexNameWithPrefix(DocumentIndex)},
+		Body:              bytes.NewReader(mustMarshalJSON(body)),
+		IgnoreUnavailable: esapi.BoolPtr(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating documents of index %s: %w", id, err)
+	}
+	var response struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+		} `json:"hits"`
+		Aggregations struct {
+			AvgSize struct {
+				Value *float64 `json:"value"`
+			} `json:"avg_size"`
+			LastDate struct {
+				Value *float64 `json:"value"`
+			} `json:"last_date"`
+		} `json:"aggregations"`
+	}
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing document stats response: %w", err)
+	}
+
+	stats := &documentStats{count: response.Hits.Total.Value}
+	if response.Aggregations.AvgSize.Value != nil {
+		stats.avgSize = *response.Aggregations.AvgSize.Value
+	}
+	if response.Aggregations.LastDate.Value != nil {
+		stats.lastDate = time.UnixMilli(int64(*response.Aggregations.LastDate.Value))
+	}
+	return stats, nil
+}
@@ -0,0 +1,164 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	// linkPageSize is the number of documents read per page when listing the links of an index
+	linkPageSize = 1000
+	// linkScoreBatchSize is the number of documents updated per bulk request
+	linkScoreBatchSize = 500
+)
+
+// LinkGraphRepository implements the outgoing.LinkGraphRepository interface using Elasticsearch
+type LinkGraphRepository struct {
+	client *Client
+}
+
+var _ outgoing.LinkGraphRepository = (*LinkGraphRepository)(nil)
+
+// NewLinkGraphRepository creates a new link graph repository
+func NewLinkGraphRepository(client *Client) *LinkGraphRepository {
+	return &LinkGraphRepository{
+		client: client,
+	}
+}
+
+// ListDocumentLinks returns the documents of the index with the URLs they link to, reading them
+// page by page from a point in time
+func (l *LinkGraphRepository) ListDocumentLinks(ctx context.Context, indexID string) ([]domain.DocumentLinks, error) {
+	if indexID == "" {
+		return nil, errors.New("index ID cannot be empty")
+	}
+	pitID, err := l.client.OpenPointInTime(ctx, DocumentIndex)
+	if err != nil {
+		return nil, fmt.Errorf("error opening point in time: %w", err)
+	}
+	defer func() {
+		if err := l.client.ClosePointInTime(ctx, pitID); err != nil {
+			fmt.Printf("Warning: failed to close point in time: %v\n", err)
+		}
+	}()
+
+	documents := make([]domain.DocumentLinks, 0)
+	var searchAfter []interface{}
+	for {
+		body := map[string]interface{}{
+			"size":    linkPageSize,
+			"_source": []string{"id", "url", "links"},
+			"query": map[string]interface{}{
+				"term": map[string]interface{}{"index_id.keyword": indexID},
+			},
+			"pit": map[string]interface{}{
+				"id":         pitID,
+				"keep_alive": pointInTimeKeepAlive,
+			},
+			"sort": []interface{}{
+				map[string]interface{}{"_shard_doc": map[string]interface{}{"order": "asc"}},
+			},
+		}
+		if searchAfter != nil {
+			body["search_after"] = searchAfter
+		}
+
+		// Searches against a point in time must not name an index
+		res, err := l.client.PerformRequest(ctx, &esapi.SearchRequest{
+			Body: bytes.NewReader(mustMarshalJSON(body)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing document links: %w", err)
+		}
+		var response struct {
+			PitID string `json:"pit_id"`
+			Hits  struct {
+				Hits []struct {
+					Source struct {
+						ID    string   `json:"id"`
+						URL   string   `json:"url"`
+						Links []string `json:"links"`
+					} `json:"_source"`
+					Sort []interface{} `json:"sort"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		if err := parseResponse(res.Body, &response); err != nil {
+			return nil, fmt.Errorf("error parsing document links response: %w", err)
+		}
+		if response.PitID != "" {
+			pitID = response.PitID
+		}
+
+		for _, hit := range response.Hits.Hits {
+			documents = append(documents, domain.DocumentLinks{
+				DocumentID: hit.Source.ID,
+				URL:        hit.Source.URL,
+				Links:      hit.Source.Links,
+			})
+		}
+		if len(response.Hits.Hits) < linkPageSize {
+			return documents, nil
+		}
+		searchAfter = response.Hits.Hits[len(response.Hits.Hits)-1].Sort
+	}
+}
+
+// UpdateLinkScores stores the importance rank and link counts of the documents with bulk
+// partial updates
+func (l *LinkGraphRepository) UpdateLinkScores(ctx context.Context, scores []domain.LinkScore) error {
+	index := l.client.IndexNameWithPrefix(DocumentIndex)
+	for start := 0; start < len(scores); start += linkScoreBatchSize {
+		var body bytes.Buffer
+		for _, score := range scores[start:min(start+linkScoreBatchSize, len(scores))] {
+			body.Write(mustMarshalJSON(map[string]interface{}{
+				"update": map[string]interface{}{"_index": index, "_id": score.DocumentID},
+			}))
+			body.WriteByte('\n')
+			body.Write(mustMarshalJSON(map[string]interface{}{
+				"doc": map[string]interface{}{
+					"importance_rank": score.ImportanceRank,
+					"incoming_links":  score.IncomingLinks,
+					"outgoing_links":  score.OutgoingLinks,
+				},
+			}))
+			body.WriteByte('\n')
+		}
+
+		res, err := l.client.PerformRequest(ctx, &esapi.BulkRequest{
+			Body: bytes.NewReader(body.Bytes()),
+		})
+		if err != nil {
+			return fmt.Errorf("error updating link scores: %w", err)
+		}
+		var response struct {
+			Errors bool `json:"errors"`
+			Items  []struct {
+				Update struct {
+					ID    string `json:"_id"`
+					Error *struct {
+						Reason string `json:"reason"`
+					} `json:"error"`
+				} `json:"update"`
+			} `json:"items"`
+		}
+		if err := parseResponse(res.Body, &response); err != nil {
+			return fmt.Errorf("error parsing bulk response: %w", err)
+		}
+		if response.Errors {
+			for _, item := range response.Items {
+				if item.Update.Error != nil {
+					return fmt.Errorf("error updating link scores of document %s: %s", item.Update.ID, item.Update.Error.Reason)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -1,19 +1,23 @@
 package models
 
 import (
-	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"math"
 	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
 )
 
 type Document struct {
 	ID                 string                 `json:"id"`
 	URL                string                 `json:"url"`
+	Domain             string                 `json:"domain"`
 	Title              string                 `json:"title"`
 	Content            string                 `json:"content"`
 	ContentType        domain.ContentType     `json:"content_type"`
 	ContentFingerprint string                 `json:"content_fingerprint"`
 	LastCrawled        time.Time              `json:"last_crawled"`
 	LastModified       time.Time              `json:"last_modified"`
+	PublishedDate      time.Time              `json:"published_date"`
 	Lang               string                 `json:"lang"`
 	MetaDesc           string                 `json:"meta_desc"`
 	MetaKeywords       []string               `json:"meta_keywords"`
@@ -22,6 +26,8 @@ type Document struct {
 	StatusCode         int                    `json:"status_code"`
 	ContentLength      int                    `json:"content_length"`
 	ImportanceRank     float64                `json:"importance_rank"`
+	IncomingLinks      int                    `json:"incoming_links"`
+	OutgoingLinks      int                    `json:"outgoing_links"`
 	IndexID            string                 `json:"index_id"`
 	IsDuplicate        bool                   `json:"is_duplicate"`
 	OriginalDocID      string                 `json:"original_doc_id"`
@@ -29,6 +35,17 @@ type Document struct {
 	CurrentVersion     int                    `json:"current_version"`
 	ParsedContent      map[string]interface{} `json:"parsed_content"`
 	Score              float64                `json:"score"`
+	Embedding          []float32              `json:"embedding,omitempty"`
+	Suggest            *Completion            `json:"suggest,omitempty"`
+	AllowedUsers       []string               `json:"allowed_users"`
+	AllowedGroups      []string               `json:"allowed_groups"`
+	AllowedRoles       []string               `json:"allowed_roles"`
+}
+
+// Completion is the input of the completion suggester for a document
+type Completion struct {
+	Input  []string `json:"input"`
+	Weight int      `json:"weight"`
 }
 
 type Keyword struct {
@@ -48,12 +65,14 @@ func FromDomain(d *domain.Document) *Document {
 	esDoc := &Document{
 		ID:                 d.ID,
 		URL:                d.URL,
+		Domain:             d.Host(),
 		Title:              d.Title,
 		Content:            d.Content,
 		ContentType:        d.ContentType,
 		ContentFingerprint: d.ContentFingerprint,
 		LastCrawled:        d.LastCrawled,
 		LastModified:       d.LastModified,
+		PublishedDate:      d.PublishedDate,
 		Lang:               d.Lang,
 		MetaDesc:           d.MetaDesc,
 		MetaKeywords:       d.MetaKeywords,
@@ -61,6 +80,8 @@ func FromDomain(d *domain.Document) *Document {
 		StatusCode:         d.StatusCode,
 		ContentLength:      d.ContentLength,
 		ImportanceRank:     d.ImportanceRank,
+		IncomingLinks:      d.IncomingLinks,
+		OutgoingLinks:      d.OutgoingLinks,
 		IndexID:            d.IndexID,
 		IsDuplicate:        d.IsDuplicate,
 		OriginalDocID:      d.OriginalDocID,
@@ -68,6 +89,11 @@ func FromDomain(d *domain.Document) *Document {
 		CurrentVersion:     d.CurrentVersion,
 		ParsedContent:      d.ParsedContent,
 		Score:              d.Score,
+		Embedding:          d.Embedding,
+		Suggest:            completionFromDomain(d),
+		AllowedUsers:       d.AllowedUsers,
+		AllowedGroups:      d.AllowedGroups,
+		AllowedRoles:       d.AllowedRoles,
 	}
 
 	// Convert enhanced keywords
@@ -102,6 +128,7 @@ func (d *Document) ToDomain() *domain.Document {
 		ContentFingerprint: d.ContentFingerprint,
 		LastCrawled:        d.LastCrawled,
 		LastModified:       d.LastModified,
+		PublishedDate:      d.PublishedDate,
 		Lang:               d.Lang,
 		MetaDesc:           d.MetaDesc,
 		MetaKeywords:       d.MetaKeywords,
@@ -109,13 +136,19 @@ func (d *Document) ToDomain() *domain.Document {
 		StatusCode:         d.StatusCode,
 		ContentLength:      d.ContentLength,
 		ImportanceRank:     d.ImportanceRank,
+		IncomingLinks:      d.IncomingLinks,
+		OutgoingLinks:      d.OutgoingLinks,
 		IndexID:            d.IndexID,
 		IsDuplicate:        d.IsDuplicate,
 		OriginalDocID:      d.OriginalDocID,
 		VersionCount:       d.VersionCount,
 		CurrentVersion:     d.CurrentVersion,
 		ParsedContent:      d.ParsedContent,
+		Embedding:          d.Embedding,
 		Score:              d.Score,
+		AllowedUsers:       d.AllowedUsers,
+		AllowedGroups:      d.AllowedGroups,
+		AllowedRoles:       d.AllowedRoles,
 	}
 
 	// Convert enhanced keywords
@@ -134,3 +167,25 @@ func (d *Document) ToDomain() *domain.Document {
 
 	return domainDoc
 }
+
+// completionFromDomain builds the completion input from the title and keywords of a document,
+// weighted by its importance
+func completionFromDomain(d *domain.Document) *Completion {
+	// Completions are suggested to every caller, so restricted documents provide none
+	if !d.IsPublic() {
+		return nil
+	}
+	inputs := make([]string, 0, len(d.MetaKeywords)+1)
+	for _, input := range append([]string{d.Title}, d.MetaKeywords...) {
+		if input = domain.NormalizeQuery(input); input != "" {
+			inputs = append(inputs, input)
+		}
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+	return &Completion{
+		Input:  inputs,
+		Weight: 1 + int(math.Round(math.Max(d.ImportanceRank, 0)*10)),
+	}
+}
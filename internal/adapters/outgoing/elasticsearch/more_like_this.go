@@ -0,0 +1,79 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// moreLikeThisFields are the document fields whose terms select related documents
+var moreLikeThisFields = []string{"title", "content", "meta_keywords"}
+
+// MoreLikeThis returns the documents of the same index sharing the most distinctive terms with
+// the document, using a more_like_this query. Duplicates and documents at the same URL are excluded.
+func (d DocumentRepository) MoreLikeThis(ctx context.Context, document *domain.Document, limit int) ([]*domain.Document, error) {
+	if document == nil {
+		return nil, errors.New("document cannot be nil")
+	}
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+
+	mustNot := []interface{}{
+		map[string]interface{}{"term": map[string]interface{}{"is_duplicate": true}},
+		map[string]interface{}{"term": map[string]interface{}{"url.keyword": document.URL}},
+	}
+	if document.OriginalDocID != "" {
+		mustNot = append(mustNot, map[string]interface{}{
+			"ids": map[string]interface{}{"values": []string{document.OriginalDocID}},
+		})
+	}
+	filter := make([]interface{}, 0, 2)
+	if clause := termFilter("index_id.keyword", document.IndexID); clause != nil {
+		filter = append(filter, clause)
+	}
+	if clause := accessFilter(domain.AccessScopeFromContext(ctx)); clause != nil {
+		filter = append(filter, clause)
+	}
+
+	body := map[string]interface{}{
+		"size": limit,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"more_like_this": map[string]interface{}{
+						"fields": moreLikeThisFields,
+						"like": []interface{}{
+							map[string]interface{}{
+								"_index": d.client.IndexNameWithPrefix(DocumentIndex),
+								"_id":    document.ID,
+							},
+						},
+						"min_term_freq":   1,
+						"min_doc_freq":    1,
+						"max_query_terms": 25,
+					},
+				},
+				"filter":   filter,
+				"must_not": mustNot,
+			},
+		},
+	}
+	res, err := d.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Index: []string{d.client.IndexNameWithPrefix(DocumentIndex)},
+		Body:  bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error searching related documents: %w", err)
+	}
+	response, err := parseSearchResponse(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return response.documents(), nil
+}
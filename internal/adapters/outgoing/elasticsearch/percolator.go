@@ -0,0 +1,242 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	StandingQueriesIndex = "standing_queries"
+	// standingQueryPageSize is the number of standing queries read per page when listing or percolating
+	standingQueryPageSize = 500
+)
+
+// textWithKeyword is the mapping dynamic mapping gives to the text fields of documents
+var textWithKeyword = map[string]interface{}{
+	"type": "text",
+	"fields": map[string]interface{}{
+		"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+	},
+}
+
+// standingQueryFieldMappings are the fields of the standing queries index: the document fields
+// queries can refer to, mapped as in the documents index, the percolator field holding the
+// queries and the stored standing queries, which are not indexed
+var standingQueryFieldMappings = map[string]interface{}{
+	"id":                map[string]interface{}{"type": "keyword"},
+	"url":               textWithKeyword,
+	"domain":            textWithKeyword,
+	"title":             textWithKeyword,
+	"content":           textWithKeyword,
+	"content_type":      textWithKeyword,
+	"lang":              textWithKeyword,
+	"meta_desc":         textWithKeyword,
+	"meta_keywords":     textWithKeyword,
+	"index_id":          textWithKeyword,
+	"original_doc_id":   textWithKeyword,
+	"is_duplicate":      map[string]interface{}{"type": "boolean"},
+	"last_crawled":      map[string]interface{}{"type": "date"},
+	"last_modified":     map[string]interface{}{"type": "date"},
+	"published_date":    map[string]interface{}{"type": "date"},
+	"query":             map[string]interface{}{"type": "percolator"},
+	"standing_query_id": map[string]interface{}{"type": "keyword"},
+	"created_at":        map[string]interface{}{"type": "date"},
+	"standing_query":    map[string]interface{}{"type": "object", "enabled": false},
+}
+
+// Percolator implements the outgoing.Percolator interface with the Elasticsearch percolator.
+// Standing queries are stored as the queries a search would run, so documents match them as
+// they would match the search, with the default analysis of their fields.
+type Percolator struct {
+	client *Client
+}
+
+var _ outgoing.Percolator = (*Percolator)(nil)
+
+// NewPercolator creates a new percolator
+func NewPercolator(client *Client) *Percolator {
+	return &Percolator{
+		client: client,
+	}
+}
+
+// standingQuery is the stored form of a standing query
+type standingQuery struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Query     domain.SearchQuery `json:"query"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// toDomain converts the stored standing query to its domain form
+func (q standingQuery) toDomain() *domain.StandingQuery {
+	return &domain.StandingQuery{
+		ID:        q.ID,
+		Name:      q.Name,
+		Query:     q.Query,
+		CreatedAt: q.CreatedAt,
+	}
+}
+
+// Register compiles a standing query into the query a search would run and stores it. The
+// index is refreshed before returning so that the next document saved is matched against it.
+func (p *Percolator) Register(ctx context.Context, query *domain.StandingQuery) error {
+	if query == nil {
+		return errors.New("standing query cannot be nil")
+	}
+	if query.ID == "" {
+		return errors.New("standing query ID cannot be empty")
+	}
+	compiled, err := query.Compiled()
+	if err != nil {
+		return fmt.Errorf("error compiling standing query: %w", err)
+	}
+	if err := p.client.EnsureFieldMappings(ctx, StandingQueriesIndex, standingQueryFieldMappings); err != nil {
+		return fmt.Errorf("error preparing standing queries index: %w", err)
+	}
+
+	stored := map[string]interface{}{
+		"query":             buildBoolQuery(compiled),
+		"standing_query_id": query.ID,
+		"created_at":        query.CreatedAt,
+		"standing_query": standingQuery{
+			ID:        query.ID,
+			Name:      query.Name,
+			Query:     query.Query,
+			CreatedAt: query.CreatedAt,
+		},
+	}
+	res, err := p.client.PerformRequest(ctx, &esapi.IndexRequest{
+		Index:      p.client.IndexNameWithPrefix(StandingQueriesIndex),
+		DocumentID: query.ID,
+		Body:       bytes.NewReader(mustMarshalJSON(stored)),
+		Refresh:    "wait_for",
+	})
+	if err != nil {
+		return fmt.Errorf("error registering standing query: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
+
+// Unregister deletes a standing query by ID
+func (p *Percolator) Unregister(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("standing query ID cannot be empty")
+	}
+	res, err := p.client.PerformRequest(ctx, &esapi.DeleteRequest{
+		Index:      p.client.IndexNameWithPrefix(StandingQueriesIndex),
+		DocumentID: id,
+		Refresh:    "wait_for",
+	})
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		closeBody(res.Body)
+		return fmt.Errorf("standing query %s not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("error unregistering standing query: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
+
+// List returns every standing query, oldest first
+func (p *Percolator) List(ctx context.Context) ([]*domain.StandingQuery, error) {
+	return p.search(ctx, map[string]interface{}{"match_all": map[string]interface{}{}})
+}
+
+// Percolate returns the standing queries matching the document
+func (p *Percolator) Percolate(ctx context.Context, document *domain.Document) ([]*domain.StandingQuery, error) {
+	if document == nil {
+		return nil, errors.New("document cannot be nil")
+	}
+	return p.search(ctx, map[string]interface{}{
+		"percolate": map[string]interface{}{
+			"field":    "query",
+			"document": percolatedDocument(document),
+		},
+	})
+}
+
+// search returns the standing queries selected by the query, oldest first, reading them page by page
+func (p *Percolator) search(ctx context.Context, query map[string]interface{}) ([]*domain.StandingQuery, error) {
+	if err := p.client.EnsureFieldMappings(ctx, StandingQueriesIndex, standingQueryFieldMappings); err != nil {
+		return nil, fmt.Errorf("error preparing standing queries index: %w", err)
+	}
+
+	queries := make([]*domain.StandingQuery, 0)
+	var searchAfter []interface{}
+	for {
+		body := map[string]interface{}{
+			"size":    standingQueryPageSize,
+			"query":   query,
+			"_source": []string{"standing_query"},
+			"sort": []interface{}{
+				map[string]interface{}{"created_at": map[string]interface{}{"order": "asc"}},
+				map[string]interface{}{"standing_query_id": map[string]interface{}{"order": "asc"}},
+			},
+		}
+		if searchAfter != nil {
+			body["search_after"] = searchAfter
+		}
+		res, err := p.client.PerformRequest(ctx, &esapi.SearchRequest{
+			Index: []string{p.client.IndexNameWithPrefix(StandingQueriesIndex)},
+			Body:  bytes.NewReader(mustMarshalJSON(body)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error searching standing queries: %w", err)
+		}
+		var response struct {
+			Hits struct {
+				Hits []struct {
+					Source struct {
+						StandingQuery standingQuery `json:"standing_query"`
+					} `json:"_source"`
+					Sort []interface{} `json:"sort"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		if err := parseResponse(res.Body, &response); err != nil {
+			return nil, fmt.Errorf("error parsing standing queries response: %w", err)
+		}
+
+		for _, hit := range response.Hits.Hits {
+			queries = append(queries, hit.Source.StandingQuery.toDomain())
+		}
+		if len(response.Hits.Hits) < standingQueryPageSize {
+			return queries, nil
+		}
+		searchAfter = response.Hits.Hits[len(response.Hits.Hits)-1].Sort
+	}
+}
+
+// percolatedDocument returns the fields of a document standing queries can match, named as in
+// the documents index
+func percolatedDocument(document *domain.Document) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              document.ID,
+		"url":             document.URL,
+		"domain":          document.Host(),
+		"title":           document.Title,
+		"content":         document.Content,
+		"content_type":    document.ContentType,
+		"lang":            document.Lang,
+		"meta_desc":       document.MetaDesc,
+		"meta_keywords":   document.MetaKeywords,
+		"index_id":        document.IndexID,
+		"original_doc_id": document.OriginalDocID,
+		"is_duplicate":    document.IsDuplicate,
+		"last_crawled":    document.LastCrawled,
+		"last_modified":   document.LastModified,
+		"published_date":  document.PublishedDate,
+	}
+}
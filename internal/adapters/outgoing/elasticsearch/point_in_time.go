@@ -0,0 +1,51 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+const (
+	// pointInTimeKeepAlive is how long a point in time stays open between two page requests
+	pointInTimeKeepAlive = "2m"
+)
+
+// OpenPointInTime opens a point in time on the given index and returns its ID
+func (c *Client) OpenPointInTime(ctx context.Context, indexName string) (string, error) {
+	fullIndexName := c.IndexNameWithPrefix(indexName)
+	res, err := c.PerformRequest(ctx, &esapi.OpenPointInTimeRequest{
+		Index:     []string{fullIndexName},
+		KeepAlive: pointInTimeKeepAlive,
+	})
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := parseResponse(res.Body, &response); err != nil {
+		return "", fmt.Errorf("error parsing point in time response: %w", err)
+	}
+	if response.ID == "" {
+		return "", fmt.Errorf("elasticsearch returned an empty point in time ID")
+	}
+	return response.ID, nil
+}
+
+// ClosePointInTime releases the resources held by a point in time
+func (c *Client) ClosePointInTime(ctx context.Context, pitID string) error {
+	body := map[string]interface{}{
+		"id": pitID,
+	}
+	res, err := c.PerformRequest(ctx, &esapi.ClosePointInTimeRequest{
+		Body: bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return err
+	}
+	defer closeBody(res.Body)
+	return nil
+}
@@ -0,0 +1,145 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	QueryHistoryIndex = "query_history"
+)
+
+// queryHistoryFieldMappings are the fields of the query history index
+var queryHistoryFieldMappings = map[string]interface{}{
+	"query":          map[string]interface{}{"type": "keyword"},
+	"use_count":      map[string]interface{}{"type": "integer"},
+	"last_used_time": map[string]interface{}{"type": "date"},
+}
+
+// QueryHistoryRepository implements the outgoing.QueryHistoryRepository interface using Elasticsearch
+type QueryHistoryRepository struct {
+	client *Client
+}
+
+var _ outgoing.QueryHistoryRepository = (*QueryHistoryRepository)(nil)
+
+// NewQueryHistoryRepository creates a new query history repository
+func NewQueryHistoryRepository(client *Client) *QueryHistoryRepository {
+	return &QueryHistoryRepository{
+		client: client,
+	}
+}
+
+// queryStat is the stored usage statistics of a normalized query
+type queryStat struct {
+	Query        string    `json:"query"`
+	UseCount     int       `json:"use_count"`
+	LastUsedTime time.Time `json:"last_used_time"`
+}
+
+// RecordQuery increments the use count of a query and updates its last use time
+func (q *QueryHistoryRepository) RecordQuery(ctx context.Context, query string, usedAt time.Time) error {
+	normalized := domain.NormalizeQuery(query)
+	if normalized == "" {
+		return errors.New("query cannot be empty")
+	}
+	if err := q.client.EnsureFieldMappings(ctx, QueryHistoryIndex, queryHistoryFieldMappings); err != nil {
+		return fmt.Errorf("error preparing query history index: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"script": map[string]interface{}{
+			"source": "ctx._source.use_count += 1; ctx._source.last_used_time = params.used_at",
+			"params": map[string]interface{}{"used_at": usedAt},
+		},
+		"upsert": queryStat{
+			Query:        normalized,
+			UseCount:     1,
+			LastUsedTime: usedAt,
+		},
+	}
+	res, err := q.client.PerformRequest(ctx, &esapi.UpdateRequest{
+		Index:           q.client.IndexNameWithPrefix(QueryHistoryIndex),
+		DocumentID:      queryDocumentID(normalized),
+		Body:            bytes.NewReader(mustMarshalJSON(body)),
+		RetryOnConflict: intPtr(3),
+	})
+	if err != nil {
+		return fmt.Errorf("error recording query: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
+
+// PopularQueries returns the most used queries starting with the prefix
+func (q *QueryHistoryRepository) PopularQueries(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	if err := q.client.EnsureFieldMappings(ctx, QueryHistoryIndex, queryHistoryFieldMappings); err != nil {
+		return nil, fmt.Errorf("error preparing query history index: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"size": limit,
+		"query": map[string]interface{}{
+			"prefix": map[string]interface{}{
+				"query": domain.NormalizeQuery(prefix),
+			},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{"use_count": map[string]interface{}{"order": "desc"}},
+			map[string]interface{}{"last_used_time": map[string]interface{}{"order": "desc"}},
+		},
+	}
+	res, err := q.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Index: []string{q.client.IndexNameWithPrefix(QueryHistoryIndex)},
+		Body:  bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting popular queries: %w", err)
+	}
+
+	var response struct {
+		Hits struct {
+			Hits []struct {
+				Source queryStat `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing popular queries response: %w", err)
+	}
+
+	suggestions := make([]domain.SearchSuggestion, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		suggestions = append(suggestions, domain.SearchSuggestion{
+			Text:         hit.Source.Query,
+			Source:       domain.SuggestionSourcePopular,
+			LastUsedTime: hit.Source.LastUsedTime,
+			UseCount:     hit.Source.UseCount,
+		})
+	}
+	return suggestions, nil
+}
+
+// queryDocumentID derives a stable document ID from a normalized query
+func queryDocumentID(normalized string) string {
+	sum := sha1.Sum([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// intPtr returns a pointer to an int value
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,479 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	QueryLogIndex     = "query_log"
+	ResultClicksIndex = "result_clicks"
+)
+
+// queryLogFieldMappings are the fields of the query log index
+var queryLogFieldMappings = map[string]interface{}{
+	"query_id":     map[string]interface{}{"type": "keyword"},
+	"query":        map[string]interface{}{"type": "keyword"},
+	"filters":      map[string]interface{}{"type": "object", "enabled": false},
+	"total_hits":   map[string]interface{}{"type": "integer"},
+	"latency_ms":   map[string]interface{}{"type": "long"},
+	"offset":       map[string]interface{}{"type": "integer"},
+	"document_ids": map[string]interface{}{"type": "keyword"},
+	"logged_at":    map[string]interface{}{"type": "date"},
+}
+
+// resultClickFieldMappings are the fields of the result clicks index
+var resultClickFieldMappings = map[string]interface{}{
+	"query_id":    map[string]interface{}{"type": "keyword"},
+	"document_id": map[string]interface{}{"type": "keyword"},
+	"position":    map[string]interface{}{"type": "integer"},
+	"clicked_at":  map[string]interface{}{"type": "date"},
+}
+
+// QueryLogRepository implements the outgoing.QueryLogRepository interface using Elasticsearch
+type QueryLogRepository struct {
+	client *Client
+}
+
+var _ outgoing.QueryLogRepository = (*QueryLogRepository)(nil)
+
+// NewQueryLogRepository creates a new query log repository
+func NewQueryLogRepository(client *Client) *QueryLogRepository {
+	return &QueryLogRepository{
+		client: client,
+	}
+}
+
+// LogQuery stores an executed search under its query ID
+func (q *QueryLogRepository) LogQuery(ctx context.Context, entry *domain.QueryLogEntry) error {
+	if entry == nil {
+		return errors.New("query log entry cannot be nil")
+	}
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("invalid query log entry: %w", err)
+	}
+	if err := q.client.EnsureFieldMappings(ctx, QueryLogIndex, queryLogFieldMappings); err != nil {
+		return fmt.Errorf("error preparing query log index: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"query_id":     entry.QueryID,
+		"query":        domain.NormalizeQuery(entry.Query),
+		"filters":      entry.Filters,
+		"total_hits":   entry.TotalHits,
+		"latency_ms":   entry.LatencyMs,
+		"offset":       entry.Offset,
+		"document_ids": entry.DocumentIDs,
+		"logged_at":    entry.LoggedAt,
+	}
+	res, err := q.client.PerformRequest(ctx, &esapi.IndexRequest{
+		Index:      q.client.IndexNameWithPrefix(QueryLogIndex),
+		DocumentID: entry.QueryID,
+		Body:       bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return fmt.Errorf("error logging query: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
+
+// LogClick stores a click on a search result
+func (q *QueryLogRepository) LogClick(ctx context.Context, click *domain.ResultClick) error {
+	if click == nil {
+		return errors.New("result click cannot be nil")
+	}
+	if err := q.client.EnsureFieldMappings(ctx, ResultClicksIndex, resultClickFieldMappings); err != nil {
+		return fmt.Errorf("error preparing result clicks index: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"query_id":    click.QueryID,
+		"document_id": click.DocumentID,
+		"position":    click.Position,
+		"clicked_at":  click.ClickedAt,
+	}
+	res, err := q.client.PerformRequest(ctx, &esapi.IndexRequest{
+		Index: q.client.IndexNameWithPrefix(ResultClicksIndex),
+		Body:  bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return fmt.Errorf("error logging result click: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
+
+// CountQueries returns the number of searches logged within the window
+func (q *QueryLogRepository) CountQueries(ctx context.Context, window domain.AnalyticsWindow) (int, error) {
+	if err := q.client.EnsureFieldMappings(ctx, QueryLogIndex, queryLogFieldMappings); err != nil {
+		return 0, fmt.Errorf("error preparing query log index: %w", err)
+	}
+	count, err := q.client.CountDocument(ctx, QueryLogIndex, map[string]interface{}{
+		"query": windowQuery("logged_at", window),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error counting queries: %w", err)
+	}
+	return int(count), nil
+}
+
+// TopQueries returns the queries searched most often within the window, optionally only
+// among the searches without results
+func (q *QueryLogRepository) TopQueries(ctx context.Context, window domain.AnalyticsWindow, zeroResultsOnly bool, limit int) ([]domain.QueryCount, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	filters := []interface{}{windowQuery("logged_at", window)}
+	if zeroResultsOnly {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{"total_hits": 0},
+		})
+	}
+	body := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter":   filters,
+				"must_not": []interface{}{map[string]interface{}{"term": map[string]interface{}{"query": ""}}},
+			},
+		},
+		"aggs": map[string]interface{}{
+			"queries": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": "query",
+					"size":  limit,
+					"order": []interface{}{
+						map[string]interface{}{"_count": "desc"},
+						map[string]interface{}{"_key": "asc"},
+					},
+				},
+			},
+		},
+	}
+
+	var response queryLogAggregations
+	if err := q.aggregate(ctx, QueryLogIndex, queryLogFieldMappings, body, &response); err != nil {
+		return nil, fmt.Errorf("error listing top queries: %w", err)
+	}
+	counts := make([]domain.QueryCount, 0, len(response.Aggregations.Queries.Buckets))
+	for _, bucket := range response.Aggregations.Queries.Buckets {
+		counts = append(counts, domain.QueryCount{Query: bucket.Key, Count: bucket.DocCount})
+	}
+	return counts, nil
+}
+
+// ClickStats returns the number of result clicks within the window and their average position
+func (q *QueryLogRepository) ClickStats(ctx context.Context, window domain.AnalyticsWindow) (domain.ClickStats, error) {
+	body := map[string]interface{}{
+		"size":             0,
+		"track_total_hits": true,
+		"query":            windowQuery("clicked_at", window),
+		"aggs": map[string]interface{}{
+			"avg_position": map[string]interface{}{
+				"avg": map[string]interface{}{"field": "position"},
+			},
+		},
+	}
+
+	var response queryLogAggregations
+	if err := q.aggregate(ctx, ResultClicksIndex, resultClickFieldMappings, body, &response); err != nil {
+		return domain.ClickStats{}, fmt.Errorf("error aggregating result clicks: %w", err)
+	}
+	stats := domain.ClickStats{Clicks: response.Hits.Total.Value}
+	if response.Aggregations.AvgPosition.Value != nil {
+		stats.AvgPosition = *response.Aggregations.AvgPosition.Value
+	}
+	return stats, nil
+}
+
+// LatencyPercentiles returns the percentiles of the search latencies within the window, in
+// milliseconds. Elasticsearch estimates percentiles; they are zero when nothing was searched.
+func (q *QueryLogRepository) LatencyPercentiles(ctx context.Context, window domain.AnalyticsWindow, percents []float64) ([]float64, error) {
+	body := map[string]interface{}{
+		"size":  0,
+		"query": windowQuery("logged_at", window),
+		"aggs": map[string]interface{}{
+			"latency": map[string]interface{}{
+				"percentiles": map[string]interface{}{
+					"field":    "latency_ms",
+					"percents": percents,
+					"keyed":    false,
+				},
+			},
+		},
+	}
+
+	var response queryLogAggregations
+	if err := q.aggregate(ctx, QueryLogIndex, queryLogFieldMappings, body, &response); err != nil {
+		return nil, fmt.Errorf("error computing latency percentiles: %w", err)
+	}
+	byPercent := make(map[string]float64, len(response.Aggregations.Latency.Values))
+	for _, value := range response.Aggregations.Latency.Values {
+		if value.Value != nil {
+			byPercent[strconv.FormatFloat(value.Key, 'f', -1, 64)] = *value.Value
+		}
+	}
+	latencies := make([]float64, len(percents))
+	for i, percent := range percents {
+		latencies[i] = byPercent[strconv.FormatFloat(percent, 'f', -1, 64)]
+	}
+	return latencies, nil
+}
+
+// PopularQueries returns the logged queries starting with the prefix that found results,
+// most searched first
+func (q *QueryLogRepository) PopularQueries(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	body := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"prefix": map[string]interface{}{"query": domain.NormalizeQuery(prefix)}},
+					map[string]interface{}{"range": map[string]interface{}{"total_hits": map[string]interface{}{"gt": 0}}},
+				},
+				"must_not": []interface{}{map[string]interface{}{"term": map[string]interface{}{"query": ""}}},
+			},
+		},
+		"aggs": map[string]interface{}{
+			"queries": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": "query",
+					"size":  limit,
+				},
+				"aggs": map[string]interface{}{
+					"last_used": map[string]interface{}{
+						"max": map[string]interface{}{"field": "logged_at"},
+					},
+				},
+			},
+		},
+	}
+
+	var response queryLogAggregations
+	if err := q.aggregate(ctx, QueryLogIndex, queryLogFieldMappings, body, &response); err != nil {
+		return nil, fmt.Errorf("error getting popular queries: %w", err)
+	}
+	suggestions := make([]domain.SearchSuggestion, 0, len(response.Aggregations.Queries.Buckets))
+	for _, bucket := range response.Aggregations.Queries.Buckets {
+		suggestion := domain.SearchSuggestion{
+			Text:     bucket.Key,
+			Source:   domain.SuggestionSourcePopular,
+			UseCount: bucket.DocCount,
+		}
+		if bucket.LastUsed.Value != nil {
+			suggestion.LastUsedTime = time.UnixMilli(int64(*bucket.LastUsed.Value)).UTC()
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, nil
+}
+
+// DocumentClickStats returns how often each of the documents was returned and clicked across
+// all logged searches. Documents never returned are absent from the map.
+func (q *QueryLogRepository) DocumentClickStats(ctx context.Context, documentIDs []string) (map[string]domain.DocumentClickStats, error) {
+	stats := make(map[string]domain.DocumentClickStats, len(documentIDs))
+	if len(documentIDs) == 0 {
+		return stats, nil
+	}
+
+	var impressions queryLogAggregations
+	if err := q.aggregate(ctx, QueryLogIndex, queryLogFieldMappings, documentCountsBody("document_ids", documentIDs), &impressions); err != nil {
+		return nil, fmt.Errorf("error counting document impressions: %w", err)
+	}
+	for _, bucket := range impressions.Aggregations.Documents.Buckets {
+		stats[bucket.Key] = domain.DocumentClickStats{DocumentID: bucket.Key, Impressions: bucket.DocCount}
+	}
+
+	var clicks queryLogAggregations
+	if err := q.aggregate(ctx, ResultClicksIndex, resultClickFieldMappings, documentCountsBody("document_id", documentIDs), &clicks); err != nil {
+		return nil, fmt.Errorf("error counting document clicks: %w", err)
+	}
+	for _, bucket := range clicks.Aggregations.Documents.Buckets {
+		stat := stats[bucket.Key]
+		stat.DocumentID = bucket.Key
+		stat.Clicks = bucket.DocCount
+		// Clicks on results logged before impressions were recorded still count as seen
+		stat.Impressions = max(stat.Impressions, stat.Clicks)
+		stats[bucket.Key] = stat
+	}
+	return stats, nil
+}
+
+// ClickedSearches returns the searches whose results were most recently clicked within the
+// window, along with the documents they returned and the documents clicked
+func (q *QueryLogRepository) ClickedSearches(ctx context.Context, window domain.AnalyticsWindow, limit int) ([]domain.ClickedSearch, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	body := map[string]interface{}{
+		"size":  0,
+		"query": windowQuery("clicked_at", window),
+		"aggs": map[string]interface{}{
+			"queries": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": "query_id",
+					"size":  limit,
+					"order": map[string]interface{}{"last_used": "desc"},
+				},
+				"aggs": map[string]interface{}{
+					"last_used": map[string]interface{}{
+						"max": map[string]interface{}{"field": "clicked_at"},
+					},
+					"documents": map[string]interface{}{
+						"terms": map[string]interface{}{"field": "document_id"},
+					},
+				},
+			},
+		},
+	}
+
+	var response queryLogAggregations
+	if err := q.aggregate(ctx, ResultClicksIndex, resultClickFieldMappings, body, &response); err != nil {
+		return nil, fmt.Errorf("error listing clicked searches: %w", err)
+	}
+	buckets := response.Aggregations.Queries.Buckets
+	if len(buckets) == 0 {
+		return []domain.ClickedSearch{}, nil
+	}
+
+	queryIDs := make([]string, 0, len(buckets))
+	clicked := make(map[string][]string, len(buckets))
+	for _, bucket := range buckets {
+		queryIDs = append(queryIDs, bucket.Key)
+		for _, document := range bucket.Documents.Buckets {
+			clicked[bucket.Key] = append(clicked[bucket.Key], document.Key)
+		}
+	}
+	res, err := q.client.PerformRequest(ctx, &esapi.MgetRequest{
+		Index: q.client.IndexNameWithPrefix(QueryLogIndex),
+		Body:  bytes.NewReader(mustMarshalJSON(map[string]interface{}{"ids": queryIDs})),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting clicked searches: %w", err)
+	}
+
+	var logs struct {
+		Docs []struct {
+			Found  bool `json:"found"`
+			Source struct {
+				QueryID     string   `json:"query_id"`
+				Query       string   `json:"query"`
+				DocumentIDs []string `json:"document_ids"`
+			} `json:"_source"`
+		} `json:"docs"`
+	}
+	if err := parseResponse(res.Body, &logs); err != nil {
+		return nil, fmt.Errorf("error parsing clicked searches response: %w", err)
+	}
+	searches := make([]domain.ClickedSearch, 0, len(logs.Docs))
+	for _, doc := range logs.Docs {
+		if !doc.Found || doc.Source.Query == "" {
+			continue
+		}
+		searches = append(searches, domain.ClickedSearch{
+			QueryID:     doc.Source.QueryID,
+			Query:       doc.Source.Query,
+			DocumentIDs: doc.Source.DocumentIDs,
+			Clicked:     clicked[doc.Source.QueryID],
+		})
+	}
+	return searches, nil
+}
+
+// documentCountsBody counts the occurrences of each of the documents in a keyword field
+func documentCountsBody(field string, documentIDs []string) map[string]interface{} {
+	return map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"terms": map[string]interface{}{field: documentIDs},
+		},
+		"aggs": map[string]interface{}{
+			"documents": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field":   field,
+					"size":    len(documentIDs),
+					"include": documentIDs,
+				},
+			},
+		},
+	}
+}
+
+// queryLogAggregations is the part of the analytics search responses read by the repository
+type queryLogAggregations struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+	} `json:"hits"`
+	Aggregations struct {
+		Queries struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int    `json:"doc_count"`
+				LastUsed struct {
+					Value *float64 `json:"value"`
+				} `json:"last_used"`
+				Documents documentBuckets `json:"documents"`
+			} `json:"buckets"`
+		} `json:"queries"`
+		Documents   documentBuckets `json:"documents"`
+		AvgPosition struct {
+			Value *float64 `json:"value"`
+		} `json:"avg_position"`
+		Latency struct {
+			Values []struct {
+				Key   float64  `json:"key"`
+				Value *float64 `json:"value"`
+			} `json:"values"`
+		} `json:"latency"`
+	} `json:"aggregations"`
+}
+
+// documentBuckets is a terms aggregation of document IDs
+type documentBuckets struct {
+	Buckets []struct {
+		Key      string `json:"key"`
+		DocCount int    `json:"doc_count"`
+	} `json:"buckets"`
+}
+
+// aggregate runs an aggregation-only search on an analytics index, creating the index if needed
+func (q *QueryLogRepository) aggregate(ctx context.Context, indexName string, mappings map[string]interface{}, body map[string]interface{}, response *queryLogAggregations) error {
+	if err := q.client.EnsureFieldMappings(ctx, indexName, mappings); err != nil {
+		return fmt.Errorf("error preparing %s index: %w", indexName, err)
+	}
+	res, err := q.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Index: []string{q.client.IndexNameWithPrefix(indexName)},
+		Body:  bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return err
+	}
+	return parseResponse(res.Body, response)
+}
+
+// windowQuery restricts a date field to the half-open window
+func windowQuery(field string, window domain.AnalyticsWindow) map[string]interface{} {
+	return map[string]interface{}{
+		"range": map[string]interface{}{
+			field: map[string]interface{}{
+				"gte": window.From,
+				"lt":  window.To,
+			},
+		},
+	}
+}
@@ -0,0 +1,229 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	QueryRulesIndex = "query_rules"
+	// queryRulePageSize is the number of query rules read per page when listing them
+	queryRulePageSize = 500
+)
+
+// queryRuleFieldMappings are the fields of the query rules index
+var queryRuleFieldMappings = map[string]interface{}{
+	"id":           map[string]interface{}{"type": "keyword"},
+	"index_id":     map[string]interface{}{"type": "keyword"},
+	"name":         map[string]interface{}{"type": "keyword"},
+	"match_type":   map[string]interface{}{"type": "keyword"},
+	"pattern":      map[string]interface{}{"type": "keyword", "index": false},
+	"pinned_ids":   map[string]interface{}{"type": "keyword"},
+	"buried_ids":   map[string]interface{}{"type": "keyword"},
+	"redirect_url": map[string]interface{}{"type": "keyword", "index": false},
+	"start_at":     map[string]interface{}{"type": "date"},
+	"end_at":       map[string]interface{}{"type": "date"},
+	"created_at":   map[string]interface{}{"type": "date"},
+	"updated_at":   map[string]interface{}{"type": "date"},
+}
+
+// QueryRuleRepository implements the outgoing.QueryRuleRepository interface using Elasticsearch
+type QueryRuleRepository struct {
+	client *Client
+}
+
+var _ outgoing.QueryRuleRepository = (*QueryRuleRepository)(nil)
+
+// NewQueryRuleRepository creates a new query rule repository
+func NewQueryRuleRepository(client *Client) *QueryRuleRepository {
+	return &QueryRuleRepository{
+		client: client,
+	}
+}
+
+// queryRule is the stored form of a query rule
+type queryRule struct {
+	ID          string    `json:"id"`
+	IndexID     string    `json:"index_id"`
+	Name        string    `json:"name"`
+	MatchType   string    `json:"match_type"`
+	Pattern     string    `json:"pattern"`
+	PinnedIDs   []string  `json:"pinned_ids"`
+	BuriedIDs   []string  `json:"buried_ids"`
+	RedirectURL string    `json:"redirect_url"`
+	StartAt     time.Time `json:"start_at"`
+	EndAt       time.Time `json:"end_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// toDomain converts the stored query rule to its domain form
+func (r queryRule) toDomain() *domain.QueryRule {
+	return &domain.QueryRule{
+		ID:          r.ID,
+		IndexID:     r.IndexID,
+		Name:        r.Name,
+		MatchType:   domain.QueryRuleMatchType(r.MatchType),
+		Pattern:     r.Pattern,
+		PinnedIDs:   r.PinnedIDs,
+		BuriedIDs:   r.BuriedIDs,
+		RedirectURL: r.RedirectURL,
+		StartAt:     r.StartAt,
+		EndAt:       r.EndAt,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+// Save creates or replaces a query rule under its ID. The index is refreshed before returning
+// so that the rule applies to the next search.
+func (q *QueryRuleRepository) Save(ctx context.Context, rule *domain.QueryRule) error {
+	if rule == nil {
+		return errors.New("query rule cannot be nil")
+	}
+	if rule.ID == "" {
+		return errors.New("query rule ID cannot be empty")
+	}
+	if err := q.client.EnsureFieldMappings(ctx, QueryRulesIndex, queryRuleFieldMappings); err != nil {
+		return fmt.Errorf("error preparing query rules index: %w", err)
+	}
+
+	stored := queryRule{
+		ID:          rule.ID,
+		IndexID:     rule.IndexID,
+		Name:        rule.Name,
+		MatchType:   string(rule.MatchType),
+		Pattern:     rule.Pattern,
+		PinnedIDs:   rule.PinnedIDs,
+		BuriedIDs:   rule.BuriedIDs,
+		RedirectURL: rule.RedirectURL,
+		StartAt:     rule.StartAt,
+		EndAt:       rule.EndAt,
+		CreatedAt:   rule.CreatedAt,
+		UpdatedAt:   rule.UpdatedAt,
+	}
+	res, err := q.client.PerformRequest(ctx, &esapi.IndexRequest{
+		Index:      q.client.IndexNameWithPrefix(QueryRulesIndex),
+		DocumentID: rule.ID,
+		Body:       bytes.NewReader(mustMarshalJSON(stored)),
+		Refresh:    "wait_for",
+	})
+	if err != nil {
+		return fmt.Errorf("error saving query rule: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
+
+// GetByID returns a query rule by ID, or nil when it does not exist
+func (q *QueryRuleRepository) GetByID(ctx context.Context, id string) (*domain.QueryRule, error) {
+	if id == "" {
+		return nil, errors.New("query rule ID cannot be empty")
+	}
+	if err := q.client.EnsureFieldMappings(ctx, QueryRulesIndex, queryRuleFieldMappings); err != nil {
+		return nil, fmt.Errorf("error preparing query rules index: %w", err)
+	}
+
+	res, err := q.client.PerformRequest(ctx, &esapi.GetRequest{
+		Index:      q.client.IndexNameWithPrefix(QueryRulesIndex),
+		DocumentID: id,
+	})
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		closeBody(res.Body)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting query rule: %w", err)
+	}
+	var response struct {
+		Source queryRule `json:"_source"`
+	}
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing query rule response: %w", err)
+	}
+	return response.Source.toDomain(), nil
+}
+
+// ListByIndex returns the query rules of an index, oldest first, reading them page by page
+func (q *QueryRuleRepository) ListByIndex(ctx context.Context, indexID string) ([]*domain.QueryRule, error) {
+	if indexID == "" {
+		return nil, errors.New("index ID cannot be empty")
+	}
+	if err := q.client.EnsureFieldMappings(ctx, QueryRulesIndex, queryRuleFieldMappings); err != nil {
+		return nil, fmt.Errorf("error preparing query rules index: %w", err)
+	}
+
+	rules := make([]*domain.QueryRule, 0)
+	var searchAfter []interface{}
+	for {
+		body := map[string]interface{}{
+			"size": queryRulePageSize,
+			"query": map[string]interface{}{
+				"term": map[string]interface{}{"index_id": indexID},
+			},
+			"sort": []interface{}{
+				map[string]interface{}{"created_at": map[string]interface{}{"order": "asc"}},
+				map[string]interface{}{"id": map[string]interface{}{"order": "asc"}},
+			},
+		}
+		if searchAfter != nil {
+			body["search_after"] = searchAfter
+		}
+		res, err := q.client.PerformRequest(ctx, &esapi.SearchRequest{
+			Index: []string{q.client.IndexNameWithPrefix(QueryRulesIndex)},
+			Body:  bytes.NewReader(mustMarshalJSON(body)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing query rules: %w", err)
+		}
+		var response struct {
+			Hits struct {
+				Hits []struct {
+					Source queryRule     `json:"_source"`
+					Sort   []interface{} `json:"sort"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		if err := parseResponse(res.Body, &response); err != nil {
+			return nil, fmt.Errorf("error parsing query rules response: %w", err)
+		}
+
+		for _, hit := range response.Hits.Hits {
+			rules = append(rules, hit.Source.toDomain())
+		}
+		if len(response.Hits.Hits) < queryRulePageSize {
+			return rules, nil
+		}
+		searchAfter = response.Hits.Hits[len(response.Hits.Hits)-1].Sort
+	}
+}
+
+// Delete deletes a query rule by ID
+func (q *QueryRuleRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("query rule ID cannot be empty")
+	}
+	res, err := q.client.PerformRequest(ctx, &esapi.DeleteRequest{
+		Index:      q.client.IndexNameWithPrefix(QueryRulesIndex),
+		DocumentID: id,
+		Refresh:    "wait_for",
+	})
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		closeBody(res.Body)
+		return fmt.Errorf("query rule %s not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting query rule: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
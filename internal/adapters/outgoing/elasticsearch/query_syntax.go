@@ -0,0 +1,216 @@
+package elasticsearch
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// queryFieldNames maps the text fields of the query syntax to their document fields
+var queryFieldNames = map[domain.QueryField]string{
+	domain.TitleField:   "title",
+	domain.ContentField: "content",
+	domain.URLField:     "url",
+}
+
+// queryFilterFields maps the filter fields of the query syntax to their keyword fields
+var queryFilterFields = map[domain.QueryField]string{
+	domain.SiteField:    "domain.keyword",
+	domain.LangField:    "lang.keyword",
+	domain.TypeField:    "content_type.keyword",
+	domain.CrawledField: "last_crawled",
+}
+
+// buildExpressionQuery converts the syntax tree of a parsed query string into an Elasticsearch query
+func buildExpressionQuery(node domain.QueryNode, query *domain.SearchQuery) map[string]interface{} {
+	switch n := node.(type) {
+	case *domain.TermNode:
+		return buildTermNodeQuery(n, query)
+	case *domain.PhraseNode:
+		return buildPhraseNodeQuery(n, query)
+	case *domain.RangeNode:
+		bounds := map[string]interface{}{}
+		if !n.From.IsZero() {
+			bounds["gte"] = n.From
+		}
+		if !n.To.IsZero() {
+			bounds["lt"] = n.To
+		}
+		return map[string]interface{}{
+			"range": map[string]interface{}{queryFilterFields[n.Field]: bounds},
+		}
+	case *domain.NotNode:
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":     []interface{}{map[string]interface{}{"match_all": map[string]interface{}{}}},
+				"must_not": []interface{}{buildExpressionQuery(n.Clause, query)},
+			},
+		}
+	case *domain.BoolNode:
+		return buildBoolNodeQuery(n, query)
+	}
+	return map[string]interface{}{"match_all": map[string]interface{}{}}
+}
+
+// buildBoolNodeQuery combines the clauses of a boolean node. Negated and filter clauses of a
+// conjunction become must_not and filter clauses so that they do not affect the score.
+func buildBoolNodeQuery(node *domain.BoolNode, query *domain.SearchQuery) map[string]interface{} {
+	if node.Operator == domain.OrOperator {
+		should := make([]interface{}, 0, len(node.Clauses))
+		for _, clause := range node.Clauses {
+			should = append(should, buildExpressionQuery(clause, query))
+		}
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               should,
+				"minimum_should_match": 1,
+			},
+		}
+	}
+
+	must := make([]interface{}, 0, len(node.Clauses))
+	mustNot := make([]interface{}, 0)
+	filter := make([]interface{}, 0)
+	for _, clause := range node.Clauses {
+		switch {
+		case isNegation(clause):
+			mustNot = append(mustNot, buildExpressionQuery(clause.(*domain.NotNode).Clause, query))
+		case isFilterNode(clause):
+			filter = append(filter, buildExpressionQuery(clause, query))
+		default:
+			must = append(must, buildExpressionQuery(clause, query))
+		}
+	}
+	if len(must) == 0 {
+		must = append(must, map[string]interface{}{"match_all": map[string]interface{}{}})
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":     must,
+			"must_not": mustNot,
+			"filter":   filter,
+		},
+	}
+}
+
+// buildTermNodeQuery matches a word in its field, or in all search fields when it is unscoped
+func buildTermNodeQuery(node *domain.TermNode, query *domain.SearchQuery) map[string]interface{} {
+	if field, ok := queryFilterFields[node.Field]; ok {
+		if node.Wildcard {
+			return wildcardQuery(field, node.Value, 0)
+		}
+		clause := map[string]interface{}{
+			"term": map[string]interface{}{field: node.Value},
+		}
+		if node.Field != domain.SiteField {
+			return clause
+		}
+		// A site also matches its subdomains
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               []interface{}{clause, wildcardQuery(field, "*."+node.Value, 0)},
+				"minimum_should_match": 1,
+			},
+		}
+	}
+
+	if field, ok := queryFieldNames[node.Field]; ok {
+		if node.Wildcard {
+			if node.Field == domain.URLField {
+				field = "url.keyword"
+			}
+			return wildcardQuery(field, node.Value, 0)
+		}
+		match := map[string]interface{}{"query": node.Value}
+		if query.Type == domain.FuzzySearch {
+			match["fuzziness"] = fuzziness(query)
+		}
+		return map[string]interface{}{
+			"match": map[string]interface{}{field: match},
+		}
+	}
+
+	if node.Wildcard {
+		fields := searchFieldBoosts(query)
+		should := make([]interface{}, 0, len(fields))
+		for _, name := range sortedFieldNames(fields) {
+			should = append(should, wildcardQuery(name, node.Value, fields[name]))
+		}
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               should,
+				"minimum_should_match": 1,
+			},
+		}
+	}
+
+	multiMatch := map[string]interface{}{
+		"query":  node.Value,
+		"type":   "best_fields",
+		"fields": searchFieldList(query),
+	}
+	if query.Type == domain.FuzzySearch {
+		multiMatch["fuzziness"] = fuzziness(query)
+	}
+	return map[string]interface{}{"multi_match": multiMatch}
+}
+
+// buildPhraseNodeQuery matches a phrase in its field, or in all search fields when it is unscoped
+func buildPhraseNodeQuery(node *domain.PhraseNode, query *domain.SearchQuery) map[string]interface{} {
+	if field, ok := queryFieldNames[node.Field]; ok {
+		return map[string]interface{}{
+			"match_phrase": map[string]interface{}{field: node.Text},
+		}
+	}
+	return map[string]interface{}{
+		"multi_match": map[string]interface{}{
+			"query":  node.Text,
+			"type":   "phrase",
+			"fields": searchFieldList(query),
+		},
+	}
+}
+
+// wildcardQuery creates a case-insensitive wildcard query, boosted when boost is positive
+func wildcardQuery(field, value string, boost float32) map[string]interface{} {
+	wildcard := map[string]interface{}{
+		"value":            strings.ToLower(value),
+		"case_insensitive": true,
+	}
+	if boost > 0 {
+		wildcard["boost"] = boost
+	}
+	return map[string]interface{}{
+		"wildcard": map[string]interface{}{field: wildcard},
+	}
+}
+
+// isNegation reports whether a clause is negated
+func isNegation(node domain.QueryNode) bool {
+	_, ok := node.(*domain.NotNode)
+	return ok
+}
+
+// isFilterNode reports whether a clause only restricts the matching documents
+func isFilterNode(node domain.QueryNode) bool {
+	switch n := node.(type) {
+	case *domain.TermNode:
+		return n.Field.IsFilter()
+	case *domain.PhraseNode:
+		return n.Field.IsFilter()
+	case *domain.RangeNode:
+		return true
+	}
+	return false
+}
+
+// sortedFieldNames returns the names of boosted fields in a stable order
+func sortedFieldNames(fields map[string]float32) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,247 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	SavedSearchesIndex = "saved_searches"
+	// savedSearchPageSize is the number of saved searches read per page when listing them
+	savedSearchPageSize = 500
+)
+
+// savedSearchFieldMappings are the fields of the saved searches index. Queries and channels are
+// stored as they are, without being indexed.
+var savedSearchFieldMappings = map[string]interface{}{
+	"id":          map[string]interface{}{"type": "keyword"},
+	"user_id":     map[string]interface{}{"type": "keyword"},
+	"name":        map[string]interface{}{"type": "keyword"},
+	"query":       map[string]interface{}{"type": "object", "enabled": false},
+	"channels":    map[string]interface{}{"type": "object", "enabled": false},
+	"last_run_at": map[string]interface{}{"type": "date"},
+	"created_at":  map[string]interface{}{"type": "date"},
+	"updated_at":  map[string]interface{}{"type": "date"},
+}
+
+// SavedSearchRepository implements the outgoing.SavedSearchRepository interface using Elasticsearch
+type SavedSearchRepository struct {
+	client *Client
+}
+
+var _ outgoing.SavedSearchRepository = (*SavedSearchRepository)(nil)
+
+// NewSavedSearchRepository creates a new saved search repository
+func NewSavedSearchRepository(client *Client) *SavedSearchRepository {
+	return &SavedSearchRepository{
+		client: client,
+	}
+}
+
+// savedSearch is the stored form of a saved search
+type savedSearch struct {
+	ID        string                `json:"id"`
+	UserID    string                `json:"user_id"`
+	Name      string                `json:"name"`
+	Query     domain.SearchQuery    `json:"query"`
+	Channels  []domain.AlertChannel `json:"channels"`
+	LastRunAt time.Time             `json:"last_run_at"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// toDomain converts the stored saved search to its domain form
+func (s savedSearch) toDomain() *domain.SavedSearch {
+	return &domain.SavedSearch{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		Name:      s.Name,
+		Query:     s.Query,
+		Channels:  s.Channels,
+		LastRunAt: s.LastRunAt,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+// Save creates or replaces a saved search under its ID. The index is refreshed before returning
+// so that the saved search is listed right away.
+func (s *SavedSearchRepository) Save(ctx context.Context, search *domain.SavedSearch) error {
+	if search == nil {
+		return errors.New("saved search cannot be nil")
+	}
+	if search.ID == "" {
+		return errors.New("saved search ID cannot be empty")
+	}
+	if err := s.client.EnsureFieldMappings(ctx, SavedSearchesIndex, savedSearchFieldMappings); err != nil {
+		return fmt.Errorf("error preparing saved searches index: %w", err)
+	}
+
+	stored := savedSearch{
+		ID:        search.ID,
+		UserID:    search.UserID,
+		Name:      search.Name,
+		Query:     search.Query,
+		Channels:  search.Channels,
+		LastRunAt: search.LastRunAt,
+		CreatedAt: search.CreatedAt,
+		UpdatedAt: search.UpdatedAt,
+	}
+	res, err := s.client.PerformRequest(ctx, &esapi.IndexRequest{
+		Index:      s.client.IndexNameWithPrefix(SavedSearchesIndex),
+		DocumentID: search.ID,
+		Body:       bytes.NewReader(mustMarshalJSON(stored)),
+		Refresh:    "wait_for",
+	})
+	if err != nil {
+		return fmt.Errorf("error saving saved search: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
+
+// GetByID returns a saved search by ID, or nil when it does not exist
+func (s *SavedSearchRepository) GetByID(ctx context.Context, id string) (*domain.SavedSearch, error) {
+	if id == "" {
+		return nil, errors.New("saved search ID cannot be empty")
+	}
+	if err := s.client.EnsureFieldMappings(ctx, SavedSearchesIndex, savedSearchFieldMappings); err != nil {
+		return nil, fmt.Errorf("error preparing saved searches index: %w", err)
+	}
+
+	res, err := s.client.PerformRequest(ctx, &esapi.GetRequest{
+		Index:      s.client.IndexNameWithPrefix(SavedSearchesIndex),
+		DocumentID: id,
+	})
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		closeBody(res.Body)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting saved search: %w", err)
+	}
+	var response struct {
+		Source savedSearch `json:"_source"`
+	}
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing saved search response: %w", err)
+	}
+	return response.Source.toDomain(), nil
+}
+
+// ListByUser returns the saved searches of a user, oldest first
+func (s *SavedSearchRepository) ListByUser(ctx context.Context, userID string) ([]*domain.SavedSearch, error) {
+	if userID == "" {
+		return nil, errors.New("user ID cannot be empty")
+	}
+	return s.list(ctx, map[string]interface{}{
+		"term": map[string]interface{}{"user_id": userID},
+	})
+}
+
+// List returns every saved search, oldest first
+func (s *SavedSearchRepository) List(ctx context.Context) ([]*domain.SavedSearch, error) {
+	return s.list(ctx, map[string]interface{}{"match_all": map[string]interface{}{}})
+}
+
+// list returns the saved searches matching the query, oldest first, reading them page by page
+func (s *SavedSearchRepository) list(ctx context.Context, query map[string]interface{}) ([]*domain.SavedSearch, error) {
+	if err := s.client.EnsureFieldMappings(ctx, SavedSearchesIndex, savedSearchFieldMappings); err != nil {
+		return nil, fmt.Errorf("error preparing saved searches index: %w", err)
+	}
+
+	searches := make([]*domain.SavedSearch, 0)
+	var searchAfter []interface{}
+	for {
+		body := map[string]interface{}{
+			"size":  savedSearchPageSize,
+			"query": query,
+			"sort": []interface{}{
+				map[string]interface{}{"created_at": map[string]interface{}{"order": "asc"}},
+				map[string]interface{}{"id": map[string]interface{}{"order": "asc"}},
+			},
+		}
+		if searchAfter != nil {
+			body["search_after"] = searchAfter
+		}
+		res, err := s.client.PerformRequest(ctx, &esapi.SearchRequest{
+			Index: []string{s.client.IndexNameWithPrefix(SavedSearchesIndex)},
+			Body:  bytes.NewReader(mustMarshalJSON(body)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing saved searches: %w", err)
+		}
+		var response struct {
+			Hits struct {
+				Hits []struct {
+					Source savedSearch   `json:"_source"`
+					Sort   []interface{} `json:"sort"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		if err := parseResponse(res.Body, &response); err != nil {
+			return nil, fmt.Errorf("error parsing saved searches response: %w", err)
+		}
+
+		for _, hit := range response.Hits.Hits {
+			searches = append(searches, hit.Source.toDomain())
+		}
+		if len(response.Hits.Hits) < savedSearchPageSize {
+			return searches, nil
+		}
+		searchAfter = response.Hits.Hits[len(response.Hits.Hits)-1].Sort
+	}
+}
+
+// Delete deletes a saved search by ID
+func (s *SavedSearchRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("saved search ID cannot be empty")
+	}
+	res, err := s.client.PerformRequest(ctx, &esapi.DeleteRequest{
+		Index:      s.client.IndexNameWithPrefix(SavedSearchesIndex),
+		DocumentID: id,
+		Refresh:    "wait_for",
+	})
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		closeBody(res.Body)
+		return fmt.Errorf("saved search %s not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting saved search: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
+
+// MarkRun records when a saved search last ran
+func (s *SavedSearchRepository) MarkRun(ctx context.Context, id string, runAt time.Time) error {
+	if id == "" {
+		return errors.New("saved search ID cannot be empty")
+	}
+	res, err := s.client.PerformRequest(ctx, &esapi.UpdateRequest{
+		Index:      s.client.IndexNameWithPrefix(SavedSearchesIndex),
+		DocumentID: id,
+		Body: bytes.NewReader(mustMarshalJSON(map[string]interface{}{
+			"doc": map[string]interface{}{"last_run_at": runAt},
+		})),
+	})
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		closeBody(res.Body)
+		return fmt.Errorf("saved search %s not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("error marking saved search as run: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
@@ -0,0 +1,244 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// defaultSearchFields are the document fields queried when the search query does not specify any
+var defaultSearchFields = map[string]float32{
+	"title":         3,
+	"meta_desc":     2,
+	"meta_keywords": 2,
+	"content":       1,
+}
+
+// filterFields maps the supported SearchQuery filters to their keyword fields
+var filterFields = map[string]string{
+	"index_id":     "index_id.keyword",
+	"content_type": "content_type.keyword",
+	"lang":         "lang.keyword",
+}
+
+// buildSearchBody converts a domain search query into an Elasticsearch search body without pagination.
+// Decay functions of the query boost the relevance of recent documents; explain queries
+// return the score breakdown of each hit, and highlighted fields their best snippets, which
+// are snippetSize characters long unless the query sets their size.
+func buildSearchBody(query *domain.SearchQuery, snippetSize int) map[string]interface{} {
+	body := map[string]interface{}{
+		"query":            applyFreshness(buildBoolQuery(query), query.DecayFunctions),
+		"sort":             buildSortClauses(query),
+		"track_total_hits": true,
+	}
+	if source := buildSourceFilter(query); source != nil {
+		body["_source"] = source
+	}
+	if query.Explain {
+		body["explain"] = true
+	}
+	if len(query.HighlightFields) > 0 {
+		body["highlight"] = buildHighlight(query, snippetSize)
+	}
+	return body
+}
+
+// buildHighlight creates the highlight section returning the best scoring fragments of the
+// highlighted fields, HTML-encoded except for the tags surrounding the matches
+func buildHighlight(query *domain.SearchQuery, snippetSize int) map[string]interface{} {
+	options := query.Highlight.WithDefaults(snippetSize)
+	fields := make(map[string]interface{}, len(query.HighlightFields))
+	for _, field := range query.HighlightFields {
+		fields[field] = map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"fields":              fields,
+		"number_of_fragments": options.FragmentCount,
+		"fragment_size":       options.FragmentSize,
+		"pre_tags":            []string{options.PreTag},
+		"post_tags":           []string{options.PostTag},
+		"encoder":             "html",
+		"order":               "score",
+	}
+}
+
+// buildBoolQuery combines the text query with the filters of the search query
+func buildBoolQuery(query *domain.SearchQuery) map[string]interface{} {
+	must := []interface{}{buildTextQuery(query)}
+	for _, term := range query.ExactTerms {
+		must = append(must, map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  term,
+				"type":   "phrase",
+				"fields": searchFieldList(query),
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":   must,
+			"filter": buildFilterClauses(query),
+		},
+	}
+}
+
+// buildTextQuery creates the full-text part of the query according to the search type.
+// Parsed query strings are translated from their syntax tree instead.
+func buildTextQuery(query *domain.SearchQuery) map[string]interface{} {
+	if query.UseQuerySyntax {
+		if query.Expression == nil {
+			return map[string]interface{}{"match_all": map[string]interface{}{}}
+		}
+		return buildExpressionQuery(query.Expression, query)
+	}
+
+	multiMatch := map[string]interface{}{
+		"query":  query.Query,
+		"fields": searchFieldList(query),
+	}
+
+	switch query.Type {
+	case domain.ExactMatchSearch:
+		multiMatch["type"] = "phrase"
+	case domain.FuzzySearch:
+		multiMatch["type"] = "best_fields"
+		multiMatch["fuzziness"] = fuzziness(query)
+	default:
+		multiMatch["type"] = "best_fields"
+	}
+
+	if query.MinimumShouldMatch != "" && query.Type != domain.ExactMatchSearch {
+		multiMatch["minimum_should_match"] = query.MinimumShouldMatch
+	}
+
+	return map[string]interface{}{
+		"multi_match": multiMatch,
+	}
+}
+
+// buildFilterClauses creates the non-scoring filters of the query
+func buildFilterClauses(query *domain.SearchQuery) []interface{} {
+	filters := make([]interface{}, 0)
+
+	for name, field := range filterFields {
+		if clause := termFilter(field, query.Filters[name]); clause != nil {
+			filters = append(filters, clause)
+		}
+	}
+
+	if query.Language != "" {
+		filters = append(filters, termFilter("lang.keyword", query.Language))
+	}
+
+	if query.TimeRange != nil && !query.TimeRange.From.IsZero() {
+		field := "last_crawled"
+		if query.TimeRange.Field != "" {
+			field = query.TimeRange.Field
+		}
+		lower, upper := "gt", "lt"
+		if query.TimeRange.Included {
+			lower, upper = "gte", "lte"
+		}
+		bounds := map[string]interface{}{
+			lower: query.TimeRange.From,
+		}
+		if !query.TimeRange.To.IsZero() {
+			bounds[upper] = query.TimeRange.To
+		}
+		filters = append(filters, map[string]interface{}{
+			"range": map[string]interface{}{
+				field: bounds,
+			},
+		})
+	}
+
+	return filters
+}
+
+// termFilter creates a term or terms filter for a filter value, or nil if the value is empty
+func termFilter(field string, value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return map[string]interface{}{
+			"term": map[string]interface{}{field: v},
+		}
+	case []string:
+		if len(v) == 0 {
+			return nil
+		}
+		return map[string]interface{}{
+			"terms": map[string]interface{}{field: v},
+		}
+	}
+	return nil
+}
+
+// buildSortClauses creates the sort section, always ending with the relevance score
+func buildSortClauses(query *domain.SearchQuery) []interface{} {
+	order := string(domain.Descending)
+	if query.SortOrder != "" {
+		order = string(query.SortOrder)
+	}
+
+	clauses := make([]interface{}, 0, len(query.SortFields)+1)
+	for _, field := range query.SortFields {
+		clauses = append(clauses, map[string]interface{}{
+			field: map[string]interface{}{"order": order},
+		})
+	}
+	clauses = append(clauses, map[string]interface{}{
+		"_score": map[string]interface{}{"order": "desc"},
+	})
+	return clauses
+}
+
+// buildSourceFilter restricts the returned source fields, or returns nil if no restriction is needed
+func buildSourceFilter(query *domain.SearchQuery) map[string]interface{} {
+	if len(query.IncludeFields) == 0 && len(query.ExcludeFields) == 0 {
+		return nil
+	}
+	source := map[string]interface{}{}
+	if len(query.IncludeFields) > 0 {
+		source["includes"] = query.IncludeFields
+	}
+	if len(query.ExcludeFields) > 0 {
+		source["excludes"] = query.ExcludeFields
+	}
+	return source
+}
+
+// searchFieldList returns the boosted field list in a stable order
+func searchFieldList(query *domain.SearchQuery) []string {
+	fields := searchFieldBoosts(query)
+	names := sortedFieldNames(fields)
+
+	list := make([]string, 0, len(names))
+	for _, name := range names {
+		list = append(list, fmt.Sprintf("%s^%g", name, fields[name]))
+	}
+	return list
+}
+
+// searchFieldBoosts returns the fields searched by the query with their boosts
+func searchFieldBoosts(query *domain.SearchQuery) map[string]float32 {
+	if len(query.SearchFields) == 0 {
+		return defaultSearchFields
+	}
+	return query.SearchFields
+}
+
+// fuzziness converts the query fuzziness settings to an Elasticsearch fuzziness value
+func fuzziness(query *domain.SearchQuery) string {
+	if query.FuzzyLevelString != "" {
+		return query.FuzzyLevelString
+	}
+	if query.FuzzyLevel > 0 {
+		return strconv.Itoa(query.FuzzyLevel)
+	}
+	return "AUTO"
+}
@@ -0,0 +1,90 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/elasticsearch/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// searchResponse represents the parts of an Elasticsearch search response used by the repositories
+type searchResponse struct {
+	PitID string `json:"pit_id"`
+	Hits  struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []searchHit `json:"hits"`
+	} `json:"hits"`
+}
+
+// searchHit represents a single hit of a search response
+type searchHit struct {
+	ID          string              `json:"_id"`
+	Score       *float64            `json:"_score"`
+	Source      models.Document     `json:"_source"`
+	Sort        []json.RawMessage   `json:"sort"`
+	Explanation *explanation        `json:"_explanation"`
+	Highlight   map[string][]string `json:"highlight"`
+}
+
+// explanation is a node of the score explanation of a hit returned by explain searches
+type explanation struct {
+	Value       float64        `json:"value"`
+	Description string         `json:"description"`
+	Details     []*explanation `json:"details"`
+}
+
+// toDomain converts the explanation tree into its domain form
+func (e *explanation) toDomain() *domain.ScoreExplanation {
+	if e == nil {
+		return nil
+	}
+	details := make([]*domain.ScoreExplanation, 0, len(e.Details))
+	for _, detail := range e.Details {
+		details = append(details, detail.toDomain())
+	}
+	return domain.NewScoreExplanation(e.Value, e.Description, details...)
+}
+
+// parseSearchResponse decodes a search response body
+func parseSearchResponse(body io.ReadCloser) (*searchResponse, error) {
+	var response searchResponse
+	if err := parseResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing search response: %w", err)
+	}
+	return &response, nil
+}
+
+// toDomain converts a hit into a domain document carrying the hit ID, score, explanation and snippets
+func (h searchHit) toDomain() *domain.Document {
+	doc := h.Source
+	doc.ID = h.ID
+	if h.Score != nil && *h.Score > 0 {
+		doc.Score = *h.Score
+	}
+	document := doc.ToDomain()
+	document.Explanation = h.Explanation.toDomain()
+	document.Highlights = h.Highlight
+	return document
+}
+
+// sortValues returns the hit sort values in a form that can be sent back as search_after
+func (h searchHit) sortValues() []interface{} {
+	values := make([]interface{}, len(h.Sort))
+	for i, value := range h.Sort {
+		values[i] = value
+	}
+	return values
+}
+
+// documents converts all hits of the response into domain documents
+func (r *searchResponse) documents() []*domain.Document {
+	documents := make([]*domain.Document, 0, len(r.Hits.Hits))
+	for _, hit := range r.Hits.Hits {
+		documents = append(documents, hit.toDomain())
+	}
+	return documents
+}
@@ -0,0 +1,108 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	// correctionSuggestName is the name of the phrase suggestion in search requests
+	correctionSuggestName = "correction"
+	// correctionField is the field whose terms are used to correct queries
+	correctionField = "content"
+)
+
+// SpellingCorrector implements the outgoing.SpellingCorrector interface using the phrase suggester
+type SpellingCorrector struct {
+	client *Client
+}
+
+var _ outgoing.SpellingCorrector = (*SpellingCorrector)(nil)
+
+// NewSpellingCorrector creates a new spelling corrector
+func NewSpellingCorrector(client *Client) *SpellingCorrector {
+	return &SpellingCorrector{
+		client: client,
+	}
+}
+
+// Correct returns the best phrase suggestion for the query, or nil when the query needs no correction
+func (s *SpellingCorrector) Correct(ctx context.Context, query string) (*domain.SearchSuggestion, error) {
+	text := strings.TrimSpace(query)
+	if text == "" {
+		return nil, nil
+	}
+
+	phrase := map[string]interface{}{
+		"field":      correctionField,
+		"size":       1,
+		"max_errors": 2,
+		"direct_generator": []interface{}{
+			map[string]interface{}{
+				"field":        correctionField,
+				"suggest_mode": "missing",
+			},
+		},
+	}
+	// Corrections are drawn from the terms of every document, so only those matching a document
+	// the caller can read are kept
+	if filter := accessFilter(domain.AccessScopeFromContext(ctx)); filter != nil {
+		phrase["collate"] = map[string]interface{}{
+			"query": map[string]interface{}{
+				"source": map[string]interface{}{
+					"bool": map[string]interface{}{
+						"must": map[string]interface{}{
+							"match": map[string]interface{}{
+								correctionField: map[string]interface{}{"query": "{{suggestion}}", "operator": "and"},
+							},
+						},
+						"filter": filter,
+					},
+				},
+			},
+		}
+	}
+	body := map[string]interface{}{
+		"size": 0,
+		"suggest": map[string]interface{}{
+			correctionSuggestName: map[string]interface{}{
+				"text":   text,
+				"phrase": phrase,
+			},
+		},
+	}
+	res, err := s.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Index: []string{s.client.IndexNameWithPrefix(DocumentIndex)},
+		Body:  bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error correcting query: %w", err)
+	}
+
+	var response suggestResponse
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing correction response: %w", err)
+	}
+
+	for _, entry := range response.Suggest[correctionSuggestName] {
+		for _, option := range entry.Options {
+			if domain.NormalizeQuery(option.Text) == domain.NormalizeQuery(text) {
+				continue
+			}
+			return &domain.SearchSuggestion{
+				Text:          option.Text,
+				Source:        domain.SuggestionSourceCorrection,
+				Score:         option.score(),
+				CorrectedFrom: query,
+			}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,100 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// completionSuggestName is the name of the completion suggestion in search requests
+const completionSuggestName = "completion"
+
+// SuggestionCompleter implements the outgoing.SuggestionCompleter interface using the completion suggester
+type SuggestionCompleter struct {
+	client *Client
+}
+
+var _ outgoing.SuggestionCompleter = (*SuggestionCompleter)(nil)
+
+// NewSuggestionCompleter creates a new suggestion completer
+func NewSuggestionCompleter(client *Client) *SuggestionCompleter {
+	return &SuggestionCompleter{
+		client: client,
+	}
+}
+
+// suggestResponse represents the suggest section of a search response
+type suggestResponse struct {
+	Suggest map[string][]struct {
+		Options []suggestOption `json:"options"`
+	} `json:"suggest"`
+}
+
+// suggestOption is a single option of a completion or phrase suggestion
+type suggestOption struct {
+	Text            string  `json:"text"`
+	PhraseScore     float64 `json:"score"`
+	CompletionScore float64 `json:"_score"`
+}
+
+// score returns the score of the option, which completion and phrase suggestions report differently
+func (o suggestOption) score() float64 {
+	if o.CompletionScore != 0 {
+		return o.CompletionScore
+	}
+	return o.PhraseScore
+}
+
+// Complete returns the titles and keywords starting with the prefix
+func (s *SuggestionCompleter) Complete(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	if err := s.client.EnsureFieldMappings(ctx, DocumentIndex, documentFieldMappings); err != nil {
+		return nil, fmt.Errorf("error preparing document index: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"_source": false,
+		"suggest": map[string]interface{}{
+			completionSuggestName: map[string]interface{}{
+				"prefix": domain.NormalizeQuery(prefix),
+				"completion": map[string]interface{}{
+					"field":           "suggest",
+					"size":            limit,
+					"skip_duplicates": true,
+				},
+			},
+		},
+	}
+	res, err := s.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Index: []string{s.client.IndexNameWithPrefix(DocumentIndex)},
+		Body:  bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error completing prefix: %w", err)
+	}
+
+	var response suggestResponse
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing completion response: %w", err)
+	}
+
+	suggestions := make([]domain.SearchSuggestion, 0, limit)
+	for _, entry := range response.Suggest[completionSuggestName] {
+		for _, option := range entry.Options {
+			suggestions = append(suggestions, domain.SearchSuggestion{
+				Text:   option.Text,
+				Source: domain.SuggestionSourceAutocomplete,
+				Score:  option.score(),
+			})
+		}
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,276 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	SuggestionStatsIndex      = "suggestion_stats"
+	SuggestionSelectionsIndex = "suggestion_selections"
+)
+
+// suggestionStatsFieldMappings are the fields of the suggestion statistics index
+var suggestionStatsFieldMappings = map[string]interface{}{
+	"text":             map[string]interface{}{"type": "keyword"},
+	"impressions":      map[string]interface{}{"type": "integer"},
+	"selections":       map[string]interface{}{"type": "integer"},
+	"position_sum":     map[string]interface{}{"type": "long"},
+	"last_shown_at":    map[string]interface{}{"type": "date"},
+	"last_selected_at": map[string]interface{}{"type": "date"},
+}
+
+// suggestionSelectionFieldMappings are the fields of the suggestion selections index
+var suggestionSelectionFieldMappings = map[string]interface{}{
+	"text":          map[string]interface{}{"type": "keyword"},
+	"source":        map[string]interface{}{"type": "keyword"},
+	"partial_query": map[string]interface{}{"type": "keyword"},
+	"position":      map[string]interface{}{"type": "integer"},
+	"time_taken_ms": map[string]interface{}{"type": "long"},
+	"selected_at":   map[string]interface{}{"type": "date"},
+}
+
+// SuggestionFeedbackRepository implements the outgoing.SuggestionFeedbackRepository interface using Elasticsearch
+type SuggestionFeedbackRepository struct {
+	client *Client
+}
+
+var _ outgoing.SuggestionFeedbackRepository = (*SuggestionFeedbackRepository)(nil)
+
+// NewSuggestionFeedbackRepository creates a new suggestion feedback repository
+func NewSuggestionFeedbackRepository(client *Client) *SuggestionFeedbackRepository {
+	return &SuggestionFeedbackRepository{
+		client: client,
+	}
+}
+
+// suggestionStat is the stored feedback of a normalized suggestion
+type suggestionStat struct {
+	Text           string    `json:"text"`
+	Impressions    int       `json:"impressions"`
+	Selections     int       `json:"selections"`
+	PositionSum    int64     `json:"position_sum"`
+	LastShownAt    time.Time `json:"last_shown_at"`
+	LastSelectedAt time.Time `json:"last_selected_at"`
+}
+
+// toDomain converts the stored feedback to domain suggestion statistics
+func (s suggestionStat) toDomain() domain.SuggestionStats {
+	stats := domain.SuggestionStats{
+		Text:           s.Text,
+		Impressions:    s.Impressions,
+		Selections:     s.Selections,
+		LastShownAt:    s.LastShownAt,
+		LastSelectedAt: s.LastSelectedAt,
+	}
+	if s.Selections > 0 {
+		stats.AvgPosition = float64(s.PositionSum) / float64(s.Selections)
+	}
+	return stats
+}
+
+// RecordImpressions increments the impression count of each shown suggestion in a single bulk request
+func (s *SuggestionFeedbackRepository) RecordImpressions(ctx context.Context, suggestions []domain.SearchSuggestion, shownAt time.Time) error {
+	if err := s.client.EnsureFieldMappings(ctx, SuggestionStatsIndex, suggestionStatsFieldMappings); err != nil {
+		return fmt.Errorf("error preparing suggestion statistics index: %w", err)
+	}
+
+	var body bytes.Buffer
+	seen := make(map[string]bool, len(suggestions))
+	for _, suggestion := range suggestions {
+		text := domain.NormalizeQuery(suggestion.Text)
+		if text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		body.Write(mustMarshalJSON(map[string]interface{}{
+			"update": map[string]interface{}{
+				"_id":               queryDocumentID(text),
+				"retry_on_conflict": 3,
+			},
+		}))
+		body.WriteByte('\n')
+		body.Write(mustMarshalJSON(map[string]interface{}{
+			"script": map[string]interface{}{
+				"source": "ctx._source.impressions += 1; ctx._source.last_shown_at = params.shown_at",
+				"params": map[string]interface{}{"shown_at": shownAt},
+			},
+			"upsert": suggestionStat{Text: text, Impressions: 1, LastShownAt: shownAt},
+		}))
+		body.WriteByte('\n')
+	}
+	if body.Len() == 0 {
+		return nil
+	}
+
+	res, err := s.client.PerformRequest(ctx, &esapi.BulkRequest{
+		Index: s.client.IndexNameWithPrefix(SuggestionStatsIndex),
+		Body:  &body,
+	})
+	if err != nil {
+		return fmt.Errorf("error recording suggestion impressions: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
+
+// RecordSelection stores a selection and adds it to the statistics of the suggestion
+func (s *SuggestionFeedbackRepository) RecordSelection(ctx context.Context, selection *domain.SuggestionSelection) error {
+	if selection == nil {
+		return errors.New("selection cannot be nil")
+	}
+	if err := s.client.EnsureFieldMappings(ctx, SuggestionSelectionsIndex, suggestionSelectionFieldMappings); err != nil {
+		return fmt.Errorf("error preparing suggestion selections index: %w", err)
+	}
+	if err := s.client.EnsureFieldMappings(ctx, SuggestionStatsIndex, suggestionStatsFieldMappings); err != nil {
+		return fmt.Errorf("error preparing suggestion statistics index: %w", err)
+	}
+
+	text := domain.NormalizeQuery(selection.Text)
+	event := map[string]interface{}{
+		"text":          text,
+		"source":        selection.Source,
+		"partial_query": selection.PartialQuery,
+		"position":      selection.Position,
+		"time_taken_ms": selection.TimeTakenMs,
+		"selected_at":   selection.SelectedAt,
+	}
+	res, err := s.client.PerformRequest(ctx, &esapi.IndexRequest{
+		Index: s.client.IndexNameWithPrefix(SuggestionSelectionsIndex),
+		Body:  bytes.NewReader(mustMarshalJSON(event)),
+	})
+	if err != nil {
+		return fmt.Errorf("error saving suggestion selection: %w", err)
+	}
+	closeBody(res.Body)
+
+	// A selection implies the suggestion was shown, which keeps the CTR at most 1
+	// even when the impression was not recorded
+	body := map[string]interface{}{
+		"script": map[string]interface{}{
+			"source": "ctx._source.selections += 1; " +
+				"if (ctx._source.impressions < ctx._source.selections) { ctx._source.impressions = ctx._source.selections } " +
+				"ctx._source.position_sum += params.position; ctx._source.last_selected_at = params.selected_at",
+			"params": map[string]interface{}{
+				"position":    selection.Position,
+				"selected_at": selection.SelectedAt,
+			},
+		},
+		"upsert": suggestionStat{
+			Text:           text,
+			Impressions:    1,
+			Selections:     1,
+			PositionSum:    int64(selection.Position),
+			LastShownAt:    selection.SelectedAt,
+			LastSelectedAt: selection.SelectedAt,
+		},
+	}
+	res, err = s.client.PerformRequest(ctx, &esapi.UpdateRequest{
+		Index:           s.client.IndexNameWithPrefix(SuggestionStatsIndex),
+		DocumentID:      queryDocumentID(text),
+		Body:            bytes.NewReader(mustMarshalJSON(body)),
+		RetryOnConflict: intPtr(3),
+	})
+	if err != nil {
+		return fmt.Errorf("error updating suggestion statistics: %w", err)
+	}
+	defer closeBody(res.Body)
+	return nil
+}
+
+// GetStats returns the statistics of the given suggestions, keyed by normalized text.
+// Suggestions without feedback are absent from the result.
+func (s *SuggestionFeedbackRepository) GetStats(ctx context.Context, texts []string) (map[string]domain.SuggestionStats, error) {
+	ids := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if text = domain.NormalizeQuery(text); text != "" {
+			ids = append(ids, queryDocumentID(text))
+		}
+	}
+	result := make(map[string]domain.SuggestionStats, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	if err := s.client.EnsureFieldMappings(ctx, SuggestionStatsIndex, suggestionStatsFieldMappings); err != nil {
+		return nil, fmt.Errorf("error preparing suggestion statistics index: %w", err)
+	}
+
+	res, err := s.client.PerformRequest(ctx, &esapi.MgetRequest{
+		Index: s.client.IndexNameWithPrefix(SuggestionStatsIndex),
+		Body:  bytes.NewReader(mustMarshalJSON(map[string]interface{}{"ids": ids})),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting suggestion statistics: %w", err)
+	}
+
+	var response struct {
+		Docs []struct {
+			Found  bool           `json:"found"`
+			Source suggestionStat `json:"_source"`
+		} `json:"docs"`
+	}
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing suggestion statistics response: %w", err)
+	}
+	for _, doc := range response.Docs {
+		if doc.Found {
+			result[doc.Source.Text] = doc.Source.toDomain()
+		}
+	}
+	return result, nil
+}
+
+// ListUnselected returns the suggestions shown at least minImpressions times that were never selected
+func (s *SuggestionFeedbackRepository) ListUnselected(ctx context.Context, minImpressions, limit int) ([]domain.SuggestionStats, error) {
+	if err := s.client.EnsureFieldMappings(ctx, SuggestionStatsIndex, suggestionStatsFieldMappings); err != nil {
+		return nil, fmt.Errorf("error preparing suggestion statistics index: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"size": limit,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"term": map[string]interface{}{"selections": 0}},
+					map[string]interface{}{"range": map[string]interface{}{"impressions": map[string]interface{}{"gte": minImpressions}}},
+				},
+			},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{"impressions": map[string]interface{}{"order": "desc"}},
+			map[string]interface{}{"text": map[string]interface{}{"order": "asc"}},
+		},
+	}
+	res, err := s.client.PerformRequest(ctx, &esapi.SearchRequest{
+		Index: []string{s.client.IndexNameWithPrefix(SuggestionStatsIndex)},
+		Body:  bytes.NewReader(mustMarshalJSON(body)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing unselected suggestions: %w", err)
+	}
+
+	var response struct {
+		Hits struct {
+			Hits []struct {
+				Source suggestionStat `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing unselected suggestions response: %w", err)
+	}
+
+	stats := make([]domain.SuggestionStats, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		stats = append(stats, hit.Source.toDomain())
+	}
+	return stats, nil
+}
@@ -0,0 +1,353 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// TextAnalyzer implements the outgoing.TextAnalyzer interface. All documents share one index, so
+// each logical index gets its own search analyzer on it, built from a synonyms set holding the
+// index's synonyms and stopwords. Synonyms sets are reloaded in place, so only defining the
+// analyzer of a new index reopens the documents index.
+type TextAnalyzer struct {
+	client *Client
+}
+
+var _ outgoing.TextAnalyzer = (*TextAnalyzer)(nil)
+
+// NewTextAnalyzer creates a new text analyzer
+func NewTextAnalyzer(client *Client) *TextAnalyzer {
+	return &TextAnalyzer{
+		client: client,
+	}
+}
+
+// searchAnalyzerPrefix prefixes the index ID in the name of its search analyzer
+const searchAnalyzerPrefix = "search_"
+
+// stopwordMarker is the token the synonyms sets map stopwords to. Stop filters are not
+// reloadable, so stopwords are kept in the updateable synonyms set and only the marker is
+// removed by the stop filter.
+const stopwordMarker = "stopwordmarker"
+
+// analyzerRegistry remembers which logical indexes have a search analyzer on the documents index
+type analyzerRegistry struct {
+	mu      sync.Mutex
+	loaded  bool
+	indexes map[string]bool
+}
+
+// searchAnalyzerName returns the name of the search analyzer of an index
+func searchAnalyzerName(indexID string) string {
+	return searchAnalyzerPrefix + indexID
+}
+
+// stopFilterName returns the name of the stopword filter of an index
+func stopFilterName(indexID string) string {
+	return "stop_" + indexID
+}
+
+// synonymFilterName returns the name of the synonym filter of an index
+func synonymFilterName(indexID string) string {
+	return "synonyms_" + indexID
+}
+
+// synonymsSetName returns the name of the synonyms set of an index
+func (c *Client) synonymsSetName(indexID string) string {
+	return c.IndexNameWithPrefix("synonyms-" + indexID)
+}
+
+// ApplyAnalysis stores the synonyms and stopwords of the index and reloads its search analyzer,
+// which is only defined when missing
+func (t *TextAnalyzer) ApplyAnalysis(ctx context.Context, index *domain.Index) error {
+	if index == nil {
+		return errors.New("index cannot be nil")
+	}
+	if err := t.client.EnsureFieldMappings(ctx, DocumentIndex, documentFieldMappings); err != nil {
+		return err
+	}
+	if err := t.putSynonymsSet(ctx, index); err != nil {
+		return err
+	}
+
+	settings, err := t.client.analysisSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if !analysisUpToDate(settings, index) {
+		if err := t.updateAnalysis(ctx, searchAnalysis(t.client, index)); err != nil {
+			return err
+		}
+	}
+
+	documentIndex := t.client.IndexNameWithPrefix(DocumentIndex)
+	res, err := t.client.PerformRequest(ctx, &esapi.IndicesReloadSearchAnalyzersRequest{
+		Index: []string{documentIndex},
+	})
+	if err != nil {
+		return fmt.Errorf("error reloading search analyzers of %s: %w", documentIndex, err)
+	}
+	closeBody(res.Body)
+
+	t.client.registerSearchAnalyzer(index.ID)
+	return nil
+}
+
+// Analyze runs the search analyzer of the index over the text. Indexes whose analysis was never
+// applied use the standard analyzer.
+func (t *TextAnalyzer) Analyze(ctx context.Context, index *domain.Index, text string) ([]domain.AnalyzedToken, error) {
+	if index == nil {
+		return nil, errors.New("index cannot be nil")
+	}
+
+	req := &esapi.IndicesAnalyzeRequest{}
+	body := map[string]interface{}{
+		"analyzer": "standard",
+		"text":     text,
+	}
+	if analyzer := t.client.searchAnalyzer(ctx, index.ID); analyzer != "" {
+		req.Index = t.client.IndexNameWithPrefix(DocumentIndex)
+		body["analyzer"] = analyzer
+	}
+	req.Body = bytes.NewReader(mustMarshalJSON(body))
+
+	res, err := t.client.PerformRequest(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("error analyzing text: %w", err)
+	}
+	var response struct {
+		Tokens []struct {
+			Token    string `json:"token"`
+			Type     string `json:"type"`
+			Position int    `json:"position"`
+		} `json:"tokens"`
+	}
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing analyze response: %w", err)
+	}
+
+	tokens := make([]domain.AnalyzedToken, 0, len(response.Tokens))
+	for _, token := range response.Tokens {
+		tokenType := domain.TokenTypeWord
+		if token.Type == "SYNONYM" {
+			tokenType = domain.TokenTypeSynonym
+		}
+		tokens = append(tokens, domain.AnalyzedToken{
+			Token:    token.Token,
+			Position: token.Position,
+			Type:     tokenType,
+		})
+	}
+	return tokens, nil
+}
+
+// putSynonymsSet replaces the synonyms set of the index with its synonym rules and rules mapping
+// its stopwords to the stopword marker. Search analyzers using the set are reloaded by Elasticsearch.
+func (t *TextAnalyzer) putSynonymsSet(ctx context.Context, index *domain.Index) error {
+	rules := make([]interface{}, 0, len(index.Settings.Synonyms)+len(index.Settings.Stopwords))
+	for i, rule := range index.Settings.Synonyms {
+		rules = append(rules, map[string]interface{}{
+			"id":       fmt.Sprintf("rule-%d", i+1),
+			"synonyms": rule.String(),
+		})
+	}
+	for i, stopword := range index.Settings.Stopwords {
+		rules = append(rules, map[string]interface{}{
+			"id":       fmt.Sprintf("stopword-%d", i+1),
+			"synonyms": domain.SynonymRule{Terms: []string{stopword}, Replacements: []string{stopwordMarker}}.String(),
+		})
+	}
+	setName := t.client.synonymsSetName(index.ID)
+	res, err := t.client.PerformRequest(ctx, &esapi.SynonymsPutSynonymRequest{
+		DocumentID: setName,
+		Body:       bytes.NewReader(mustMarshalJSON(map[string]interface{}{"synonyms_set": rules})),
+	})
+	if err != nil {
+		return fmt.Errorf("error storing synonyms set %s: %w", setName, err)
+	}
+	closeBody(res.Body)
+	return nil
+}
+
+// updateAnalysis adds analysis settings to the documents index. Analyzers can only be
+// defined on a closed index, so the index is closed for the update and always reopened.
+// Searches fail while it is closed, hence analyzers are only defined once per index.
+func (t *TextAnalyzer) updateAnalysis(ctx context.Context, analysis map[string]interface{}) (err error) {
+	documentIndex := t.client.IndexNameWithPrefix(DocumentIndex)
+	res, err := t.client.PerformRequest(ctx, &esapi.IndicesCloseRequest{Index: []string{documentIndex}})
+	if err != nil {
+		return fmt.Errorf("error closing index %s: %w", documentIndex, err)
+	}
+	closeBody(res.Body)
+
+	defer func() {
+		res, openErr := t.client.PerformRequest(ctx, &esapi.IndicesOpenRequest{Index: []string{documentIndex}})
+		if openErr != nil {
+			if err == nil {
+				err = fmt.Errorf("error reopening index %s: %w", documentIndex, openErr)
+			}
+			return
+		}
+		closeBody(res.Body)
+	}()
+
+	res, err = t.client.PerformRequest(ctx, &esapi.IndicesPutSettingsRequest{
+		Index: []string{documentIndex},
+		Body:  bytes.NewReader(mustMarshalJSON(map[string]interface{}{"analysis": analysis})),
+	})
+	if err != nil {
+		return fmt.Errorf("error updating analysis settings of %s: %w", documentIndex, err)
+	}
+	closeBody(res.Body)
+	return nil
+}
+
+// searchAnalysis creates the analysis settings defining the search analyzer of an index: the
+// updateable synonym filter expands synonyms and marks stopwords, which the stop filter removes
+func searchAnalysis(client *Client, index *domain.Index) map[string]interface{} {
+	return map[string]interface{}{
+		"filter": map[string]interface{}{
+			synonymFilterName(index.ID): map[string]interface{}{
+				"type":         "synonym_graph",
+				"synonyms_set": client.synonymsSetName(index.ID),
+				"updateable":   true,
+			},
+			stopFilterName(index.ID): map[string]interface{}{
+				"type":      "stop",
+				"stopwords": []string{stopwordMarker},
+			},
+		},
+		"analyzer": map[string]interface{}{
+			searchAnalyzerName(index.ID): map[string]interface{}{
+				"type":      "custom",
+				"tokenizer": "standard",
+				"filter":    []string{"lowercase", synonymFilterName(index.ID), stopFilterName(index.ID)},
+			},
+		},
+	}
+}
+
+// analysisUpToDate reports whether the analysis settings define the search analyzer of the
+// index, with the stop filter removing the stopword marker. Analyzers defined with a list of
+// stopwords are redefined once.
+func analysisUpToDate(analysis map[string]interface{}, index *domain.Index) bool {
+	analyzers, _ := analysis["analyzer"].(map[string]interface{})
+	if _, ok := analyzers[searchAnalyzerName(index.ID)]; !ok {
+		return false
+	}
+	filters, _ := analysis["filter"].(map[string]interface{})
+	filter, _ := filters[stopFilterName(index.ID)].(map[string]interface{})
+	return fmt.Sprint(filter["stopwords"]) == fmt.Sprint([]interface{}{stopwordMarker})
+}
+
+// analysisSettings returns the analysis settings of the documents index, or nil when the
+// index does not exist or has none
+func (c *Client) analysisSettings(ctx context.Context) (map[string]interface{}, error) {
+	documentIndex := c.IndexNameWithPrefix(DocumentIndex)
+	res, err := (&esapi.IndicesGetSettingsRequest{Index: []string{documentIndex}}).Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("error getting settings of %s: %w", documentIndex, err)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res.Body)
+		return nil, nil
+	}
+	if res.IsError() {
+		defer closeBody(res.Body)
+		return nil, fmt.Errorf("error getting settings of %s: %s", documentIndex, res.String())
+	}
+
+	var response map[string]struct {
+		Settings struct {
+			Index struct {
+				Analysis map[string]interface{} `json:"analysis"`
+			} `json:"index"`
+		} `json:"settings"`
+	}
+	if err := parseResponse(res.Body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing settings of %s: %w", documentIndex, err)
+	}
+	return response[documentIndex].Settings.Index.Analysis, nil
+}
+
+// searchAnalyzer returns the search analyzer of an index, or an empty string when it has none.
+// The analyzers defined on the documents index are loaded on first use.
+func (c *Client) searchAnalyzer(ctx context.Context, indexID string) string {
+	if indexID == "" {
+		return ""
+	}
+	c.analyzers.mu.Lock()
+	defer c.analyzers.mu.Unlock()
+	if !c.analyzers.loaded {
+		analysis, err := c.analysisSettings(ctx)
+		if err != nil {
+			return ""
+		}
+		c.analyzers.indexes = make(map[string]bool)
+		analyzers, _ := analysis["analyzer"].(map[string]interface{})
+		for name := range analyzers {
+			if id, ok := strings.CutPrefix(name, searchAnalyzerPrefix); ok {
+				c.analyzers.indexes[id] = true
+			}
+		}
+		c.analyzers.loaded = true
+	}
+	if !c.analyzers.indexes[indexID] {
+		return ""
+	}
+	return searchAnalyzerName(indexID)
+}
+
+// registerSearchAnalyzer records that an index has a search analyzer
+func (c *Client) registerSearchAnalyzer(indexID string) {
+	c.analyzers.mu.Lock()
+	defer c.analyzers.mu.Unlock()
+	if c.analyzers.indexes == nil {
+		c.analyzers.indexes = make(map[string]bool)
+	}
+	c.analyzers.indexes[indexID] = true
+}
+
+// applySearchAnalyzer sets the search analyzer on the full-text clauses of a query
+func applySearchAnalyzer(clause interface{}, analyzer string) {
+	switch c := clause.(type) {
+	case []interface{}:
+		for _, item := range c {
+			applySearchAnalyzer(item, analyzer)
+		}
+	case map[string]interface{}:
+		for key, value := range c {
+			switch key {
+			case "multi_match":
+				if params, ok := value.(map[string]interface{}); ok {
+					params["analyzer"] = analyzer
+				}
+			case "match", "match_phrase":
+				fields, ok := value.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for field, params := range fields {
+					switch p := params.(type) {
+					case map[string]interface{}:
+						p["analyzer"] = analyzer
+					case string:
+						fields[field] = map[string]interface{}{"query": p, "analyzer": analyzer}
+					}
+				}
+			default:
+				applySearchAnalyzer(value, analyzer)
+			}
+		}
+	}
+}
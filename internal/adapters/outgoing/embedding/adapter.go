@@ -0,0 +1,35 @@
+package embedding
+
+import (
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/di"
+)
+
+// EmbeddingAdapterFactory implements the di.AdapterRegistrar interface
+type EmbeddingAdapterFactory struct{}
+
+var _ di.AdapterRegistrar = (*EmbeddingAdapterFactory)(nil)
+
+// NewEmbeddingAdapterFactory creates a new factory for embedding adapters
+func NewEmbeddingAdapterFactory() *EmbeddingAdapterFactory {
+	return &EmbeddingAdapterFactory{}
+}
+
+// Register implements the AdapterRegistrar interface
+func (e *EmbeddingAdapterFactory) Register(container *di.Container) error {
+	return RegisterEmbeddingAdapters(container)
+}
+
+// RegisterEmbeddingAdapters registers all embedding implementations with the DI container
+func RegisterEmbeddingAdapters(container *di.Container) error {
+	// Register embedder implementation
+	container.Register("embedder", func() (interface{}, error) {
+		return NewHashingEmbedder(), nil
+	})
+	return nil
+}
+
+// GetEmbedder retrieves the embedder from the container
+func GetEmbedder(container *di.Container) outgoing.Embedder {
+	return container.MustResolve("embedder").(outgoing.Embedder)
+}
@@ -0,0 +1,62 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// DocumentRepository decorates a document repository so that every saved or updated
+// document carries the embedding of its text
+type DocumentRepository struct {
+	outgoing.DocumentRepository
+	embedder outgoing.Embedder
+}
+
+var _ outgoing.DocumentRepository = (*DocumentRepository)(nil)
+
+// NewDocumentRepository wraps a document repository with an embedder
+func NewDocumentRepository(repository outgoing.DocumentRepository, embedder outgoing.Embedder) *DocumentRepository {
+	return &DocumentRepository{
+		DocumentRepository: repository,
+		embedder:           embedder,
+	}
+}
+
+// Save embeds the document and saves it
+func (r *DocumentRepository) Save(ctx context.Context, document *domain.Document) error {
+	if err := r.embed(ctx, document); err != nil {
+		return err
+	}
+	return r.DocumentRepository.Save(ctx, document)
+}
+
+// Update re-embeds the document and updates it
+func (r *DocumentRepository) Update(ctx context.Context, document *domain.Document) error {
+	if err := r.embed(ctx, document); err != nil {
+		return err
+	}
+	return r.DocumentRepository.Update(ctx, document)
+}
+
+// embed sets the embedding of a document from its title, description, keywords and content
+func (r *DocumentRepository) embed(ctx context.Context, document *domain.Document) error {
+	if document == nil {
+		return nil
+	}
+	text := strings.Join([]string{
+		document.Title,
+		document.MetaDesc,
+		strings.Join(document.MetaKeywords, " "),
+		document.Content,
+	}, " ")
+	vector, err := r.embedder.Embed(ctx, text)
+	if err != nil {
+		return fmt.Errorf("failed to embed document: %w", err)
+	}
+	document.Embedding = vector
+	return nil
+}
@@ -0,0 +1,108 @@
+package embedding
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/textutil"
+)
+
+const (
+	// charGramSize is the length of the character n-grams hashed for each token
+	charGramSize = 3
+	// wordWeight, bigramWeight and charGramWeight weight the hashed features
+	wordWeight     = 1.0
+	bigramWeight   = 0.75
+	charGramWeight = 0.5
+)
+
+// HashingEmbedder computes embeddings locally by hashing words, word bigrams and
+// character trigrams into a fixed number of signed buckets. Texts sharing vocabulary
+// or word fragments get similar vectors without any model or network access.
+type HashingEmbedder struct {
+	dimensions int
+}
+
+// HashingEmbedderOption is a function that configures a HashingEmbedder
+type HashingEmbedderOption func(*HashingEmbedder)
+
+// NewHashingEmbedder creates a new hashing embedder
+func NewHashingEmbedder(options ...HashingEmbedderOption) *HashingEmbedder {
+	embedder := &HashingEmbedder{
+		dimensions: domain.EmbeddingDimensions,
+	}
+	for _, option := range options {
+		option(embedder)
+	}
+	return embedder
+}
+
+// WithDimensions sets the number of dimensions of the embeddings
+func WithDimensions(dimensions int) HashingEmbedderOption {
+	return func(e *HashingEmbedder) {
+		e.dimensions = dimensions
+	}
+}
+
+var _ outgoing.Embedder = (*HashingEmbedder)(nil)
+
+// Dimensions returns the number of dimensions of the embeddings
+func (e *HashingEmbedder) Dimensions() int {
+	return e.dimensions
+}
+
+// Embed returns the L2-normalized embedding of the text, or nil when the text has no tokens
+func (e *HashingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	tokens := textutil.Tokenize(text)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	vector := make([]float64, e.dimensions)
+	for i, token := range tokens {
+		e.add(vector, "w:"+token, wordWeight)
+		if i > 0 {
+			e.add(vector, "b:"+tokens[i-1]+" "+token, bigramWeight)
+		}
+		padded := []rune("#" + token + "#")
+		for j := 0; j+charGramSize <= len(padded); j++ {
+			e.add(vector, "c:"+string(padded[j:j+charGramSize]), charGramWeight)
+		}
+	}
+
+	// Dampen repeated features so that long documents are not dominated by their most frequent terms
+	var norm float64
+	for i, value := range vector {
+		vector[i] = math.Copysign(math.Log1p(math.Abs(value)), value)
+		norm += vector[i] * vector[i]
+	}
+	if norm == 0 {
+		return nil, nil
+	}
+
+	norm = math.Sqrt(norm)
+	embedding := make([]float32, e.dimensions)
+	for i, value := range vector {
+		embedding[i] = float32(value / norm)
+	}
+	return embedding, nil
+}
+
+// add hashes a feature into a bucket of the vector, with a hash-derived sign to reduce collision bias
+func (e *HashingEmbedder) add(vector []float64, feature string, weight float64) {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(feature))
+	sum := hash.Sum64()
+	bucket := int(sum % uint64(e.dimensions))
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vector[bucket] += weight
+}
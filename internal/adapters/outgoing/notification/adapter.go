@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/di"
+	"github.com/mohamedshehata15/intelli-index/pkg/config"
+)
+
+// NotificationAdapterFactory implements the di.AdapterRegistrar interface
+type NotificationAdapterFactory struct {
+	config *config.NotificationConfig
+}
+
+var _ di.AdapterRegistrar = (*NotificationAdapterFactory)(nil)
+
+// NewNotificationAdapterFactory creates a new factory for the alert notifiers and match subscribers
+func NewNotificationAdapterFactory(cfg *config.NotificationConfig) *NotificationAdapterFactory {
+	return &NotificationAdapterFactory{
+		config: cfg,
+	}
+}
+
+// Register implements the AdapterRegistrar interface
+func (n *NotificationAdapterFactory) Register(container *di.Container) error {
+	return RegisterNotificationAdapters(container, n.config)
+}
+
+// RegisterNotificationAdapters registers the alert notifiers and match subscribers with the DI
+// container. Webhook alerts are always available; email and file alerts and the match
+// subscribers only when configured.
+func RegisterNotificationAdapters(container *di.Container, cfg *config.NotificationConfig) error {
+	container.Register("alertNotifiers", func() (interface{}, error) {
+		notifiers := []outgoing.AlertNotifier{NewWebhookNotifier(cfg.WebhookTimeout)}
+		if cfg.SMTPHost != "" {
+			notifiers = append(notifiers, NewSMTPNotifier(cfg))
+		}
+		if cfg.AlertFilePath != "" {
+			notifiers = append(notifiers, NewFileNotifier(cfg.AlertFilePath))
+		}
+		return notifiers, nil
+	})
+	container.Register("matchSubscribers", func() (interface{}, error) {
+		subscribers := make([]outgoing.MatchSubscriber, 0)
+		if cfg.MatchWebhookURL != "" {
+			subscribers = append(subscribers, NewWebhookMatchSubscriber(cfg.MatchWebhookURL, cfg.WebhookTimeout))
+		}
+		if cfg.MatchFilePath != "" {
+			subscribers = append(subscribers, NewFileMatchSubscriber(cfg.MatchFilePath))
+		}
+		return subscribers, nil
+	})
+	return nil
+}
+
+// GetAlertNotifiers retrieves the configured alert notifiers from the container
+func GetAlertNotifiers(container *di.Container) []outgoing.AlertNotifier {
+	return container.MustResolve("alertNotifiers").([]outgoing.AlertNotifier)
+}
+
+// GetMatchSubscribers retrieves the configured match subscribers from the container
+func GetMatchSubscribers(container *di.Container) []outgoing.MatchSubscriber {
+	return container.MustResolve("matchSubscribers").([]outgoing.MatchSubscriber)
+}
@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// alertPayload is the JSON form of an alert posted to webhooks and written to files
+type alertPayload struct {
+	SavedSearchID string       `json:"saved_search_id"`
+	UserID        string       `json:"user_id"`
+	Name          string       `json:"name"`
+	Query         string       `json:"query"`
+	Since         time.Time    `json:"since"`
+	Until         time.Time    `json:"until"`
+	TotalHits     int          `json:"total_hits"`
+	Documents     []alertMatch `json:"documents"`
+}
+
+// alertMatch is a document newly matching a saved search
+type alertMatch struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Title       string    `json:"title"`
+	Score       float64   `json:"score"`
+	LastCrawled time.Time `json:"last_crawled"`
+}
+
+// newAlertPayload converts an alert to its JSON form
+func newAlertPayload(alert *domain.SavedSearchAlert) alertPayload {
+	payload := alertPayload{
+		SavedSearchID: alert.SavedSearch.ID,
+		UserID:        alert.SavedSearch.UserID,
+		Name:          alert.SavedSearch.Name,
+		Query:         alert.SavedSearch.Query.Query,
+		Since:         alert.Since,
+		Until:         alert.Until,
+		TotalHits:     alert.TotalHits,
+		Documents:     make([]alertMatch, 0, len(alert.Documents)),
+	}
+	for _, doc := range alert.Documents {
+		payload.Documents = append(payload.Documents, alertMatch{
+			ID:          doc.ID,
+			URL:         doc.URL,
+			Title:       doc.Title,
+			Score:       doc.Score,
+			LastCrawled: doc.LastCrawled,
+		})
+	}
+	return payload
+}
@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// FileNotifier implements the outgoing.AlertNotifier interface by appending alerts as JSON lines
+// to a local file. It is meant for tests and local runs.
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ outgoing.AlertNotifier = (*FileNotifier)(nil)
+
+// NewFileNotifier creates a file notifier writing to the path
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{
+		path: path,
+	}
+}
+
+// Channel returns the file channel type
+func (f *FileNotifier) Channel() domain.AlertChannelType {
+	return domain.FileChannel
+}
+
+// Notify appends the alert to the file as a JSON line
+func (f *FileNotifier) Notify(_ context.Context, alert *domain.SavedSearchAlert, _ domain.AlertChannel) error {
+	line, err := json.Marshal(newAlertPayload(alert))
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return appendLine(f.path, line)
+}
+
+// appendLine appends a line to a file, creating the file when it does not exist
+func appendLine(path string, line []byte) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return file.Close()
+}
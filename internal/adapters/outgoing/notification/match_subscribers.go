@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// matchPayload is the JSON form of a standing query match posted to webhooks and written to files
+type matchPayload struct {
+	StandingQueryID string     `json:"standing_query_id"`
+	Name            string     `json:"name"`
+	Query           string     `json:"query"`
+	MatchedAt       time.Time  `json:"matched_at"`
+	Document        alertMatch `json:"document"`
+}
+
+// newMatchPayload converts a match to its JSON form
+func newMatchPayload(match *domain.PercolatorMatch) matchPayload {
+	return matchPayload{
+		StandingQueryID: match.Query.ID,
+		Name:            match.Query.Name,
+		Query:           match.Query.Query.Query,
+		MatchedAt:       match.MatchedAt,
+		Document: alertMatch{
+			ID:          match.Document.ID,
+			URL:         match.Document.URL,
+			Title:       match.Document.Title,
+			Score:       match.Document.Score,
+			LastCrawled: match.Document.LastCrawled,
+		},
+	}
+}
+
+// WebhookMatchSubscriber implements the outgoing.MatchSubscriber interface by posting every match
+// as JSON to a webhook
+type WebhookMatchSubscriber struct {
+	url    string
+	client *http.Client
+}
+
+var _ outgoing.MatchSubscriber = (*WebhookMatchSubscriber)(nil)
+
+// NewWebhookMatchSubscriber creates a subscriber posting to the URL, with requests timing out
+// after the timeout
+func NewWebhookMatchSubscriber(url string, timeout time.Duration) *WebhookMatchSubscriber {
+	return &WebhookMatchSubscriber{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// OnMatch posts the match to the webhook, failing unless it answers with a 2xx status
+func (w *WebhookMatchSubscriber) OnMatch(ctx context.Context, match *domain.PercolatorMatch) error {
+	body, err := json.Marshal(newMatchPayload(match))
+	if err != nil {
+		return fmt.Errorf("failed to encode match: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// FileMatchSubscriber implements the outgoing.MatchSubscriber interface by appending matches as
+// JSON lines to a local file
+type FileMatchSubscriber struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ outgoing.MatchSubscriber = (*FileMatchSubscriber)(nil)
+
+// NewFileMatchSubscriber creates a subscriber writing to the path
+func NewFileMatchSubscriber(path string) *FileMatchSubscriber {
+	return &FileMatchSubscriber{
+		path: path,
+	}
+}
+
+// OnMatch appends the match to the file as a JSON line
+func (f *FileMatchSubscriber) OnMatch(_ context.Context, match *domain.PercolatorMatch) error {
+	line, err := json.Marshal(newMatchPayload(match))
+	if err != nil {
+		return fmt.Errorf("failed to encode match: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return appendLine(f.path, line)
+}
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/pkg/config"
+)
+
+// SMTPNotifier implements the outgoing.AlertNotifier interface by emailing alerts to the address
+// of their channel through an SMTP server
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+var _ outgoing.AlertNotifier = (*SMTPNotifier)(nil)
+
+// NewSMTPNotifier creates an SMTP notifier for the server of the configuration, authenticating
+// when a username is set
+func NewSMTPNotifier(cfg *config.NotificationConfig) *SMTPNotifier {
+	notifier := &SMTPNotifier{
+		addr: net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		from: cfg.SMTPFrom,
+	}
+	if cfg.SMTPUsername != "" {
+		notifier.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return notifier
+}
+
+// Channel returns the email channel type
+func (s *SMTPNotifier) Channel() domain.AlertChannelType {
+	return domain.EmailChannel
+}
+
+// Notify emails the alert as plain text listing the new matches
+func (s *SMTPNotifier) Notify(_ context.Context, alert *domain.SavedSearchAlert, channel domain.AlertChannel) error {
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{channel.Target}, s.message(alert, channel.Target)); err != nil {
+		return fmt.Errorf("failed to send alert email: %w", err)
+	}
+	return nil
+}
+
+// message builds the email of an alert
+func (s *SMTPNotifier) message(alert *domain.SavedSearchAlert, to string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %d new results for %s\r\n", alert.TotalHits, headerText(alert.SavedSearch.Name))
+	fmt.Fprintf(&b, "Date: %s\r\n", alert.Until.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+
+	fmt.Fprintf(&b, "%d documents crawled since %s match your saved search %q (%s).\r\n\r\n",
+		alert.TotalHits, alert.Since.Format(time.RFC1123), alert.SavedSearch.Name, alert.SavedSearch.Query.Query)
+	for i, doc := range alert.Documents {
+		fmt.Fprintf(&b, "%d. %s\r\n   %s\r\n", i+1, doc.Title, doc.URL)
+	}
+	if more := alert.TotalHits - len(alert.Documents); more > 0 {
+		fmt.Fprintf(&b, "\r\nand %d more.\r\n", more)
+	}
+	return []byte(b.String())
+}
+
+// headerText removes line breaks from text placed in an email header
+func headerText(text string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(text)
+}
@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// WebhookNotifier implements the outgoing.AlertNotifier interface by posting alerts as JSON to
+// the URL of their channel
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+var _ outgoing.AlertNotifier = (*WebhookNotifier)(nil)
+
+// NewWebhookNotifier creates a webhook notifier whose requests time out after the timeout
+func NewWebhookNotifier(timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Channel returns the webhook channel type
+func (w *WebhookNotifier) Channel() domain.AlertChannelType {
+	return domain.WebhookChannel
+}
+
+// Notify posts the alert to the webhook, failing unless it answers with a 2xx status
+func (w *WebhookNotifier) Notify(ctx context.Context, alert *domain.SavedSearchAlert, channel domain.AlertChannel) error {
+	body, err := json.Marshal(newAlertPayload(alert))
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
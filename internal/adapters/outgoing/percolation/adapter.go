@@ -0,0 +1,43 @@
+package percolation
+
+import (
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/di"
+)
+
+// PercolationAdapterFactory implements the di.AdapterRegistrar interface
+type PercolationAdapterFactory struct {
+	documents  func(*di.Container) outgoing.DocumentRepository
+	percolator func(*di.Container) incoming.PercolatorService
+}
+
+var _ di.AdapterRegistrar = (*PercolationAdapterFactory)(nil)
+
+// NewPercolationAdapterFactory creates a new factory decorating the document repository of a
+// backend with the percolator service, both resolved from the container
+func NewPercolationAdapterFactory(documents func(*di.Container) outgoing.DocumentRepository, percolator func(*di.Container) incoming.PercolatorService) *PercolationAdapterFactory {
+	return &PercolationAdapterFactory{
+		documents:  documents,
+		percolator: percolator,
+	}
+}
+
+// Register implements the AdapterRegistrar interface
+func (p *PercolationAdapterFactory) Register(container *di.Container) error {
+	return RegisterPercolationAdapters(container, p.documents, p.percolator)
+}
+
+// RegisterPercolationAdapters registers the percolating document repository with the DI
+// container. Documents are written through it so that standing queries see every document.
+func RegisterPercolationAdapters(container *di.Container, documents func(*di.Container) outgoing.DocumentRepository, percolator func(*di.Container) incoming.PercolatorService) error {
+	container.Register("percolatingDocumentRepository", func() (interface{}, error) {
+		return NewDocumentRepository(documents(container), percolator(container)), nil
+	})
+	return nil
+}
+
+// GetDocumentRepository retrieves the percolating document repository from the container
+func GetDocumentRepository(container *di.Container) *DocumentRepository {
+	return container.MustResolve("percolatingDocumentRepository").(*DocumentRepository)
+}
@@ -0,0 +1,96 @@
+package percolation
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// queueSize is the number of written documents waiting to be percolated before writers
+// percolate their documents themselves
+const queueSize = 256
+
+// DocumentRepository decorates a document repository so that every saved or updated document is
+// matched against the standing queries of a percolator service. Documents are percolated in the
+// background once written; failures are logged and never fail the write.
+type DocumentRepository struct {
+	outgoing.DocumentRepository
+	percolator incoming.PercolatorService
+	queue      chan *domain.Document
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+var _ outgoing.DocumentRepository = (*DocumentRepository)(nil)
+
+// NewDocumentRepository wraps a document repository with the percolator service matching the
+// documents it writes and starts percolating them. The repository must be closed to percolate
+// the pending documents.
+func NewDocumentRepository(repository outgoing.DocumentRepository, percolator incoming.PercolatorService) *DocumentRepository {
+	r := &DocumentRepository{
+		DocumentRepository: repository,
+		percolator:         percolator,
+		queue:              make(chan *domain.Document, queueSize),
+		done:               make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Save saves the document and queues it for percolation
+func (r *DocumentRepository) Save(ctx context.Context, document *domain.Document) error {
+	if err := r.DocumentRepository.Save(ctx, document); err != nil {
+		return err
+	}
+	r.enqueue(ctx, document)
+	return nil
+}
+
+// Update updates the document and queues it for percolation
+func (r *DocumentRepository) Update(ctx context.Context, document *domain.Document) error {
+	if err := r.DocumentRepository.Update(ctx, document); err != nil {
+		return err
+	}
+	r.enqueue(ctx, document)
+	return nil
+}
+
+// Close percolates the queued documents and stops the background percolation. The repository
+// must not be written to once closed.
+func (r *DocumentRepository) Close() {
+	r.closeOnce.Do(func() {
+		close(r.queue)
+		<-r.done
+	})
+}
+
+// enqueue hands a copy of the written document to the background percolation. When the queue
+// is full the document is percolated by the writer, slowing writes down rather than losing matches.
+func (r *DocumentRepository) enqueue(ctx context.Context, document *domain.Document) {
+	written := *document
+	select {
+	case r.queue <- &written:
+	default:
+		r.percolate(context.WithoutCancel(ctx), &written)
+	}
+}
+
+// run percolates the queued documents until the queue is closed
+func (r *DocumentRepository) run() {
+	defer close(r.done)
+	for document := range r.queue {
+		r.percolate(context.Background(), document)
+	}
+}
+
+// percolate matches a document and logs the failures to match it or to publish its matches
+func (r *DocumentRepository) percolate(ctx context.Context, document *domain.Document) {
+	matches, err := r.percolator.PercolateDocument(ctx, document)
+	if err != nil {
+		log.Printf("Failed to percolate document %s (%d matches): %v", document.ID, len(matches), err)
+	}
+}
@@ -58,6 +58,66 @@ func RegisterStorageAdapters(container *di.Container, cfg *config.DBConfig) erro
 		return adapter.IndexRepository(), nil
 	})
 
+	// Register query history repository implementation
+	container.Register("queryHistoryRepositoryDB", func() (interface{}, error) {
+		adapter := GetSQLAdapter(container)
+		return adapter.QueryHistoryRepository(), nil
+	})
+
+	// Register suggestion completer implementation
+	container.Register("suggestionCompleterDB", func() (interface{}, error) {
+		adapter := GetSQLAdapter(container)
+		return adapter.SuggestionCompleter(), nil
+	})
+
+	// Register suggestion feedback repository implementation
+	container.Register("suggestionFeedbackRepositoryDB", func() (interface{}, error) {
+		adapter := GetSQLAdapter(container)
+		return adapter.SuggestionFeedbackRepository(), nil
+	})
+
+	// Register spelling corrector implementation
+	container.Register("spellingCorrectorDB", func() (interface{}, error) {
+		adapter := GetSQLAdapter(container)
+		return adapter.SpellingCorrector(), nil
+	})
+
+	// Register text analyzer implementation
+	container.Register("textAnalyzerDB", func() (interface{}, error) {
+		adapter := GetSQLAdapter(container)
+		return adapter.TextAnalyzer(), nil
+	})
+
+	// Register query log repository implementation
+	container.Register("queryLogRepositoryDB", func() (interface{}, error) {
+		adapter := GetSQLAdapter(container)
+		return adapter.QueryLogRepository(), nil
+	})
+
+	// Register link graph repository implementation
+	container.Register("linkGraphRepositoryDB", func() (interface{}, error) {
+		adapter := GetSQLAdapter(container)
+		return adapter.LinkGraphRepository(), nil
+	})
+
+	// Register saved search repository implementation
+	container.Register("savedSearchRepositoryDB", func() (interface{}, error) {
+		adapter := GetSQLAdapter(container)
+		return adapter.SavedSearchRepository(), nil
+	})
+
+	// Register percolator implementation
+	container.Register("percolatorDB", func() (interface{}, error) {
+		adapter := GetSQLAdapter(container)
+		return adapter.Percolator(), nil
+	})
+
+	// Register query rule repository implementation
+	container.Register("queryRuleRepositoryDB", func() (interface{}, error) {
+		adapter := GetSQLAdapter(container)
+		return adapter.QueryRuleRepository(), nil
+	})
+
 	// Register migration handler
 	container.Register("migrationHandler", func() (interface{}, error) {
 		adapter := GetSQLAdapter(container)
@@ -82,7 +142,57 @@ func GetIndexRepository(container *di.Container) *IndexRepository {
 	return container.MustResolve("indexRepositoryDB").(*IndexRepository)
 }
 
+// GetQueryHistoryRepository retrieves the query history repository from the container
+func GetQueryHistoryRepository(container *di.Container) *QueryHistoryRepository {
+	return container.MustResolve("queryHistoryRepositoryDB").(*QueryHistoryRepository)
+}
+
+// GetSuggestionCompleter retrieves the suggestion completer from the container
+func GetSuggestionCompleter(container *di.Container) *SuggestionCompleter {
+	return container.MustResolve("suggestionCompleterDB").(*SuggestionCompleter)
+}
+
+// GetSuggestionFeedbackRepository retrieves the suggestion feedback repository from the container
+func GetSuggestionFeedbackRepository(container *di.Container) *SuggestionFeedbackRepository {
+	return container.MustResolve("suggestionFeedbackRepositoryDB").(*SuggestionFeedbackRepository)
+}
+
+// GetSpellingCorrector retrieves the spelling corrector from the container
+func GetSpellingCorrector(container *di.Container) *SpellingCorrector {
+	return container.MustResolve("spellingCorrectorDB").(*SpellingCorrector)
+}
+
+// GetTextAnalyzer retrieves the text analyzer from the container
+func GetTextAnalyzer(container *di.Container) *TextAnalyzer {
+	return container.MustResolve("textAnalyzerDB").(*TextAnalyzer)
+}
+
+// GetQueryLogRepository retrieves the query log repository from the container
+func GetQueryLogRepository(container *di.Container) *QueryLogRepository {
+	return container.MustResolve("queryLogRepositoryDB").(*QueryLogRepository)
+}
+
 // GetMigrationHandler retrieves the migration handler from the container
 func GetMigrationHandler(container *di.Container) *MigrationHandler {
 	return container.MustResolve("migrationHandler").(*MigrationHandler)
 }
+
+// GetLinkGraphRepository retrieves the link graph repository from the container
+func GetLinkGraphRepository(container *di.Container) *LinkGraphRepository {
+	return container.MustResolve("linkGraphRepositoryDB").(*LinkGraphRepository)
+}
+
+// GetSavedSearchRepository retrieves the saved search repository from the container
+func GetSavedSearchRepository(container *di.Container) *SavedSearchRepository {
+	return container.MustResolve("savedSearchRepositoryDB").(*SavedSearchRepository)
+}
+
+// GetPercolator retrieves the percolator from the container
+func GetPercolator(container *di.Container) *Percolator {
+	return container.MustResolve("percolatorDB").(*Percolator)
+}
+
+// GetQueryRuleRepository retrieves the query rule repository from the container
+func GetQueryRuleRepository(container *di.Container) *QueryRuleRepository {
+	return container.MustResolve("queryRuleRepositoryDB").(*QueryRuleRepository)
+}
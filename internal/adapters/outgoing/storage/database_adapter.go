@@ -7,10 +7,20 @@ import (
 
 // SQLAdapter is a unified adapter for SQL database operations
 type SQLAdapter struct {
-	client           *Client
-	documentRepo     *DocumentRepository
-	indexRepo        *IndexRepository
-	migrationHandler *MigrationHandler
+	client              *Client
+	documentRepo        *DocumentRepository
+	indexRepo           *IndexRepository
+	queryHistoryRepo    *QueryHistoryRepository
+	suggestionCompleter *SuggestionCompleter
+	feedbackRepo        *SuggestionFeedbackRepository
+	spellingCorrector   *SpellingCorrector
+	textAnalyzer        *TextAnalyzer
+	queryLogRepo        *QueryLogRepository
+	linkGraphRepo       *LinkGraphRepository
+	savedSearchRepo     *SavedSearchRepository
+	percolator          *Percolator
+	queryRuleRepo       *QueryRuleRepository
+	migrationHandler    *MigrationHandler
 }
 
 // NewSQLAdapter creates a new SQL Adapter
@@ -25,10 +35,20 @@ func NewSQLAdapter(cfg *config.DBConfig) (*SQLAdapter, error) {
 	migrationHandler := NewMigrationHandler(client)
 
 	adapter := &SQLAdapter{
-		client,
-		documentRepo,
-		indexRepo,
-		migrationHandler,
+		client:              client,
+		documentRepo:        documentRepo,
+		indexRepo:           indexRepo,
+		queryHistoryRepo:    NewQueryHistoryRepository(client),
+		suggestionCompleter: NewSuggestionCompleter(client),
+		feedbackRepo:        NewSuggestionFeedbackRepository(client),
+		spellingCorrector:   NewSpellingCorrector(client),
+		textAnalyzer:        NewTextAnalyzer(client),
+		queryLogRepo:        NewQueryLogRepository(client),
+		linkGraphRepo:       NewLinkGraphRepository(client),
+		savedSearchRepo:     NewSavedSearchRepository(client),
+		percolator:          NewPercolator(client),
+		queryRuleRepo:       NewQueryRuleRepository(client),
+		migrationHandler:    migrationHandler,
 	}
 	return adapter, nil
 }
@@ -56,6 +76,56 @@ func (s *SQLAdapter) IndexRepository() *IndexRepository {
 	return s.indexRepo
 }
 
+// QueryHistoryRepository returns the query history repository
+func (s *SQLAdapter) QueryHistoryRepository() *QueryHistoryRepository {
+	return s.queryHistoryRepo
+}
+
+// SuggestionCompleter returns the suggestion completer
+func (s *SQLAdapter) SuggestionCompleter() *SuggestionCompleter {
+	return s.suggestionCompleter
+}
+
+// SuggestionFeedbackRepository returns the suggestion feedback repository
+func (s *SQLAdapter) SuggestionFeedbackRepository() *SuggestionFeedbackRepository {
+	return s.feedbackRepo
+}
+
+// SpellingCorrector returns the spelling corrector
+func (s *SQLAdapter) SpellingCorrector() *SpellingCorrector {
+	return s.spellingCorrector
+}
+
+// TextAnalyzer returns the text analyzer
+func (s *SQLAdapter) TextAnalyzer() *TextAnalyzer {
+	return s.textAnalyzer
+}
+
+// QueryLogRepository returns the query log repository
+func (s *SQLAdapter) QueryLogRepository() *QueryLogRepository {
+	return s.queryLogRepo
+}
+
+// LinkGraphRepository returns the link graph repository
+func (s *SQLAdapter) LinkGraphRepository() *LinkGraphRepository {
+	return s.linkGraphRepo
+}
+
+// SavedSearchRepository returns the saved search repository
+func (s *SQLAdapter) SavedSearchRepository() *SavedSearchRepository {
+	return s.savedSearchRepo
+}
+
+// Percolator returns the percolator
+func (s *SQLAdapter) Percolator() *Percolator {
+	return s.percolator
+}
+
+// QueryRuleRepository returns the query rule repository
+func (s *SQLAdapter) QueryRuleRepository() *QueryRuleRepository {
+	return s.queryRuleRepo
+}
+
 // MigrationHandler returns the migration handler
 func (s *SQLAdapter) MigrationHandler() *MigrationHandler {
 	return s.migrationHandler
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// saveDocumentAccess stores the access control entries of a document
+func (d DocumentRepository) saveDocumentAccess(tx *gorm.DB, documentID string, document *domain.Document) error {
+	entries := make([]models.DocumentAccess, 0)
+	for _, acl := range documentAccessLists(document) {
+		for _, principal := range acl.principals {
+			entries = append(entries, models.DocumentAccess{
+				DocumentID: documentID,
+				Kind:       acl.kind,
+				Principal:  principal,
+			})
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		return fmt.Errorf("failed to save document access: %w", err)
+	}
+	return nil
+}
+
+// updateDocumentAccess replaces the access control entries of a document
+func (d DocumentRepository) updateDocumentAccess(tx *gorm.DB, documentID string, document *domain.Document) error {
+	if err := tx.Where("document_id = ?", documentID).Delete(&models.DocumentAccess{}).Error; err != nil {
+		return fmt.Errorf("failed to delete old document access: %w", err)
+	}
+	return d.saveDocumentAccess(tx, documentID, document)
+}
+
+// accessList is the principals of one kind allowed to read a document or granted to a scope
+type accessList struct {
+	kind       string
+	principals []string
+}
+
+// documentAccessLists returns the access control lists of a document by kind
+func documentAccessLists(document *domain.Document) []accessList {
+	return []accessList{
+		{models.AccessKindUser, document.AllowedUsers},
+		{models.AccessKindGroup, document.AllowedGroups},
+		{models.AccessKindRole, document.AllowedRoles},
+	}
+}
+
+// applyDocumentAccess restricts a query to the documents the scope can read: the public
+// documents, without access control entries, and those listing one of its principals.
+// Queries without a scope are not restricted.
+func applyDocumentAccess(db *gorm.DB, scope *domain.AccessScope) *gorm.DB {
+	if scope == nil || scope.Unrestricted {
+		return db
+	}
+	conditions := []string{"NOT EXISTS (SELECT 1 FROM document_accesses WHERE document_accesses.document_id = documents.id)"}
+	granted := make([]string, 0, 3)
+	args := make([]interface{}, 0, 6)
+	for _, acl := range []accessList{
+		{models.AccessKindUser, scope.Users},
+		{models.AccessKindGroup, scope.Groups},
+		{models.AccessKindRole, scope.Roles},
+	} {
+		if len(acl.principals) > 0 {
+			granted = append(granted, "(document_accesses.kind = ? AND document_accesses.principal IN ?)")
+			args = append(args, acl.kind, acl.principals)
+		}
+	}
+	if len(granted) > 0 {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM document_accesses WHERE document_accesses.document_id = documents.id AND ("+
+			strings.Join(granted, " OR ")+"))")
+	}
+	return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
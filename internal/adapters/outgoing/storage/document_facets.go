@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// facetRow is a single grouped row of a facet query
+type facetRow struct {
+	FacetKey   string
+	FacetCount int
+}
+
+// facetColumns maps the column-backed terms facets to their document columns
+var facetColumns = map[string]string{
+	domain.FacetFieldContentType: "documents.content_type",
+	domain.FacetFieldLang:        "documents.lang",
+	domain.FacetFieldDomain:      "documents.domain",
+}
+
+func (d DocumentRepository) Facets(ctx context.Context, query *domain.SearchQuery) ([]domain.FacetResult, error) {
+	if query == nil {
+		return nil, errors.New("search query cannot be nil")
+	}
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid search query: %w", err)
+	}
+
+	text, err := d.analyzeText(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.FacetResult, 0, len(query.Facets))
+	for _, facet := range query.Facets {
+		buckets, err := d.facetBuckets(ctx, query, text, facet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute facet %s: %w", facet.Name, err)
+		}
+		results = append(results, domain.FacetResult{
+			Name:    facet.Name,
+			Field:   facet.Field,
+			Type:    facet.Type,
+			Buckets: buckets,
+		})
+	}
+	return results, nil
+}
+
+// facetBuckets computes the buckets of a single facet over the documents matching the query
+func (d DocumentRepository) facetBuckets(ctx context.Context, query *domain.SearchQuery, text textQuery, facet domain.FacetRequest) ([]domain.FacetBucket, error) {
+	switch facet.Type {
+	case domain.TermsFacet:
+		return d.termsFacet(ctx, query, text, facet)
+	case domain.DateHistogramFacet:
+		return d.dateHistogramFacet(ctx, query, text, facet)
+	case domain.RangeFacet:
+		return d.rangeFacet(ctx, query, text, facet)
+	}
+	return nil, fmt.Errorf("unsupported facet type %q", facet.Type)
+}
+
+// termsFacet groups the matching documents by a field value
+func (d DocumentRepository) termsFacet(ctx context.Context, query *domain.SearchQuery, text textQuery, facet domain.FacetRequest) ([]domain.FacetBucket, error) {
+	db := d.buildSearchQuery(ctx, query, text)
+
+	var keyColumn string
+	switch facet.Field {
+	case domain.FacetFieldCategory:
+		db = db.Joins("JOIN document_metadata ON document_metadata.document_id = documents.id")
+		keyColumn = "document_metadata.category"
+	case domain.FacetFieldKeywords:
+		db = db.Joins("JOIN document_keywords ON document_keywords.document_id = documents.id")
+		keyColumn = "document_keywords.keyword"
+	default:
+		keyColumn = facetColumns[facet.Field]
+	}
+
+	var rows []facetRow
+	if err := db.
+		Select(fmt.Sprintf("%s AS facet_key, COUNT(DISTINCT documents.id) AS facet_count", keyColumn)).
+		Where(fmt.Sprintf("%s <> ''", keyColumn)).
+		Group(keyColumn).
+		Order("facet_count DESC, facet_key ASC").
+		Limit(facet.BucketSize()).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toFacetBuckets(rows), nil
+}
+
+// dateHistogramFacet groups the matching documents by their truncated crawl date
+func (d DocumentRepository) dateHistogramFacet(ctx context.Context, query *domain.SearchQuery, text textQuery, facet domain.FacetRequest) ([]domain.FacetBucket, error) {
+	keyExpression := d.dateBucketExpression("documents.last_crawled", facet.Interval)
+
+	var rows []facetRow
+	if err := d.buildSearchQuery(ctx, query, text).
+		Select(fmt.Sprintf("%s AS facet_key, COUNT(*) AS facet_count", keyExpression)).
+		Group(keyExpression).
+		Order("facet_key ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return toFacetBuckets(rows), nil
+}
+
+// rangeFacet counts the matching documents within each content length range
+func (d DocumentRepository) rangeFacet(ctx context.Context, query *domain.SearchQuery, text textQuery, facet domain.FacetRequest) ([]domain.FacetBucket, error) {
+	buckets := make([]domain.FacetBucket, 0, len(facet.Ranges))
+	for _, r := range facet.Ranges {
+		db := d.buildSearchQuery(ctx, query, text)
+		if r.From != nil {
+			db = db.Where("documents.content_length >= ?", *r.From)
+		}
+		if r.To != nil {
+			db = db.Where("documents.content_length < ?", *r.To)
+		}
+
+		var count int64
+		if err := db.Count(&count).Error; err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, domain.FacetBucket{
+			Key:   rangeKey(r),
+			Count: int(count),
+			From:  r.From,
+			To:    r.To,
+		})
+	}
+	return buckets, nil
+}
+
+// dateBucketExpression returns the dialect-specific SQL expression formatting a date column as a bucket key
+func (d DocumentRepository) dateBucketExpression(column, interval string) string {
+	switch d.db.Dialector.Name() {
+	case "postgres":
+		formats := map[string]string{domain.IntervalDay: "YYYY-MM-DD", domain.IntervalMonth: "YYYY-MM", domain.IntervalYear: "YYYY"}
+		return fmt.Sprintf("to_char(%s, '%s')", column, formats[interval])
+	case "mysql":
+		formats := map[string]string{domain.IntervalDay: "%Y-%m-%d", domain.IntervalMonth: "%Y-%m", domain.IntervalYear: "%Y"}
+		return fmt.Sprintf("DATE_FORMAT(%s, '%s')", column, formats[interval])
+	default:
+		// SQLite stores timestamps as ISO-8601 text, so the key is a prefix of the value
+		lengths := map[string]int{domain.IntervalDay: 10, domain.IntervalMonth: 7, domain.IntervalYear: 4}
+		return fmt.Sprintf("substr(%s, 1, %d)", column, lengths[interval])
+	}
+}
+
+// toFacetBuckets converts grouped rows into facet buckets
+func toFacetBuckets(rows []facetRow) []domain.FacetBucket {
+	buckets := make([]domain.FacetBucket, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, domain.FacetBucket{
+			Key:   row.FacetKey,
+			Count: row.FacetCount,
+		})
+	}
+	return buckets
+}
+
+// rangeKey returns the key of a range bucket, deriving one from its bounds when none is set
+func rangeKey(r domain.FacetRange) string {
+	if r.Key != "" {
+		return r.Key
+	}
+	from, to := "*", "*"
+	if r.From != nil {
+		from = fmt.Sprintf("%g", *r.From)
+	}
+	if r.To != nil {
+		to = fmt.Sprintf("%g", *r.To)
+	}
+	return from + "-" + to
+}
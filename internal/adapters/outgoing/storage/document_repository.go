@@ -17,13 +17,15 @@ import (
 
 // DocumentRepository implements the outgoing.DocumentRepository interface using GORM
 type DocumentRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	fullText *fullTextSearch
 }
 
 // NewDocumentRepository creates a new document repository
 func NewDocumentRepository(client *Client) *DocumentRepository {
 	return &DocumentRepository{
-		db: client.DB,
+		db:       client.DB,
+		fullText: newFullTextSearch(client.DB),
 	}
 }
 
@@ -72,6 +74,10 @@ func (d DocumentRepository) Save(ctx context.Context, document *domain.Document)
 			}
 		}
 
+		if err := d.saveDocumentAccess(tx, dbDoc.ID, document); err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
@@ -191,10 +197,11 @@ func (d DocumentRepository) GetByID(ctx context.Context, id string) (*domain.Doc
 	}
 
 	var dbDoc models.Document
-	result := d.db.WithContext(ctx).
+	result := applyDocumentAccess(d.db.WithContext(ctx), domain.AccessScopeFromContext(ctx)).
 		Preload("DocumentMetadata").
 		Preload("DocumentLinks").
 		Preload("DocumentKeywords").
+		Preload("DocumentAccess").
 		First(&dbDoc, "id = ?", id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -211,16 +218,17 @@ func (d DocumentRepository) GetByURL(ctx context.Context, url string) (*domain.D
 	}
 
 	var dbDoc models.Document
-	result := d.db.WithContext(ctx).
+	result := applyDocumentAccess(d.db.WithContext(ctx), domain.AccessScopeFromContext(ctx)).
 		Preload("DocumentMetadata").
 		Preload("DocumentLinks").
 		Preload("DocumentKeywords").
+		Preload("DocumentAccess").
 		First(&dbDoc, "url = ?", url)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to get document by URL: ", result.Error)
+		return nil, fmt.Errorf("failed to get document by URL: %w", result.Error)
 	}
 	return dbDoc.ToDomain(), nil
 }
@@ -251,6 +259,10 @@ func (d DocumentRepository) Delete(ctx context.Context, id string) error {
 			return fmt.Errorf("failed to delete document metadata: %w", err)
 		}
 
+		if err := tx.Where("document_id = ?", id).Delete(&models.DocumentAccess{}).Error; err != nil {
+			return fmt.Errorf("failed to delete document access: %w", err)
+		}
+
 		if err := tx.Delete(&models.Document{}, "id = ?", id).Error; err != nil {
 			return fmt.Errorf("failed to delete document: %w", err)
 		}
@@ -285,16 +297,23 @@ func (d DocumentRepository) Update(ctx context.Context, document *domain.Documen
 
 		if err := tx.Model(&dbDoc).Updates(map[string]interface{}{
 			"url":             dbDoc.URL,
+			"domain":          dbDoc.Domain,
 			"title":           dbDoc.Title,
 			"content":         dbDoc.Content,
 			"content_type":    dbDoc.ContentType,
 			"last_crawled":    dbDoc.LastCrawled,
 			"last_modified":   dbDoc.LastModified,
+			"published_date":  dbDoc.PublishedDate,
 			"lang":            dbDoc.Lang,
 			"meta_desc":       dbDoc.MetaDesc,
 			"content_length":  dbDoc.ContentLength,
 			"importance_rank": dbDoc.ImportanceRank,
+			"incoming_links":  dbDoc.IncomingLinks,
+			"outgoing_links":  dbDoc.OutgoingLinks,
 			"index_id":        dbDoc.IndexID,
+			"is_duplicate":    dbDoc.IsDuplicate,
+			"original_doc_id": dbDoc.OriginalDocID,
+			"embedding":       dbDoc.Embedding,
 		}).Error; err != nil {
 			if d.isUniqueConstraintViolation(err) {
 				return fmt.Errorf("document with URL %s already exists", document.URL)
@@ -316,6 +335,10 @@ func (d DocumentRepository) Update(ctx context.Context, document *domain.Documen
 			return err
 		}
 
+		if err := d.updateDocumentAccess(tx, document.ID, document); err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
@@ -423,16 +446,18 @@ func (d DocumentRepository) List(ctx context.Context, page, pageSize int) ([]*do
 	}
 	offset := (page - 1) * pageSize
 
+	scope := domain.AccessScopeFromContext(ctx)
 	var count int64
-	if err := d.db.WithContext(ctx).Model(&models.Document{}).Count(&count).Error; err != nil {
+	if err := applyDocumentAccess(d.db.WithContext(ctx).Model(&models.Document{}), scope).Count(&count).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count documents: %w", err)
 	}
 
 	var dbDocs []models.Document
-	result := d.db.WithContext(ctx).
+	result := applyDocumentAccess(d.db.WithContext(ctx), scope).
 		Preload("DocumentMetadata").
 		Preload("DocumentLinks").
 		Preload("DocumentKeywords").
+		Preload("DocumentAccess").
 		Offset(offset).
 		Limit(pageSize).
 		Order("last_crawled desc").
@@ -447,6 +472,48 @@ func (d DocumentRepository) List(ctx context.Context, page, pageSize int) ([]*do
 	return documents, int(count), nil
 }
 
+func (d DocumentRepository) ListAfter(ctx context.Context, cursor string, pageSize int) ([]*domain.Document, string, error) {
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	db := applyDocumentAccess(d.db.WithContext(ctx).Model(&models.Document{}), domain.AccessScopeFromContext(ctx))
+	if cursor != "" {
+		position, err := decodeListCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		db = db.Where("last_crawled < ? OR (last_crawled = ? AND id < ?)",
+			position.LastCrawled, position.LastCrawled, position.ID)
+	}
+
+	var dbDocs []models.Document
+	result := db.
+		Preload("DocumentMetadata").
+		Preload("DocumentLinks").
+		Preload("DocumentKeywords").
+		Preload("DocumentAccess").
+		Order("last_crawled DESC, id DESC").
+		Limit(pageSize).
+		Find(&dbDocs)
+	if result.Error != nil {
+		return nil, "", fmt.Errorf("failed to list documents: %w", result.Error)
+	}
+
+	documents := make([]*domain.Document, len(dbDocs))
+	for i, dbDoc := range dbDocs {
+		documents[i] = dbDoc.ToDomain()
+	}
+	if len(dbDocs) < pageSize {
+		return documents, "", nil
+	}
+	last := dbDocs[len(dbDocs)-1]
+	return documents, encodeListCursor(last), nil
+}
+
 func (d DocumentRepository) Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.Document, int, error) {
 	if query == nil {
 		return nil, 0, errors.New("search query cannot be nil")
@@ -454,20 +521,25 @@ func (d DocumentRepository) Search(ctx context.Context, query *domain.SearchQuer
 	if err := query.Validate(); err != nil {
 		return nil, 0, fmt.Errorf("invalid search query: %w", err)
 	}
-	db := d.buildSearchQuery(ctx, query)
+	text, err := d.analyzeText(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	db := d.buildSearchQuery(ctx, query, text)
 
 	var count int64
 	if err := db.Count(&count).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
 	}
 
-	db = d.applyPaginationAndSorting(db, query)
+	db = d.applyPaginationAndSorting(db, query, text)
 
 	var dbDocs []models.Document
 	result := db.
 		Preload("DocumentMetadata").
 		Preload("DocumentLinks").
 		Preload("DocumentKeywords").
+		Preload("DocumentAccess").
 		Find(&dbDocs)
 	if result.Error != nil {
 		return nil, 0, fmt.Errorf("failed to search documents: %w", result.Error)
@@ -476,30 +548,125 @@ func (d DocumentRepository) Search(ctx context.Context, query *domain.SearchQuer
 	documents := make([]*domain.Document, 0, len(dbDocs))
 	for _, dbDoc := range dbDocs {
 		doc := dbDoc.ToDomain()
-		d.applyScoring(doc, query)
+		d.applyScoring(doc, dbDoc, query, text)
 		documents = append(documents, doc)
 	}
+	if len(query.HighlightFields) > 0 {
+		highlightDocuments(documents, query, text)
+	}
+	if usesFreshness(query) {
+		documents = applyFreshness(documents, query, time.Now())
+	}
 
 	return documents, int(count), nil
 }
 
-func (d DocumentRepository) buildSearchQuery(ctx context.Context, query *domain.SearchQuery) *gorm.DB {
+func (d DocumentRepository) SearchAfter(ctx context.Context, query *domain.SearchQuery) ([]*domain.Document, int, string, error) {
+	if query == nil {
+		return nil, 0, "", errors.New("search query cannot be nil")
+	}
+	if err := query.Validate(); err != nil {
+		return nil, 0, "", fmt.Errorf("invalid search query: %w", err)
+	}
+	text, err := d.analyzeText(ctx, query)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	db := d.buildSearchQuery(ctx, query, text)
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, "", fmt.Errorf("failed to count search results: %w", err)
+	}
+	if text.ranked() {
+		db = d.selectScore(db, text)
+	}
+
+	keys, err := d.searchKeyset(query, text)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	if db, err = applyKeyset(db, keys, query.Cursor); err != nil {
+		return nil, 0, "", err
+	}
+
+	var dbDocs []models.Document
+	result := db.
+		Preload("DocumentMetadata").
+		Preload("DocumentLinks").
+		Preload("DocumentKeywords").
+		Preload("DocumentAccess").
+		Limit(query.Limit()).
+		Find(&dbDocs)
+	if result.Error != nil {
+		return nil, 0, "", fmt.Errorf("failed to search documents: %w", result.Error)
+	}
+
+	documents := make([]*domain.Document, 0, len(dbDocs))
+	for _, dbDoc := range dbDocs {
+		doc := dbDoc.ToDomain()
+		d.applyScoring(doc, dbDoc, query, text)
+		documents = append(documents, doc)
+	}
+	if len(query.HighlightFields) > 0 {
+		highlightDocuments(documents, query, text)
+	}
+	if len(dbDocs) < query.Limit() {
+		return documents, int(count), "", nil
+	}
+	last := dbDocs[len(dbDocs)-1]
+	return documents, int(count), encodeSearchCursor(last, keys), nil
+}
+
+// buildSearchQuery restricts the documents to those matching the search query. The analyzed text
+// is matched with the full-text index when it exists, by substrings otherwise.
+func (d DocumentRepository) buildSearchQuery(ctx context.Context, query *domain.SearchQuery, text textQuery) *gorm.DB {
 	db := d.db.WithContext(ctx).Model(&models.Document{})
 
-	if query.Query != "" {
-		searchTerm := "%" + query.Query + "%"
-		db = db.Where("title LIKE ? OR content LIKE ? OR meta_desc LIKE ?", searchTerm, searchTerm, searchTerm)
+	if query.UseQuerySyntax {
+		if query.Expression != nil {
+			var fullText *fullTextSearch
+			if d.fullText.available(ctx) {
+				fullText = d.fullText
+			}
+			condition, args := expressionCondition(query.Expression, fullText)
+			db = db.Where(condition, args...)
+		}
+	} else if text.fullText {
+		db = d.fullText.match(db, text)
+	} else if len(text.groups) > 0 {
+		db = likeGroups(db, text.groups)
+	} else if query.Query != "" {
+		searchTerm := "%" + escapeLike(query.Query) + "%"
+		db = db.Where("title"+likeCondition+" OR content"+likeCondition+" OR meta_desc"+likeCondition, searchTerm, searchTerm, searchTerm)
 	}
 
+	db = applyDocumentAccess(db, domain.AccessScopeFromContext(ctx))
+	return d.applyFilters(db, query)
+}
+
+// applyFilters restricts a query to the documents matching the filters and time range of the search query
+func (d DocumentRepository) applyFilters(db *gorm.DB, query *domain.SearchQuery) *gorm.DB {
 	if query.Filters != nil {
-		if indexID, ok := query.Filters["index_id"].(string); ok && indexID != "" {
-			db = db.Where("index_id = ?", indexID)
+		switch indexID := query.Filters["index_id"].(type) {
+		case string:
+			if indexID != "" {
+				db = db.Where("index_id = ?", indexID)
+			}
+		case []string:
+			if len(indexID) > 0 {
+				db = db.Where("index_id IN ?", indexID)
+			}
 		}
 		if contentType, ok := query.Filters["content_type"].(string); ok && contentType != "" {
 			db = db.Where("content_type = ?", contentType)
 		}
 	}
 
+	if query.Language != "" {
+		db = db.Where("lang = ?", query.Language)
+	}
+
 	if query.TimeRange != nil && !query.TimeRange.From.IsZero() {
 		field := "last_crawled"
 		if query.TimeRange.Field != "" {
@@ -515,13 +682,25 @@ func (d DocumentRepository) buildSearchQuery(ctx context.Context, query *domain.
 	return db
 }
 
-func (d DocumentRepository) applyPaginationAndSorting(db *gorm.DB, query *domain.SearchQuery) *gorm.DB {
-	db = db.Offset(query.Offset()).Limit(query.Limit())
+// applyPaginationAndSorting orders and pages the results. Full-text searches rank by relevance
+// unless sort fields are given. Searches boosting fresh documents fetch the top candidates
+// instead of the page, to re-order them once scored.
+func (d DocumentRepository) applyPaginationAndSorting(db *gorm.DB, query *domain.SearchQuery, text textQuery) *gorm.DB {
+	if usesFreshness(query) {
+		db = db.Limit(freshnessCandidates(query))
+	} else {
+		db = db.Offset(query.Offset()).Limit(query.Limit())
+	}
+	if text.ranked() {
+		db = d.selectScore(db, text)
+	}
 	if len(query.SortFields) > 0 {
 		for _, field := range query.SortFields {
 			order := strings.ToUpper(string(query.SortOrder))
 			db = db.Order(fmt.Sprintf("%s %s", field, order))
 		}
+	} else if text.ranked() {
+		db = db.Order("text_score DESC, importance_rank DESC, last_crawled DESC")
 	} else {
 		db = db.Order("importance_rank DESC, last_crawled DESC")
 	}
@@ -529,7 +708,25 @@ func (d DocumentRepository) applyPaginationAndSorting(db *gorm.DB, query *domain
 	return db
 }
 
-func (d DocumentRepository) applyScoring(doc *domain.Document, query *domain.SearchQuery) {
+// applyScoring sets the score of a result: the full-text relevance when the index was used, the
+// weights of the fields containing the analyzed phrases when it was not, otherwise a flat bonus
+// for the query text appearing in the title or content. Explained searches also record how the
+// score was computed.
+func (d DocumentRepository) applyScoring(doc *domain.Document, dbDoc models.Document, query *domain.SearchQuery, text textQuery) {
+	if text.fullText {
+		doc.Score = relevance(dbDoc.TextScore)
+		if query.Explain {
+			doc.Explanation = d.fullText.explain(dbDoc, text.weights)
+		}
+		return
+	}
+	if text.ranked() {
+		doc.Score = dbDoc.TextScore
+		if query.Explain {
+			doc.Explanation = explainLikeScore(doc, text)
+		}
+		return
+	}
 	if query.Query == "" {
 		return
 	}
@@ -538,11 +735,17 @@ func (d DocumentRepository) applyScoring(doc *domain.Document, query *domain.Sea
 	titleLower := strings.ToLower(doc.Title)
 	contentLower := strings.ToLower(doc.Content)
 
+	var details []*domain.ScoreExplanation
 	if strings.Contains(titleLower, queryLower) {
 		doc.Score += 10.0
+		details = append(details, domain.NewScoreExplanation(10, "query text in title"))
 	}
 	if strings.Contains(contentLower, queryLower) {
 		doc.Score += 5.0
+		details = append(details, domain.NewScoreExplanation(5, "query text in content"))
+	}
+	if query.Explain {
+		doc.Explanation = domain.NewScoreExplanation(doc.Score, "substring match bonus, sum of:", details...)
 	}
 }
 
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// vectorScanBatchSize is the number of embeddings loaded at once during a vector search
+const vectorScanBatchSize = 500
+
+// vectorRow holds the columns of a document read during a vector search
+type vectorRow struct {
+	ID        string
+	Embedding []byte
+}
+
+// vectorMatch is a document ID with its similarity to the query vector
+type vectorMatch struct {
+	id         string
+	similarity float64
+}
+
+// SearchByVector returns the k documents whose embeddings are most similar to the query vector.
+// SQL databases have no vector index, so all embeddings matching the filters are scanned.
+func (d DocumentRepository) SearchByVector(ctx context.Context, query *domain.SearchQuery, k int) ([]*domain.Document, error) {
+	if query == nil {
+		return nil, errors.New("search query cannot be nil")
+	}
+	if len(query.Vector) == 0 {
+		return nil, errors.New("search query has no vector")
+	}
+	if k <= 0 {
+		return nil, errors.New("k must be positive")
+	}
+
+	matches := make([]vectorMatch, 0, k+1)
+	var rows []vectorRow
+	db := applyDocumentAccess(d.db.WithContext(ctx).Model(&models.Document{}), domain.AccessScopeFromContext(ctx))
+	err := d.applyFilters(db, query).
+		Select("id, embedding").
+		Where("embedding IS NOT NULL").
+		FindInBatches(&rows, vectorScanBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, row := range rows {
+				similarity := domain.CosineSimilarity(query.Vector, models.DecodeEmbedding(row.Embedding))
+				if similarity <= 0 {
+					continue
+				}
+				matches = insertMatch(matches, vectorMatch{row.ID, similarity}, k)
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan document embeddings: %w", err)
+	}
+	documents, err := d.loadMatches(ctx, matches)
+	if err != nil {
+		return nil, err
+	}
+	if query.Explain {
+		for _, doc := range documents {
+			doc.Explanation = domain.NewScoreExplanation(doc.Score, "cosine similarity of the query and document embeddings")
+		}
+	}
+	return documents, nil
+}
+
+// loadMatches loads the documents of the matches in their order, scored by their similarity
+func (d DocumentRepository) loadMatches(ctx context.Context, matches []vectorMatch) ([]*domain.Document, error) {
+	if len(matches) == 0 {
+		return []*domain.Document{}, nil
+	}
+
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.id
+	}
+	var dbDocs []models.Document
+	if err := d.db.WithContext(ctx).
+		Preload("DocumentMetadata").
+		Preload("DocumentLinks").
+		Preload("DocumentKeywords").
+		Preload("DocumentAccess").
+		Where("id IN ?", ids).
+		Find(&dbDocs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load similar documents: %w", err)
+	}
+
+	byID := make(map[string]*domain.Document, len(dbDocs))
+	for i := range dbDocs {
+		byID[dbDocs[i].ID] = dbDocs[i].ToDomain()
+	}
+	documents := make([]*domain.Document, 0, len(matches))
+	for _, match := range matches {
+		if doc, ok := byID[match.id]; ok {
+			doc.Score = match.similarity
+			documents = append(documents, doc)
+		}
+	}
+	return documents, nil
+}
+
+// insertMatch adds a match to a list sorted by descending similarity, keeping at most k matches
+func insertMatch(matches []vectorMatch, match vectorMatch, k int) []vectorMatch {
+	if len(matches) == k && match.similarity <= matches[k-1].similarity {
+		return matches
+	}
+	i := sort.Search(len(matches), func(i int) bool {
+		return matches[i].similarity < match.similarity
+	})
+	matches = append(matches, vectorMatch{})
+	copy(matches[i+1:], matches[i:])
+	matches[i] = match
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
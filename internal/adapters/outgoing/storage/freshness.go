@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// freshnessWindow is the minimum number of top candidates re-scored by the decay functions of a
+// search. Documents beyond the window are not boosted into the results.
+const freshnessWindow = 200
+
+// usesFreshness reports whether the results of a search are re-ordered by their freshness.
+// Explicit sorting keeps the requested order.
+func usesFreshness(query *domain.SearchQuery) bool {
+	return len(query.DecayFunctions) > 0 && len(query.SortFields) == 0
+}
+
+// freshnessCandidates returns the number of top candidates to fetch for the requested page
+func freshnessCandidates(query *domain.SearchQuery) int {
+	return max(freshnessWindow, query.Offset()+query.Limit())
+}
+
+// applyFreshness multiplies the score of each candidate by its freshness boost, re-orders the
+// candidates by score and returns the requested page. Ties keep the database order.
+func applyFreshness(documents []*domain.Document, query *domain.SearchQuery, now time.Time) []*domain.Document {
+	for _, doc := range documents {
+		boost := domain.FreshnessBoost(query.DecayFunctions, doc, now)
+		if query.Explain {
+			doc.Explanation = domain.NewScoreExplanation(doc.Score*boost, "relevance × freshness boost, product of:",
+				doc.Explanation, domain.ExplainFreshness(query.DecayFunctions, doc, now))
+		}
+		doc.Score *= boost
+	}
+	sort.SliceStable(documents, func(i, j int) bool {
+		return documents[i].Score > documents[j].Score
+	})
+	start := min(query.Offset(), len(documents))
+	end := min(start+query.Limit(), len(documents))
+	return documents[start:end]
+}
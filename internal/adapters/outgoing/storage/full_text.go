@@ -0,0 +1,472 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/textutil"
+)
+
+// Full-text index objects. Titles, descriptions and contents are weighted by the search fields
+// of the query, 3:2:1 by default in every dialect, matching the Elasticsearch repository.
+const (
+	postgresSearchColumn = "search_vector"
+	postgresSearchIndex  = "idx_documents_search_vector"
+	mysqlFullTextIndex   = "idx_documents_fulltext"
+	mysqlTitleTextIndex  = "idx_documents_fulltext_title"
+	sqliteFullTextTable  = "documents_fts"
+	postgresSearchVector = "setweight(to_tsvector('simple', coalesce(NEW.title, '')), 'A') || setweight(to_tsvector('simple', coalesce(NEW.meta_desc, '')), 'B') || setweight(to_tsvector('simple', coalesce(NEW.content, '')), 'C')"
+	// The ranks of each field alone, selected to explain scores
+	sqliteFieldRankExpressions = "-bm25(documents_fts, 1.0, 0.0, 0.0) AS title_score, -bm25(documents_fts, 0.0, 1.0, 0.0) AS meta_desc_score, -bm25(documents_fts, 0.0, 0.0, 1.0) AS content_score"
+	postgresTitleWeights       = "{0, 0, 0, 1}"
+	postgresMetaDescWeights    = "{0, 0, 1, 0}"
+	postgresContentWeights     = "{0, 1, 0, 0}"
+	mysqlMatchAllColumns       = "MATCH(title, meta_desc, content) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	mysqlMatchTitleColumn      = "MATCH(title) AGAINST (? IN NATURAL LANGUAGE MODE)"
+)
+
+// fullTextMigrations are the statements creating the full-text index, and the triggers keeping it
+// in sync, of each dialect. MySQL maintains FULLTEXT indexes itself and is handled separately.
+var fullTextMigrations = map[string][]string{
+	"postgres": {
+		"ALTER TABLE documents ADD COLUMN IF NOT EXISTS " + postgresSearchColumn + " tsvector",
+		`CREATE OR REPLACE FUNCTION documents_search_vector_update() RETURNS trigger AS $$
+BEGIN
+	NEW.` + postgresSearchColumn + ` := ` + postgresSearchVector + `;
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql`,
+		"DROP TRIGGER IF EXISTS documents_search_vector_trigger ON documents",
+		"CREATE TRIGGER documents_search_vector_trigger BEFORE INSERT OR UPDATE OF title, meta_desc, content ON documents FOR EACH ROW EXECUTE FUNCTION documents_search_vector_update()",
+		// Touching the title fires the trigger for the documents stored before the column existed
+		"UPDATE documents SET title = title WHERE " + postgresSearchColumn + " IS NULL",
+		"CREATE INDEX IF NOT EXISTS " + postgresSearchIndex + " ON documents USING GIN (" + postgresSearchColumn + ")",
+	},
+	"sqlite": {
+		"CREATE VIRTUAL TABLE IF NOT EXISTS " + sqliteFullTextTable + " USING fts5(title, meta_desc, content, content='documents')",
+		`CREATE TRIGGER IF NOT EXISTS documents_fts_insert AFTER INSERT ON documents BEGIN
+	INSERT INTO documents_fts(rowid, title, meta_desc, content) VALUES (new.rowid, new.title, new.meta_desc, new.content);
+END`,
+		`CREATE TRIGGER IF NOT EXISTS documents_fts_delete AFTER DELETE ON documents BEGIN
+	INSERT INTO documents_fts(documents_fts, rowid, title, meta_desc, content) VALUES ('delete', old.rowid, old.title, old.meta_desc, old.content);
+END`,
+		`CREATE TRIGGER IF NOT EXISTS documents_fts_update AFTER UPDATE OF title, meta_desc, content ON documents BEGIN
+	INSERT INTO documents_fts(documents_fts, rowid, title, meta_desc, content) VALUES ('delete', old.rowid, old.title, old.meta_desc, old.content);
+	INSERT INTO documents_fts(rowid, title, meta_desc, content) VALUES (new.rowid, new.title, new.meta_desc, new.content);
+END`,
+		"INSERT INTO documents_fts(documents_fts) VALUES ('rebuild')",
+	},
+}
+
+// migrateFullText creates the full-text index of the documents table. SQLite builds without the
+// FTS5 module (the sqlite_fts5 build tag of go-sqlite3) keep the LIKE based search.
+func (m *MigrationHandler) migrateFullText() error {
+	dialect := m.db.Dialector.Name()
+	if dialect == "mysql" {
+		for name, columns := range map[string]string{mysqlFullTextIndex: "title, meta_desc, content", mysqlTitleTextIndex: "title"} {
+			if m.db.Migrator().HasIndex(&models.Document{}, name) {
+				continue
+			}
+			if err := m.db.Exec(fmt.Sprintf("CREATE FULLTEXT INDEX %s ON documents (%s)", name, columns)).Error; err != nil {
+				return fmt.Errorf("failed to create full-text index %s: %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	// The SQLite index is only rebuilt when it is created; triggers keep it in sync afterwards
+	if dialect == "sqlite" && m.db.Migrator().HasTable(sqliteFullTextTable) {
+		return nil
+	}
+	for _, statement := range fullTextMigrations[dialect] {
+		if err := m.db.Exec(statement).Error; err != nil {
+			if dialect == "sqlite" && strings.Contains(err.Error(), "no such module: fts5") {
+				log.Println("WARNING: SQLite was built without FTS5, full-text search falls back to LIKE matching")
+				return nil
+			}
+			return fmt.Errorf("failed to create full-text index: %w", err)
+		}
+	}
+	return nil
+}
+
+// dropFullText drops the full-text objects that are not dropped along with the documents table
+func (m *MigrationHandler) dropFullText() error {
+	if m.db.Dialector.Name() != "sqlite" {
+		return nil
+	}
+	if err := m.db.Exec("DROP TABLE IF EXISTS " + sqliteFullTextTable).Error; err != nil {
+		return fmt.Errorf("failed to drop full-text index: %w", err)
+	}
+	return nil
+}
+
+// fullTextSearch matches and ranks documents with the native full-text index of the database dialect
+type fullTextSearch struct {
+	db    *gorm.DB
+	ready atomic.Bool
+}
+
+// newFullTextSearch creates a full-text search over the documents table
+func newFullTextSearch(db *gorm.DB) *fullTextSearch {
+	return &fullTextSearch{
+		db: db,
+	}
+}
+
+// available reports whether the full-text index exists. A missing index is looked up again on
+// the next search, so searches switch to the index once migrations have created it.
+func (f *fullTextSearch) available(ctx context.Context) bool {
+	if f.ready.Load() {
+		return true
+	}
+	migrator := f.db.WithContext(ctx).Migrator()
+	var exists bool
+	switch f.db.Dialector.Name() {
+	case "postgres":
+		exists = migrator.HasColumn(&models.Document{}, postgresSearchColumn)
+	case "mysql":
+		exists = migrator.HasIndex(&models.Document{}, mysqlFullTextIndex) && migrator.HasIndex(&models.Document{}, mysqlTitleTextIndex)
+	case "sqlite":
+		exists = migrator.HasTable(sqliteFullTextTable)
+	}
+	if exists {
+		f.ready.Store(true)
+	}
+	return exists
+}
+
+// match restricts a query to the documents matching every group of terms by one of its phrases
+func (f *fullTextSearch) match(db *gorm.DB, text textQuery) *gorm.DB {
+	switch f.db.Dialector.Name() {
+	case "postgres":
+		return db.Where(postgresSearchColumn+" @@ to_tsquery('simple', ?)", postgresQueryExpression(text.groups))
+	case "mysql":
+		return db.Where("MATCH(title, meta_desc, content) AGAINST (? IN BOOLEAN MODE)", mysqlBooleanExpression(text.groups))
+	default:
+		// bm25 can only be computed within the full-text query, so the ranking is joined in
+		columns := sqliteRankExpression(text.weights) + " AS text_score"
+		if text.explain {
+			columns += ", " + sqliteFieldRankExpressions
+		}
+		return db.Joins("JOIN (SELECT rowid AS fts_rowid, "+columns+" FROM "+sqliteFullTextTable+
+			" WHERE "+sqliteFullTextTable+" MATCH ?) AS fts ON fts.fts_rowid = documents.rowid", sqliteMatchExpression(text.groups))
+	}
+}
+
+// phraseCondition returns the condition matching the documents containing the words of a
+// phrase with the full-text index, usable within boolean expressions. Values without any word
+// cannot be matched by the index.
+func (f *fullTextSearch) phraseCondition(value string) (string, []interface{}, bool) {
+	words := textutil.Tokenize(value)
+	if len(words) == 0 {
+		return "", nil, false
+	}
+	groups := [][]string{{strings.Join(words, " ")}}
+	switch f.db.Dialector.Name() {
+	case "postgres":
+		return postgresSearchColumn + " @@ to_tsquery('simple', ?)", []interface{}{postgresQueryExpression(groups)}, true
+	case "mysql":
+		return "MATCH(title, meta_desc, content) AGAINST (? IN BOOLEAN MODE)", []interface{}{mysqlBooleanExpression(groups)}, true
+	default:
+		return "documents.rowid IN (SELECT rowid FROM " + sqliteFullTextTable + " WHERE " + sqliteFullTextTable + " MATCH ?)",
+			[]interface{}{sqliteMatchExpression(groups)}, true
+	}
+}
+
+// selectScore selects the documents with their raw relevance to the terms as text_score,
+// where higher is more relevant. Explained searches also select the relevance of each field.
+func (f *fullTextSearch) selectScore(db *gorm.DB, text textQuery) *gorm.DB {
+	switch f.db.Dialector.Name() {
+	case "postgres":
+		expression := postgresQueryExpression(text.groups)
+		if text.explain {
+			return db.Select("documents.*, "+postgresRankColumn(postgresRankWeights(text.weights), "text_score")+", "+
+				postgresRankColumn(postgresTitleWeights, "title_score")+", "+
+				postgresRankColumn(postgresMetaDescWeights, "meta_desc_score")+", "+
+				postgresRankColumn(postgresContentWeights, "content_score"),
+				expression, expression, expression, expression)
+		}
+		return db.Select("documents.*, "+postgresRankColumn(postgresRankWeights(text.weights), "text_score"), expression)
+	case "mysql":
+		terms := strings.Join(flattenGroups(text.groups), " ")
+		if text.explain {
+			return db.Select("documents.*, "+mysqlRankExpression(text.weights)+" AS text_score, "+
+				mysqlMatchTitleColumn+" AS title_score", terms, terms, terms)
+		}
+		return db.Select("documents.*, "+mysqlRankExpression(text.weights)+" AS text_score", terms, terms)
+	default:
+		if text.explain {
+			return db.Select("documents.*, fts.text_score AS text_score, fts.title_score AS title_score, " +
+				"fts.meta_desc_score AS meta_desc_score, fts.content_score AS content_score")
+		}
+		return db.Select("documents.*, fts.text_score AS text_score")
+	}
+}
+
+// scoreExpression returns the raw relevance selected as text_score by selectScore, as an
+// expression that can be compared in conditions where the alias cannot
+func (f *fullTextSearch) scoreExpression(text textQuery) (string, []interface{}) {
+	switch f.db.Dialector.Name() {
+	case "postgres":
+		return "ts_rank_cd('" + postgresRankWeights(text.weights) + "', " + postgresSearchColumn + ", to_tsquery('simple', ?))",
+			[]interface{}{postgresQueryExpression(text.groups)}
+	case "mysql":
+		terms := strings.Join(flattenGroups(text.groups), " ")
+		return mysqlRankExpression(text.weights), []interface{}{terms, terms}
+	default:
+		return "fts.text_score", nil
+	}
+}
+
+// postgresRankColumn selects the cover density rank of the documents with the weights of the
+// title, description and content as the named column
+func postgresRankColumn(weights, name string) string {
+	return "ts_rank_cd('" + weights + "', " + postgresSearchColumn + ", to_tsquery('simple', ?)) AS " + name
+}
+
+// sqliteRankExpression ranks the documents by BM25 with the weights of the fields
+func sqliteRankExpression(weights fieldWeights) string {
+	return fmt.Sprintf("-bm25(documents_fts, %g, %g, %g)", weights.title, weights.metaDesc, weights.content)
+}
+
+// postgresRankWeights returns the weights of the content, description and title labels of the
+// search vector, scaled into the [0, 1] range ts_rank_cd expects
+func postgresRankWeights(weights fieldWeights) string {
+	scale := max(weights.title, weights.metaDesc, weights.content)
+	return fmt.Sprintf("{0, %g, %g, %g}", weights.content/scale, weights.metaDesc/scale, weights.title/scale)
+}
+
+// mysqlRankExpression ranks the documents by their relevance over all fields, weighted as the
+// content, plus their title relevance weighted by the rest of the title weight. MySQL only
+// indexes the title on its own, so descriptions are weighted as contents.
+func mysqlRankExpression(weights fieldWeights) string {
+	return fmt.Sprintf("%g * %s + %g * %s", weights.content, mysqlMatchAllColumns, max(weights.title-weights.content, 0), mysqlMatchTitleColumn)
+}
+
+// explain returns the explanation of the full-text relevance of a document, breaking the text
+// score down by field
+func (f *fullTextSearch) explain(dbDoc models.Document, weights fieldWeights) *domain.ScoreExplanation {
+	var text *domain.ScoreExplanation
+	switch f.db.Dialector.Name() {
+	case "mysql":
+		titleBoost := max(weights.title-weights.content, 0)
+		text = domain.NewScoreExplanation(dbDoc.TextScore, "text score, sum of:",
+			domain.NewScoreExplanation(dbDoc.TextScore-titleBoost*dbDoc.TitleScore, fmt.Sprintf("MATCH(title, meta_desc, content) natural language relevance × %.4g", weights.content)),
+			domain.NewScoreExplanation(titleBoost*dbDoc.TitleScore, fmt.Sprintf("title boost: MATCH(title) %.4g × %.4g", dbDoc.TitleScore, titleBoost)))
+	case "postgres":
+		scale := max(weights.title, weights.metaDesc, weights.content)
+		text = domain.NewScoreExplanation(dbDoc.TextScore, "text score, ts_rank_cd of the weighted fields, approximately the sum of:",
+			explainField("title", "ts_rank_cd", dbDoc.TitleScore, weights.title/scale),
+			explainField("meta_desc", "ts_rank_cd", dbDoc.MetaDescScore, weights.metaDesc/scale),
+			explainField("content", "ts_rank_cd", dbDoc.ContentScore, weights.content/scale))
+	default:
+		text = domain.NewScoreExplanation(dbDoc.TextScore, "text score, BM25 of the weighted fields, scoring alone:",
+			explainField("title", "BM25", dbDoc.TitleScore, weights.title),
+			explainField("meta_desc", "BM25", dbDoc.MetaDescScore, weights.metaDesc),
+			explainField("content", "BM25", dbDoc.ContentScore, weights.content))
+	}
+	return domain.NewScoreExplanation(relevance(dbDoc.TextScore), "full-text relevance, text score / (1 + text score), from:", text)
+}
+
+// explainField explains the weighted relevance of a field
+func explainField(field, ranking string, score, weight float64) *domain.ScoreExplanation {
+	return domain.NewScoreExplanation(score*weight, fmt.Sprintf("%s: %s %.4g × field weight %.4g", field, ranking, score, weight))
+}
+
+// textQuery is the analyzed text of a search query: groups of alternative phrases, each group
+// required, the weights of the fields ranking them, whether they are matched with the full-text
+// index and whether scores are explained
+type textQuery struct {
+	groups   [][]string
+	weights  fieldWeights
+	fullText bool
+	explain  bool
+}
+
+// ranked reports whether the documents are ranked by their relevance to the text
+func (t textQuery) ranked() bool {
+	return len(t.groups) > 0
+}
+
+// fieldWeights are the weights of the text fields in the relevance of a document
+type fieldWeights struct {
+	title, metaDesc, content float64
+}
+
+// weightedColumn is a text column with its weight
+type weightedColumn struct {
+	name   string
+	weight float64
+}
+
+// columns returns the text columns with their weights
+func (w fieldWeights) columns() []weightedColumn {
+	return []weightedColumn{{"title", w.title}, {"meta_desc", w.metaDesc}, {"content", w.content}}
+}
+
+// defaultFieldWeights weight titles, descriptions and contents 3:2:1
+var defaultFieldWeights = fieldWeights{title: 3, metaDesc: 2, content: 1}
+
+// searchFieldWeights returns the weights of the search fields of a query. Fields left out of
+// the search fields do not rank documents; queries weighting none of the text fields use the
+// default weights.
+func searchFieldWeights(query *domain.SearchQuery) fieldWeights {
+	weights := fieldWeights{
+		title:    float64(query.SearchFields["title"]),
+		metaDesc: float64(query.SearchFields["meta_desc"]),
+		content:  float64(query.SearchFields["content"]),
+	}
+	if weights.title <= 0 && weights.metaDesc <= 0 && weights.content <= 0 {
+		return defaultFieldWeights
+	}
+	weights.title, weights.metaDesc, weights.content = max(weights.title, 0), max(weights.metaDesc, 0), max(weights.content, 0)
+	return weights
+}
+
+// analyzeText analyzes the text of a search query with the stopwords and synonyms of the searched
+// index. Parsed query strings are matched from their syntax tree and are not analyzed.
+func (d DocumentRepository) analyzeText(ctx context.Context, query *domain.SearchQuery) (textQuery, error) {
+	if query.UseQuerySyntax || query.Query == "" {
+		return textQuery{}, nil
+	}
+	groups, err := d.queryGroups(ctx, query)
+	if err != nil {
+		return textQuery{}, err
+	}
+	return textQuery{
+		groups:   groups,
+		weights:  searchFieldWeights(query),
+		fullText: len(groups) > 0 && d.fullText.available(ctx),
+		explain:  query.Explain,
+	}, nil
+}
+
+// selectScore selects the relevance of the documents to the text as text_score: their full-text
+// rank when the index is used, otherwise the weights of the fields containing the phrases
+func (d DocumentRepository) selectScore(db *gorm.DB, text textQuery) *gorm.DB {
+	if text.fullText {
+		return d.fullText.selectScore(db, text)
+	}
+	expression, args := likeScoreExpression(text)
+	return db.Select("documents.*, "+expression+" AS text_score", args...)
+}
+
+// scoreExpression returns the relevance selected as text_score by selectScore, as an expression
+// that can be compared in conditions
+func (d DocumentRepository) scoreExpression(text textQuery) (string, []interface{}) {
+	if text.fullText {
+		return d.fullText.scoreExpression(text)
+	}
+	return likeScoreExpression(text)
+}
+
+// likeGroups restricts a query to the documents containing every group of terms by one of its
+// phrases, for databases without a full-text index
+func likeGroups(db *gorm.DB, groups [][]string) *gorm.DB {
+	for _, group := range groups {
+		conditions := make([]string, 0, len(group))
+		args := make([]interface{}, 0, len(group)*len(textSearchColumns))
+		for _, phrase := range group {
+			condition, phraseArgs := termCondition(domain.AnyField, phrase, false)
+			conditions = append(conditions, condition)
+			args = append(args, phraseArgs...)
+		}
+		db = db.Where(strings.Join(conditions, " OR "), args...)
+	}
+	return db
+}
+
+// likeScoreExpression ranks the documents matched without a full-text index by the weights of
+// the fields containing each phrase
+func likeScoreExpression(text textQuery) (string, []interface{}) {
+	terms := make([]string, 0)
+	args := make([]interface{}, 0)
+	for _, phrase := range flattenGroups(text.groups) {
+		for _, column := range text.weights.columns() {
+			if column.weight > 0 {
+				terms = append(terms, fmt.Sprintf("CASE WHEN %s%s THEN %g ELSE 0 END", column.name, likeCondition, column.weight))
+				args = append(args, "%"+escapeLike(phrase)+"%")
+			}
+		}
+	}
+	return "(" + strings.Join(terms, " + ") + ")", args
+}
+
+// explainLikeScore explains the score of likeScoreExpression, listing the fields containing each phrase
+func explainLikeScore(doc *domain.Document, text textQuery) *domain.ScoreExplanation {
+	details := make([]*domain.ScoreExplanation, 0)
+	for _, phrase := range flattenGroups(text.groups) {
+		for _, column := range text.weights.columns() {
+			if column.weight > 0 && strings.Contains(strings.ToLower(documentColumn(doc, column.name)), strings.ToLower(phrase)) {
+				details = append(details, domain.NewScoreExplanation(column.weight, fmt.Sprintf("%q in %s, field weight", phrase, column.name)))
+			}
+		}
+	}
+	return domain.NewScoreExplanation(doc.Score, "substring relevance, sum of:", details...)
+}
+
+// postgresQueryExpression joins the groups into a tsquery: phrases are word sequences,
+// alternatives are ORed and groups are ANDed
+func postgresQueryExpression(groups [][]string) string {
+	return joinGroups(groups, " & ", " | ", func(phrase string) string {
+		words := strings.Fields(phrase)
+		for i, word := range words {
+			words[i] = "'" + strings.ReplaceAll(word, "'", "''") + "'"
+		}
+		return strings.Join(words, " <-> ")
+	})
+}
+
+// mysqlBooleanExpression joins the groups into a boolean mode search requiring each group.
+// Boolean mode phrases cannot escape double quotes, so they are removed from the phrases.
+func mysqlBooleanExpression(groups [][]string) string {
+	return "+" + joinGroups(groups, " +", " ", func(phrase string) string {
+		return `"` + strings.ReplaceAll(phrase, `"`, " ") + `"`
+	})
+}
+
+// sqliteMatchExpression quotes each phrase so that FTS5 matches it literally, all groups required
+func sqliteMatchExpression(groups [][]string) string {
+	return joinGroups(groups, " AND ", " OR ", func(phrase string) string {
+		return `"` + strings.ReplaceAll(phrase, `"`, `""`) + `"`
+	})
+}
+
+// joinGroups formats the phrases of each group, joins the alternatives of a group in parentheses
+// and joins the groups
+func joinGroups(groups [][]string, groupSeparator, alternativeSeparator string, format func(string) string) string {
+	parts := make([]string, len(groups))
+	for i, group := range groups {
+		alternatives := make([]string, len(group))
+		for j, phrase := range group {
+			alternatives[j] = format(phrase)
+		}
+		parts[i] = "(" + strings.Join(alternatives, alternativeSeparator) + ")"
+	}
+	return strings.Join(parts, groupSeparator)
+}
+
+// flattenGroups returns all the phrases of the groups
+func flattenGroups(groups [][]string) []string {
+	phrases := make([]string, 0, len(groups))
+	for _, group := range groups {
+		phrases = append(phrases, group...)
+	}
+	return phrases
+}
+
+// relevance maps a raw text score of any dialect onto [0, 1)
+func relevance(textScore float64) float64 {
+	if textScore <= 0 {
+		return 0
+	}
+	return textScore / (1 + textScore)
+}
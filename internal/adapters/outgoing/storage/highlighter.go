@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/textutil"
+)
+
+// highlightTerm is a word, phrase or wildcard of a query marked in snippets. Scoped terms only
+// mark their column.
+type highlightTerm struct {
+	column  string
+	words   []string
+	pattern *regexp.Regexp
+}
+
+// highlightToken is a word of a text with its rune offsets
+type highlightToken struct {
+	start, end int
+	text       string
+}
+
+// highlightMatch is an occurrence of a term in a text, by rune offsets
+type highlightMatch struct {
+	start, end int
+	term       int
+}
+
+// passage is a candidate snippet of a text with the matches it contains
+type passage struct {
+	start, end int
+	matches    []highlightMatch
+	score      int
+}
+
+// highlightDocuments sets the snippets of the highlighted fields of the documents. Snippets are
+// the passages of a field holding the most distinct query terms, then the most matches.
+func highlightDocuments(documents []*domain.Document, query *domain.SearchQuery, text textQuery) {
+	terms := highlightTerms(query, text)
+	if len(terms) == 0 {
+		return
+	}
+	options := query.Highlight.WithDefaults(0)
+	for _, doc := range documents {
+		highlights := make(map[string][]string)
+		for _, field := range query.HighlightFields {
+			if snippets := highlightText(documentColumn(doc, field), field, terms, options); len(snippets) > 0 {
+				highlights[field] = snippets
+			}
+		}
+		if len(highlights) > 0 {
+			doc.Highlights = highlights
+		}
+	}
+}
+
+// highlightTerms returns the terms of a query to mark: the analyzed groups of its text, or the
+// words, phrases and wildcards its parsed expression requires
+func highlightTerms(query *domain.SearchQuery, text textQuery) []highlightTerm {
+	terms := make([]highlightTerm, 0)
+	if query.UseQuerySyntax {
+		if query.Expression != nil {
+			terms = appendExpressionTerms(terms, query.Expression)
+		}
+		return terms
+	}
+	for _, group := range text.groups {
+		for _, phrase := range group {
+			if words := textutil.Tokenize(phrase); len(words) > 0 {
+				terms = append(terms, highlightTerm{words: words})
+			}
+		}
+	}
+	return terms
+}
+
+// appendExpressionTerms appends the text terms of an expression, leaving out filters and negated clauses
+func appendExpressionTerms(terms []highlightTerm, node domain.QueryNode) []highlightTerm {
+	switch n := node.(type) {
+	case *domain.TermNode:
+		if n.Field.IsFilter() {
+			return terms
+		}
+		term := highlightTerm{column: queryFieldColumns[n.Field]}
+		if n.Wildcard {
+			term.pattern = likePattern(wildcardPattern(strings.ToLower(n.Value)))
+		} else if term.words = textutil.Tokenize(n.Value); len(term.words) == 0 {
+			return terms
+		}
+		return append(terms, term)
+	case *domain.PhraseNode:
+		if words := textutil.Tokenize(n.Text); len(words) > 0 {
+			return append(terms, highlightTerm{column: queryFieldColumns[n.Field], words: words})
+		}
+	case *domain.BoolNode:
+		for _, clause := range n.Clauses {
+			terms = appendExpressionTerms(terms, clause)
+		}
+	}
+	return terms
+}
+
+// highlightText returns the best passages of a text marking the terms, best first. Passages
+// are about the fragment size, cut at word boundaries and HTML-escaped except for the tags.
+func highlightText(text, column string, terms []highlightTerm, options domain.HighlightOptions) []string {
+	runes := []rune(text)
+	matches := findMatches(tokenizeRunes(runes), column, terms)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	candidates := make([]passage, 0, len(matches))
+	for _, match := range matches {
+		candidates = append(candidates, newPassage(runes, match, matches, options.FragmentSize))
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].start < candidates[j].start
+	})
+
+	selected := make([]passage, 0, options.FragmentCount)
+	for _, candidate := range candidates {
+		if len(selected) == options.FragmentCount {
+			break
+		}
+		overlaps := false
+		for _, chosen := range selected {
+			if candidate.start < chosen.end && chosen.start < candidate.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			selected = append(selected, candidate)
+		}
+	}
+
+	snippets := make([]string, 0, len(selected))
+	for _, p := range selected {
+		snippets = append(snippets, formatPassage(runes, p, options))
+	}
+	return snippets
+}
+
+// tokenizeRunes splits a text into lower-cased words of letters and digits, like textutil.Tokenize
+func tokenizeRunes(runes []rune) []highlightToken {
+	tokens := make([]highlightToken, 0)
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && isWordRune(runes[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, highlightToken{start: start, end: i, text: strings.ToLower(string(runes[start:i]))})
+			start = -1
+		}
+	}
+	return tokens
+}
+
+// findMatches returns the occurrences of the terms applying to the column, in text order and
+// without overlaps, preferring the longest occurrence at each position
+func findMatches(tokens []highlightToken, column string, terms []highlightTerm) []highlightMatch {
+	matches := make([]highlightMatch, 0)
+	for i := 0; i < len(tokens); {
+		best := highlightMatch{start: -1}
+		length := 0
+		for t, term := range terms {
+			if term.column != "" && term.column != column {
+				continue
+			}
+			n := term.matchAt(tokens, i)
+			if n > length {
+				length = n
+				best = highlightMatch{start: tokens[i].start, end: tokens[i+n-1].end, term: t}
+			}
+		}
+		if length == 0 {
+			i++
+			continue
+		}
+		matches = append(matches, best)
+		i += length
+	}
+	return matches
+}
+
+// matchAt returns the number of tokens the term matches from a position, or zero
+func (t highlightTerm) matchAt(tokens []highlightToken, i int) int {
+	if t.pattern != nil {
+		if t.pattern.MatchString(tokens[i].text) {
+			return 1
+		}
+		return 0
+	}
+	if i+len(t.words) > len(tokens) {
+		return 0
+	}
+	for j, word := range t.words {
+		if tokens[i+j].text != word {
+			return 0
+		}
+	}
+	return len(t.words)
+}
+
+// newPassage creates the passage of about size runes centered on a match, cut at word
+// boundaries, and scores it by its distinct terms, then its number of matches
+func newPassage(runes []rune, match highlightMatch, matches []highlightMatch, size int) passage {
+	start, end := match.start, match.end
+	if end-start < size {
+		start = max(0, match.start-(size-(match.end-match.start))/2)
+		end = min(len(runes), start+size)
+		start = max(0, end-size)
+	}
+	for start < match.start && start > 0 && isWordRune(runes[start-1]) && isWordRune(runes[start]) {
+		start++
+	}
+	for start < match.start && !isWordRune(runes[start]) {
+		start++
+	}
+	for end > match.end && end < len(runes) && isWordRune(runes[end-1]) && isWordRune(runes[end]) {
+		end--
+	}
+	for end > match.end && unicode.IsSpace(runes[end-1]) {
+		end--
+	}
+
+	p := passage{start: start, end: end}
+	terms := make(map[int]bool)
+	for _, m := range matches {
+		if m.start >= start && m.end <= end {
+			p.matches = append(p.matches, m)
+			terms[m.term] = true
+		}
+	}
+	p.score = len(terms)*len(matches) + len(p.matches)
+	return p
+}
+
+// formatPassage renders a passage with its matches surrounded by the tags
+func formatPassage(runes []rune, p passage, options domain.HighlightOptions) string {
+	var b strings.Builder
+	position := p.start
+	for _, m := range p.matches {
+		b.WriteString(html.EscapeString(string(runes[position:m.start])))
+		b.WriteString(options.PreTag)
+		b.WriteString(html.EscapeString(string(runes[m.start:m.end])))
+		b.WriteString(options.PostTag)
+		position = m.end
+	}
+	b.WriteString(html.EscapeString(string(runes[position:p.end])))
+	return b.String()
+}
+
+// isWordRune reports whether a rune is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
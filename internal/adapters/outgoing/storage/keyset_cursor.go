@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// keysetPosition is the sort key of the last row returned by a keyset paginated query
+type keysetPosition struct {
+	LastCrawled time.Time
+	ID          string
+}
+
+// encodeListCursor creates the cursor following the given document in list order
+func encodeListCursor(doc models.Document) string {
+	cursor := &domain.PageCursor{
+		SearchAfter: []interface{}{doc.LastCrawled.Format(time.RFC3339Nano), doc.ID},
+	}
+	return cursor.Encode()
+}
+
+// decodeListCursor parses a cursor created by encodeListCursor
+func decodeListCursor(token string) (*keysetPosition, error) {
+	values, err := cursorValues(token, 2)
+	if err != nil {
+		return nil, err
+	}
+	lastCrawled, err := cursorTime(values[0])
+	if err != nil {
+		return nil, err
+	}
+	id, ok := values[1].(string)
+	if !ok {
+		return nil, errors.New("malformed cursor")
+	}
+	return &keysetPosition{LastCrawled: lastCrawled, ID: id}, nil
+}
+
+// keysetKind is the type of the values of a keyset column, as carried by cursors
+type keysetKind int
+
+const (
+	keysetNumber keysetKind = iota
+	keysetTime
+	keysetText
+)
+
+// keysetKey is a column of the order walked by keyset paginated searches. The expression compares
+// rows with a cursor; the order column sorts them and may be a selected alias.
+type keysetKey struct {
+	expression string
+	args       []interface{}
+	order      string
+	desc       bool
+	kind       keysetKind
+	value      func(models.Document) interface{}
+}
+
+// keysetSortColumns are the columns searches can be keyset paginated by, with their values
+var keysetSortColumns = map[string]keysetKey{
+	"importance_rank": {kind: keysetNumber, value: func(d models.Document) interface{} { return d.ImportanceRank }},
+	"incoming_links":  {kind: keysetNumber, value: func(d models.Document) interface{} { return d.IncomingLinks }},
+	"outgoing_links":  {kind: keysetNumber, value: func(d models.Document) interface{} { return d.OutgoingLinks }},
+	"content_length":  {kind: keysetNumber, value: func(d models.Document) interface{} { return d.ContentLength }},
+	"last_crawled":    {kind: keysetTime, value: func(d models.Document) interface{} { return d.LastCrawled }},
+	"last_modified":   {kind: keysetTime, value: func(d models.Document) interface{} { return d.LastModified }},
+	"published_date":  {kind: keysetTime, value: func(d models.Document) interface{} { return d.PublishedDate }},
+	"title":           {kind: keysetText, value: func(d models.Document) interface{} { return d.Title }},
+	"url":             {kind: keysetText, value: func(d models.Document) interface{} { return d.URL }},
+	"domain":          {kind: keysetText, value: func(d models.Document) interface{} { return d.Domain }},
+	"lang":            {kind: keysetText, value: func(d models.Document) interface{} { return d.Lang }},
+	"content_type":    {kind: keysetText, value: func(d models.Document) interface{} { return d.ContentType }},
+}
+
+// keysetIDKey is the ID tiebreaker ending every keyset order
+func keysetIDKey(desc bool) keysetKey {
+	return keysetKey{expression: "id", order: "id", desc: desc, kind: keysetText, value: func(d models.Document) interface{} { return d.ID }}
+}
+
+// searchKeyset returns the keyset order of a search, the same as its offset paginated order with
+// the ID as tiebreaker: the sort fields, otherwise the text score, importance rank and crawl date
+func (d DocumentRepository) searchKeyset(query *domain.SearchQuery, text textQuery) ([]keysetKey, error) {
+	if len(query.SortFields) > 0 {
+		desc := query.SortOrder == domain.Descending
+		keys := make([]keysetKey, 0, len(query.SortFields)+1)
+		for _, field := range query.SortFields {
+			key, ok := keysetSortColumns[field]
+			if !ok {
+				return nil, fmt.Errorf("cursor pagination does not support sorting by %q", field)
+			}
+			key.expression, key.order, key.desc = field, field, desc
+			keys = append(keys, key)
+		}
+		return append(keys, keysetIDKey(desc)), nil
+	}
+
+	keys := make([]keysetKey, 0, 4)
+	if text.ranked() {
+		expression, args := d.scoreExpression(text)
+		keys = append(keys, keysetKey{
+			expression: expression, args: args, order: "text_score", desc: true, kind: keysetNumber,
+			value: func(d models.Document) interface{} { return d.TextScore },
+		})
+	}
+	for _, field := range []string{"importance_rank", "last_crawled"} {
+		key := keysetSortColumns[field]
+		key.expression, key.order, key.desc = field, field, true
+		keys = append(keys, key)
+	}
+	return append(keys, keysetIDKey(true)), nil
+}
+
+// applyKeyset orders a query by the keys and, given a cursor, restricts it to the rows after it
+func applyKeyset(db *gorm.DB, keys []keysetKey, cursor string) (*gorm.DB, error) {
+	if cursor != "" {
+		values, err := decodeSearchCursor(cursor, keys)
+		if err != nil {
+			return nil, err
+		}
+		// (k1 after v1) OR (k1 = v1 AND k2 after v2) OR ...
+		conditions := make([]string, 0, len(keys))
+		args := make([]interface{}, 0)
+		for i, key := range keys {
+			parts := make([]string, 0, i+1)
+			for j := 0; j < i; j++ {
+				parts = append(parts, keys[j].expression+" = ?")
+				args = append(append(args, keys[j].args...), values[j])
+			}
+			operator := ">"
+			if key.desc {
+				operator = "<"
+			}
+			parts = append(parts, key.expression+" "+operator+" ?")
+			args = append(append(args, key.args...), values[i])
+			conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+		}
+		db = db.Where(strings.Join(conditions, " OR "), args...)
+	}
+	for _, key := range keys {
+		direction := "ASC"
+		if key.desc {
+			direction = "DESC"
+		}
+		db = db.Order(key.order + " " + direction)
+	}
+	return db, nil
+}
+
+// encodeSearchCursor creates the cursor following the given document in the keyset order
+func encodeSearchCursor(doc models.Document, keys []keysetKey) string {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		value := key.value(doc)
+		if t, ok := value.(time.Time); ok {
+			value = t.Format(time.RFC3339Nano)
+		}
+		values[i] = value
+	}
+	cursor := &domain.PageCursor{SearchAfter: values}
+	return cursor.Encode()
+}
+
+// decodeSearchCursor parses a cursor created by encodeSearchCursor with the same keys
+func decodeSearchCursor(token string, keys []keysetKey) ([]interface{}, error) {
+	values, err := cursorValues(token, len(keys))
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range keys {
+		switch key.kind {
+		case keysetNumber:
+			number, ok := values[i].(json.Number)
+			if !ok {
+				return nil, errors.New("malformed cursor")
+			}
+			if values[i], err = number.Float64(); err != nil {
+				return nil, errors.New("malformed cursor")
+			}
+		case keysetTime:
+			if values[i], err = cursorTime(values[i]); err != nil {
+				return nil, err
+			}
+		default:
+			if _, ok := values[i].(string); !ok {
+				return nil, errors.New("malformed cursor")
+			}
+		}
+	}
+	return values, nil
+}
+
+// cursorValues decodes a cursor token and checks the number of sort values it carries
+func cursorValues(token string, expected int) ([]interface{}, error) {
+	cursor, err := domain.DecodePageCursor(token)
+	if err != nil {
+		return nil, err
+	}
+	if len(cursor.SearchAfter) != expected {
+		return nil, errors.New("malformed cursor")
+	}
+	return cursor.SearchAfter, nil
+}
+
+// cursorTime parses a timestamp sort value
+func cursorTime(value interface{}) (time.Time, error) {
+	text, ok := value.(string)
+	if !ok {
+		return time.Time{}, errors.New("malformed cursor")
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return time.Time{}, errors.New("malformed cursor")
+	}
+	return parsed, nil
+}
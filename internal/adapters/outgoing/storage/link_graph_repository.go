@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// linkScoreBatchSize is the number of documents whose link scores are updated per transaction
+const linkScoreBatchSize = 500
+
+// LinkGraphRepository implements the outgoing.LinkGraphRepository interface using GORM
+type LinkGraphRepository struct {
+	db *gorm.DB
+}
+
+// NewLinkGraphRepository creates a new link graph repository
+func NewLinkGraphRepository(client *Client) *LinkGraphRepository {
+	return &LinkGraphRepository{
+		db: client.DB,
+	}
+}
+
+// Ensure LinkGraphRepository implements the outgoing.LinkGraphRepository interface
+var _ outgoing.LinkGraphRepository = (*LinkGraphRepository)(nil)
+
+// ListDocumentLinks returns the documents of the index with the URLs they link to
+func (l *LinkGraphRepository) ListDocumentLinks(ctx context.Context, indexID string) ([]domain.DocumentLinks, error) {
+	if indexID == "" {
+		return nil, errors.New("index ID cannot be empty")
+	}
+
+	var documents []struct {
+		ID  string
+		URL string
+	}
+	if err := l.db.WithContext(ctx).
+		Model(&models.Document{}).
+		Select("id, url").
+		Where("index_id = ?", indexID).
+		Order("id").
+		Scan(&documents).Error; err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	var links []models.DocumentLink
+	if err := l.db.WithContext(ctx).
+		Where("source_id IN (?)", l.db.Model(&models.Document{}).Select("id").Where("index_id = ?", indexID)).
+		Order("id").
+		Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to list document links: %w", err)
+	}
+	targets := make(map[string][]string, len(documents))
+	for _, link := range links {
+		targets[link.SourceID] = append(targets[link.SourceID], link.TargetURL)
+	}
+
+	result := make([]domain.DocumentLinks, 0, len(documents))
+	for _, doc := range documents {
+		result = append(result, domain.DocumentLinks{
+			DocumentID: doc.ID,
+			URL:        doc.URL,
+			Links:      targets[doc.ID],
+		})
+	}
+	return result, nil
+}
+
+// UpdateLinkScores stores the importance rank and link counts of the documents
+func (l *LinkGraphRepository) UpdateLinkScores(ctx context.Context, scores []domain.LinkScore) error {
+	for start := 0; start < len(scores); start += linkScoreBatchSize {
+		batch := scores[start:min(start+linkScoreBatchSize, len(scores))]
+		err := l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for _, score := range batch {
+				if err := tx.Model(&models.Document{}).
+					Where("id = ?", score.DocumentID).
+					Updates(map[string]interface{}{
+						"importance_rank": score.ImportanceRank,
+						"incoming_links":  score.IncomingLinks,
+						"outgoing_links":  score.OutgoingLinks,
+					}).Error; err != nil {
+					return fmt.Errorf("failed to update link scores of document %s: %w", score.DocumentID, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
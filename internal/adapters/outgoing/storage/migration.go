@@ -31,6 +31,9 @@ func (m *MigrationHandler) RunMigrations() error {
 			}
 		}
 	}
+	if err := m.migrateFullText(); err != nil {
+		return err
+	}
 	log.Println("Database migrations completed successfully")
 	return nil
 }
@@ -38,6 +41,9 @@ func (m *MigrationHandler) RunMigrations() error {
 // ResetDatabase drops all tables and reruns migrations
 func (m *MigrationHandler) ResetDatabase() error {
 	log.Println("WARNING: Resetting database - all data will be lost")
+	if err := m.dropFullText(); err != nil {
+		return err
+	}
 	for _, dbModel := range m.models() {
 		if err := m.db.Migrator().DropTable(dbModel); err != nil {
 			return fmt.Errorf("failed to drop table for %T: %w", dbModel, err)
@@ -53,6 +59,16 @@ func (m *MigrationHandler) models() []interface{} {
 		&models.DocumentKeyword{},
 		&models.DocumentLink{},
 		&models.DocumentTag{},
+		&models.DocumentAccess{},
 		&models.Index{},
+		&models.QueryStat{},
+		&models.SuggestionStat{},
+		&models.SuggestionSelection{},
+		&models.QueryLog{},
+		&models.QueryLogResult{},
+		&models.ResultClick{},
+		&models.SavedSearch{},
+		&models.StandingQuery{},
+		&models.QueryRule{},
 	}
 }
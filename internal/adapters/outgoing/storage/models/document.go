@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
 )
@@ -13,24 +14,39 @@ import (
 type Document struct {
 	BaseModel
 	URL            string `gorm:"type:varchar(2048);uniqueIndex"`
+	Domain         string `gorm:"type:varchar(255);index"`
 	Title          string `gorm:"type:varchar(512)"`
 	Content        string `gorm:"type:text"`
 	ContentType    string `gorm:"type:varchar(100)"`
 	LastCrawled    time.Time
 	LastModified   time.Time
+	PublishedDate  time.Time
 	Lang           string `gorm:"type:varchar(10)"`
 	MetaDesc       string `gorm:"type:text"`
 	ContentLength  int
 	ImportanceRank float64
+	IncomingLinks  int
+	OutgoingLinks  int
 	IndexID        string `gorm:"type:varchar(36);index"`
+	IsDuplicate    bool
+	OriginalDocID  string `gorm:"type:varchar(36);index"`
+	Embedding      []byte
+	// TextScore is the full-text relevance selected by searches; it is not stored
+	TextScore float64 `gorm:"->;-:migration"`
+	// TitleScore, MetaDescScore and ContentScore are the relevance of each field selected by
+	// explained searches; they are not stored
+	TitleScore    float64 `gorm:"->;-:migration"`
+	MetaDescScore float64 `gorm:"->;-:migration"`
+	ContentScore  float64 `gorm:"->;-:migration"`
 
 	DocumentMetadata DocumentMetadata  `gorm:"foreignKey:DocumentID"`
 	DocumentLinks    []DocumentLink    `gorm:"foreignKey:SourceID"`
 	DocumentKeywords []DocumentKeyword `gorm:"foreignKey:DocumentID"`
+	DocumentAccess   []DocumentAccess  `gorm:"foreignKey:DocumentID"`
 }
 
 // BeforeCreate is a GORM hook that generates a UUID if ID is empty
-func (d *Document) BeforeCreate() (err error) {
+func (d *Document) BeforeCreate(tx *gorm.DB) (err error) {
 	if d.ID == "" {
 		d.ID = uuid.NewString()
 	}
@@ -47,11 +63,17 @@ func (d *Document) ToDomain() *domain.Document {
 		ContentType:    domain.ContentType(d.ContentType),
 		LastCrawled:    d.LastCrawled,
 		LastModified:   d.LastModified,
+		PublishedDate:  d.PublishedDate,
 		Lang:           d.Lang,
 		MetaDesc:       d.MetaDesc,
 		ContentLength:  d.ContentLength,
 		ImportanceRank: d.ImportanceRank,
+		IncomingLinks:  d.IncomingLinks,
+		OutgoingLinks:  d.OutgoingLinks,
 		IndexID:        d.IndexID,
+		IsDuplicate:    d.IsDuplicate,
+		OriginalDocID:  d.OriginalDocID,
+		Embedding:      DecodeEmbedding(d.Embedding),
 		StatusCode:     http.StatusOK,
 		MetaKeywords:   make([]string, 0),
 		Links:          make([]string, 0),
@@ -66,6 +88,17 @@ func (d *Document) ToDomain() *domain.Document {
 		doc.Links = append(doc.Links, link.TargetURL)
 	}
 
+	for _, entry := range d.DocumentAccess {
+		switch entry.Kind {
+		case AccessKindUser:
+			doc.AllowedUsers = append(doc.AllowedUsers, entry.Principal)
+		case AccessKindGroup:
+			doc.AllowedGroups = append(doc.AllowedGroups, entry.Principal)
+		case AccessKindRole:
+			doc.AllowedRoles = append(doc.AllowedRoles, entry.Principal)
+		}
+	}
+
 	return doc
 }
 
@@ -73,15 +106,22 @@ func (d *Document) ToDomain() *domain.Document {
 func (d *Document) FromDomain(doc *domain.Document) *Document {
 	d.ID = doc.ID
 	d.URL = doc.URL
+	d.Domain = doc.Host()
 	d.Title = doc.Title
 	d.Content = doc.Content
 	d.ContentType = string(doc.ContentType)
 	d.LastCrawled = doc.LastCrawled
 	d.LastModified = doc.LastModified
+	d.PublishedDate = doc.PublishedDate
 	d.Lang = doc.Lang
 	d.MetaDesc = doc.MetaDesc
 	d.ContentLength = doc.ContentLength
 	d.ImportanceRank = doc.ImportanceRank
+	d.IncomingLinks = doc.IncomingLinks
+	d.OutgoingLinks = doc.OutgoingLinks
 	d.IndexID = doc.IndexID
+	d.IsDuplicate = doc.IsDuplicate
+	d.OriginalDocID = doc.OriginalDocID
+	d.Embedding = EncodeEmbedding(doc.Embedding)
 	return d
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Kinds of principals of the access control entries of a document
+const (
+	AccessKindUser  = "user"
+	AccessKindGroup = "group"
+	AccessKindRole  = "role"
+)
+
+// DocumentAccess represents a principal allowed to read a document
+type DocumentAccess struct {
+	ID         uint `gorm:"primaryKey;autoIncrement"`
+	CreatedAt  time.Time
+	DocumentID string `gorm:"type:varchar(36);index"`
+	Kind       string `gorm:"type:varchar(10);index:idx_document_access_principal"`
+	Principal  string `gorm:"type:varchar(255);index:idx_document_access_principal"`
+}
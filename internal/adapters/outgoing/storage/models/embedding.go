@@ -0,0 +1,30 @@
+package models
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// EncodeEmbedding serializes an embedding as little-endian float32 values, or nil for an empty embedding
+func EncodeEmbedding(embedding []float32) []byte {
+	if len(embedding) == 0 {
+		return nil
+	}
+	data := make([]byte, 4*len(embedding))
+	for i, value := range embedding {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(value))
+	}
+	return data
+}
+
+// DecodeEmbedding deserializes an embedding encoded by EncodeEmbedding
+func DecodeEmbedding(data []byte) []float32 {
+	if len(data) == 0 || len(data)%4 != 0 {
+		return nil
+	}
+	embedding := make([]float32, len(data)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return embedding
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// QueryLog represents a single executed search
+type QueryLog struct {
+	ID          uint `gorm:"primaryKey;autoIncrement"`
+	CreatedAt   time.Time
+	QueryID     string `gorm:"type:varchar(36);uniqueIndex"`
+	Query       string `gorm:"type:varchar(255);index"`
+	FiltersJSON string `gorm:"type:text;column:filters"`
+	TotalHits   int
+	LatencyMs   int64
+	LoggedAt    time.Time `gorm:"index"`
+}
+
+// QueryLogFromDomain converts a domain log entry to the database model
+func QueryLogFromDomain(e *domain.QueryLogEntry) (*QueryLog, error) {
+	log := &QueryLog{
+		QueryID:   e.QueryID,
+		Query:     domain.NormalizeQuery(e.Query),
+		TotalHits: e.TotalHits,
+		LatencyMs: e.LatencyMs,
+		LoggedAt:  e.LoggedAt,
+	}
+	if len(e.Filters) > 0 {
+		filtersJSON, err := json.Marshal(e.Filters)
+		if err != nil {
+			return nil, err
+		}
+		log.FiltersJSON = string(filtersJSON)
+	}
+	return log, nil
+}
+
+// QueryLogResult represents a document returned by a logged search at its position
+type QueryLogResult struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	QueryID    string `gorm:"type:varchar(36);index"`
+	DocumentID string `gorm:"type:varchar(36);index"`
+	Position   int
+}
+
+// QueryLogResultsFromDomain converts the returned documents of a domain log entry to database
+// models, positioned by their rank across pages
+func QueryLogResultsFromDomain(e *domain.QueryLogEntry) []QueryLogResult {
+	results := make([]QueryLogResult, 0, len(e.DocumentIDs))
+	for i, documentID := range e.DocumentIDs {
+		results = append(results, QueryLogResult{
+			QueryID:    e.QueryID,
+			DocumentID: documentID,
+			Position:   e.Offset + i + 1,
+		})
+	}
+	return results
+}
+
+// ResultClick represents a click on a search result
+type ResultClick struct {
+	ID         uint `gorm:"primaryKey;autoIncrement"`
+	CreatedAt  time.Time
+	QueryID    string `gorm:"type:varchar(36);index"`
+	DocumentID string `gorm:"type:varchar(36);index"`
+	Position   int
+	ClickedAt  time.Time `gorm:"index"`
+}
+
+// ResultClickFromDomain converts a domain click to the database model
+func ResultClickFromDomain(c *domain.ResultClick) *ResultClick {
+	return &ResultClick{
+		QueryID:    c.QueryID,
+		DocumentID: c.DocumentID,
+		Position:   c.Position,
+		ClickedAt:  c.ClickedAt,
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// QueryRule represents a query rule of an index, with its pinned and buried document IDs as JSON
+type QueryRule struct {
+	ID          string `gorm:"type:varchar(36);primaryKey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	IndexID     string `gorm:"type:varchar(36);index"`
+	Name        string `gorm:"type:varchar(255)"`
+	MatchType   string `gorm:"type:varchar(20)"`
+	Pattern     string `gorm:"type:text"`
+	PinnedJSON  string `gorm:"type:text;column:pinned_ids"`
+	BuriedJSON  string `gorm:"type:text;column:buried_ids"`
+	RedirectURL string `gorm:"type:text"`
+	StartAt     time.Time
+	EndAt       time.Time
+}
+
+// QueryRuleFromDomain converts a domain query rule to the database model
+func QueryRuleFromDomain(r *domain.QueryRule) (*QueryRule, error) {
+	pinnedJSON, err := json.Marshal(r.PinnedIDs)
+	if err != nil {
+		return nil, err
+	}
+	buriedJSON, err := json.Marshal(r.BuriedIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &QueryRule{
+		ID:          r.ID,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+		IndexID:     r.IndexID,
+		Name:        r.Name,
+		MatchType:   string(r.MatchType),
+		Pattern:     r.Pattern,
+		PinnedJSON:  string(pinnedJSON),
+		BuriedJSON:  string(buriedJSON),
+		RedirectURL: r.RedirectURL,
+		StartAt:     r.StartAt,
+		EndAt:       r.EndAt,
+	}, nil
+}
+
+// ToDomain converts the database model to a domain query rule
+func (r *QueryRule) ToDomain() (*domain.QueryRule, error) {
+	rule := &domain.QueryRule{
+		ID:          r.ID,
+		IndexID:     r.IndexID,
+		Name:        r.Name,
+		MatchType:   domain.QueryRuleMatchType(r.MatchType),
+		Pattern:     r.Pattern,
+		RedirectURL: r.RedirectURL,
+		StartAt:     r.StartAt,
+		EndAt:       r.EndAt,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+	if err := json.Unmarshal([]byte(r.PinnedJSON), &rule.PinnedIDs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(r.BuriedJSON), &rule.BuriedIDs); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
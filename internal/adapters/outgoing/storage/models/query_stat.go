@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// QueryStat represents the usage statistics of a normalized search query
+type QueryStat struct {
+	ID         uint `gorm:"primaryKey;autoIncrement"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Query      string `gorm:"type:varchar(255);uniqueIndex"`
+	UseCount   int    `gorm:"index"`
+	LastUsedAt time.Time
+}
+
+// ToSuggestion converts the query statistics to a popular query suggestion
+func (q *QueryStat) ToSuggestion() domain.SearchSuggestion {
+	return domain.SearchSuggestion{
+		Text:         q.Query,
+		Source:       domain.SuggestionSourcePopular,
+		LastUsedTime: q.LastUsedAt,
+		UseCount:     q.UseCount,
+	}
+}
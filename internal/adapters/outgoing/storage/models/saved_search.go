@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// SavedSearch represents a saved search of a user, with its query and alert channels as JSON
+type SavedSearch struct {
+	ID           string `gorm:"type:varchar(36);primaryKey"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	UserID       string `gorm:"type:varchar(36);index"`
+	Name         string `gorm:"type:varchar(255)"`
+	QueryJSON    string `gorm:"type:text;column:query"`
+	ChannelsJSON string `gorm:"type:text;column:channels"`
+	LastRunAt    time.Time
+}
+
+// SavedSearchFromDomain converts a domain saved search to the database model
+func SavedSearchFromDomain(s *domain.SavedSearch) (*SavedSearch, error) {
+	queryJSON, err := json.Marshal(s.Query)
+	if err != nil {
+		return nil, err
+	}
+	channelsJSON, err := json.Marshal(s.Channels)
+	if err != nil {
+		return nil, err
+	}
+	return &SavedSearch{
+		ID:           s.ID,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+		UserID:       s.UserID,
+		Name:         s.Name,
+		QueryJSON:    string(queryJSON),
+		ChannelsJSON: string(channelsJSON),
+		LastRunAt:    s.LastRunAt,
+	}, nil
+}
+
+// ToDomain converts the database model to a domain saved search
+func (s *SavedSearch) ToDomain() (*domain.SavedSearch, error) {
+	search := &domain.SavedSearch{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		Name:      s.Name,
+		LastRunAt: s.LastRunAt,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+	if err := json.Unmarshal([]byte(s.QueryJSON), &search.Query); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(s.ChannelsJSON), &search.Channels); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// StandingQuery represents a standing query of the percolator, with its search query as JSON
+type StandingQuery struct {
+	ID        string `gorm:"type:varchar(36);primaryKey"`
+	CreatedAt time.Time
+	Name      string `gorm:"type:varchar(255)"`
+	QueryJSON string `gorm:"type:text;column:query"`
+}
+
+// StandingQueryFromDomain converts a domain standing query to the database model
+func StandingQueryFromDomain(q *domain.StandingQuery) (*StandingQuery, error) {
+	queryJSON, err := json.Marshal(q.Query)
+	if err != nil {
+		return nil, err
+	}
+	return &StandingQuery{
+		ID:        q.ID,
+		CreatedAt: q.CreatedAt,
+		Name:      q.Name,
+		QueryJSON: string(queryJSON),
+	}, nil
+}
+
+// ToDomain converts the database model to a domain standing query
+func (q *StandingQuery) ToDomain() (*domain.StandingQuery, error) {
+	query := &domain.StandingQuery{
+		ID:        q.ID,
+		Name:      q.Name,
+		CreatedAt: q.CreatedAt,
+	}
+	if err := json.Unmarshal([]byte(q.QueryJSON), &query.Query); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
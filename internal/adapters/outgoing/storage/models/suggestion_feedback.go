@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// SuggestionStat represents the aggregated feedback of a normalized suggestion
+type SuggestionStat struct {
+	ID             uint `gorm:"primaryKey;autoIncrement"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Text           string `gorm:"type:varchar(255);uniqueIndex"`
+	Impressions    int
+	Selections     int `gorm:"index"`
+	PositionSum    int64
+	LastShownAt    time.Time
+	LastSelectedAt time.Time
+}
+
+// ToDomain converts the database model to domain suggestion statistics
+func (s *SuggestionStat) ToDomain() domain.SuggestionStats {
+	stats := domain.SuggestionStats{
+		Text:           s.Text,
+		Impressions:    s.Impressions,
+		Selections:     s.Selections,
+		LastShownAt:    s.LastShownAt,
+		LastSelectedAt: s.LastSelectedAt,
+	}
+	if s.Selections > 0 {
+		stats.AvgPosition = float64(s.PositionSum) / float64(s.Selections)
+	}
+	return stats
+}
+
+// SuggestionSelection represents a single selection of a suggestion
+type SuggestionSelection struct {
+	ID           uint `gorm:"primaryKey;autoIncrement"`
+	CreatedAt    time.Time
+	Text         string `gorm:"type:varchar(255);index"`
+	Source       string `gorm:"type:varchar(20)"`
+	PartialQuery string `gorm:"type:varchar(255)"`
+	Position     int
+	TimeTakenMs  int64
+	SelectedAt   time.Time `gorm:"index"`
+}
+
+// SuggestionSelectionFromDomain converts a domain selection to the database model
+func SuggestionSelectionFromDomain(s *domain.SuggestionSelection) *SuggestionSelection {
+	return &SuggestionSelection{
+		Text:         domain.NormalizeQuery(s.Text),
+		Source:       string(s.Source),
+		PartialQuery: s.PartialQuery,
+		Position:     s.Position,
+		TimeTakenMs:  s.TimeTakenMs,
+		SelectedAt:   s.SelectedAt,
+	}
+}
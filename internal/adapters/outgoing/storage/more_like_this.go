@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// relatedCandidateLimit is the number of documents sharing the most keywords with a document
+// that are compared to it
+const relatedCandidateLimit = 200
+
+// sharedKeywordRow is a document with the number of keywords it shares with another
+type sharedKeywordRow struct {
+	DocumentID string
+	Shared     int
+}
+
+// keywordCountRow is a keyword with the number of documents having it
+type keywordCountRow struct {
+	Keyword   string
+	Documents int
+}
+
+// MoreLikeThis returns the documents of the same index whose keyword vectors are closest to the
+// document's. Keywords are weighted by their inverse document frequency and documents compared
+// by cosine similarity. Duplicates and documents at the same URL are excluded.
+func (d DocumentRepository) MoreLikeThis(ctx context.Context, document *domain.Document, limit int) ([]*domain.Document, error) {
+	if document == nil {
+		return nil, errors.New("document cannot be nil")
+	}
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	keywords := uniqueKeywords(document.MetaKeywords)
+	if len(keywords) == 0 {
+		return []*domain.Document{}, nil
+	}
+
+	db := d.db.WithContext(ctx).
+		Table("document_keywords").
+		Select("document_keywords.document_id, COUNT(*) AS shared").
+		Joins("JOIN documents ON documents.id = document_keywords.document_id AND documents.deleted_at IS NULL").
+		Where("document_keywords.keyword IN ?", keywords).
+		Where("documents.id <> ? AND documents.url <> ? AND documents.is_duplicate = ?", document.ID, document.URL, false)
+	if document.OriginalDocID != "" {
+		db = db.Where("documents.id <> ?", document.OriginalDocID)
+	}
+	if document.IndexID != "" {
+		db = db.Where("documents.index_id = ?", document.IndexID)
+	}
+	db = applyDocumentAccess(db, domain.AccessScopeFromContext(ctx))
+	var candidates []sharedKeywordRow
+	if err := db.Group("document_keywords.document_id").
+		Order("shared DESC").
+		Limit(relatedCandidateLimit).
+		Scan(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to find documents sharing keywords: %w", err)
+	}
+	if len(candidates) == 0 {
+		return []*domain.Document{}, nil
+	}
+
+	ids := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.DocumentID
+	}
+	var rows []models.DocumentKeyword
+	if err := d.db.WithContext(ctx).
+		Select("document_id, keyword").
+		Where("document_id IN ?", ids).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load candidate keywords: %w", err)
+	}
+	vectors := make(map[string][]string, len(candidates))
+	for _, row := range rows {
+		vectors[row.DocumentID] = append(vectors[row.DocumentID], row.Keyword)
+	}
+
+	idf, err := d.keywordIDF(ctx, keywords, rows)
+	if err != nil {
+		return nil, err
+	}
+	source := keywordVector(keywords, idf)
+	matches := make([]vectorMatch, 0, limit+1)
+	for _, id := range ids {
+		similarity := keywordSimilarity(source, keywordVector(uniqueKeywords(vectors[id]), idf))
+		if similarity > 0 {
+			matches = insertMatch(matches, vectorMatch{id, similarity}, limit)
+		}
+	}
+	return d.loadMatches(ctx, matches)
+}
+
+// keywordIDF returns the inverse document frequency of the keywords of the document and of
+// its candidates
+func (d DocumentRepository) keywordIDF(ctx context.Context, keywords []string, rows []models.DocumentKeyword) (map[string]float64, error) {
+	all := append([]string{}, keywords...)
+	for _, row := range rows {
+		all = append(all, row.Keyword)
+	}
+	all = uniqueKeywords(all)
+
+	var total int64
+	if err := d.db.WithContext(ctx).Model(&models.Document{}).Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+	var counts []keywordCountRow
+	if err := d.db.WithContext(ctx).
+		Model(&models.DocumentKeyword{}).
+		Select("keyword, COUNT(DISTINCT document_id) AS documents").
+		Where("keyword IN ?", all).
+		Group("keyword").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count keyword frequencies: %w", err)
+	}
+	idf := make(map[string]float64, len(counts))
+	for _, count := range counts {
+		idf[count.Keyword] = math.Log(1 + float64(total)/float64(max(count.Documents, 1)))
+	}
+	return idf, nil
+}
+
+// uniqueKeywords returns the non-empty keywords without duplicates, in their order
+func uniqueKeywords(keywords []string) []string {
+	seen := make(map[string]bool, len(keywords))
+	unique := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if keyword == "" || seen[keyword] {
+			continue
+		}
+		seen[keyword] = true
+		unique = append(unique, keyword)
+	}
+	return unique
+}
+
+// keywordVector weights each keyword by its inverse document frequency
+func keywordVector(keywords []string, idf map[string]float64) map[string]float64 {
+	vector := make(map[string]float64, len(keywords))
+	for _, keyword := range keywords {
+		vector[keyword] = idf[keyword]
+	}
+	return vector
+}
+
+// keywordSimilarity returns the cosine similarity of two keyword vectors
+func keywordSimilarity(a, b map[string]float64) float64 {
+	dot, normA, normB := 0.0, 0.0, 0.0
+	for keyword, weight := range a {
+		dot += weight * b[keyword]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if dot == 0 || normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// Percolator implements the outgoing.Percolator interface with standing queries stored in the
+// database and matched in process. Documents match a standing query when a search would
+// return them, with the text matched by case-insensitive substrings.
+type Percolator struct {
+	db *gorm.DB
+}
+
+// NewPercolator creates a new percolator
+func NewPercolator(client *Client) *Percolator {
+	return &Percolator{
+		db: client.DB,
+	}
+}
+
+// Ensure Percolator implements the outgoing.Percolator interface
+var _ outgoing.Percolator = (*Percolator)(nil)
+
+// Register stores a standing query
+func (p *Percolator) Register(ctx context.Context, query *domain.StandingQuery) error {
+	if query == nil {
+		return errors.New("standing query cannot be nil")
+	}
+	if query.ID == "" {
+		return errors.New("standing query ID cannot be empty")
+	}
+	dbQuery, err := models.StandingQueryFromDomain(query)
+	if err != nil {
+		return fmt.Errorf("failed to convert domain model to database model: %w", err)
+	}
+	if err := p.db.WithContext(ctx).Save(dbQuery).Error; err != nil {
+		return fmt.Errorf("failed to save standing query: %w", err)
+	}
+	return nil
+}
+
+// Unregister deletes a standing query by ID
+func (p *Percolator) Unregister(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("standing query ID cannot be empty")
+	}
+	result := p.db.WithContext(ctx).Delete(&models.StandingQuery{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete standing query: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("standing query %s not found", id)
+	}
+	return nil
+}
+
+// List returns every standing query, oldest first
+func (p *Percolator) List(ctx context.Context) ([]*domain.StandingQuery, error) {
+	var dbQueries []models.StandingQuery
+	if err := p.db.WithContext(ctx).Order("created_at, id").Find(&dbQueries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list standing queries: %w", err)
+	}
+	queries := make([]*domain.StandingQuery, 0, len(dbQueries))
+	for i := range dbQueries {
+		query, err := dbQueries[i].ToDomain()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert database model to domain model: %w", err)
+		}
+		queries = append(queries, query)
+	}
+	return queries, nil
+}
+
+// Percolate returns the standing queries matching the document. The queries are read on every
+// call so that those registered by other processes apply right away.
+func (p *Percolator) Percolate(ctx context.Context, document *domain.Document) ([]*domain.StandingQuery, error) {
+	if document == nil {
+		return nil, errors.New("document cannot be nil")
+	}
+	queries, err := p.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*domain.StandingQuery, 0)
+	for _, query := range queries {
+		compiled, err := query.Compiled()
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile standing query %s: %w", query.ID, err)
+		}
+		if !matchesFilters(document, compiled) {
+			continue
+		}
+		matched, err := p.matchesText(ctx, document, compiled)
+		if err != nil {
+			return nil, fmt.Errorf("failed to match standing query %s: %w", query.ID, err)
+		}
+		if matched {
+			matches = append(matches, query)
+		}
+	}
+	return matches, nil
+}
+
+// matchesText reports whether the document matches the text of a query the way a search does:
+// by the syntax tree of a parsed query string, or else by every group of its analyzed terms
+func (p *Percolator) matchesText(ctx context.Context, document *domain.Document, query *domain.SearchQuery) (bool, error) {
+	if query.UseQuerySyntax {
+		return query.Expression == nil || matchesExpression(document, query.Expression), nil
+	}
+	groups, err := analyzeQueryGroups(ctx, p.db, query)
+	if err != nil {
+		return false, err
+	}
+	if len(groups) == 0 {
+		return matchesTerm(document, domain.AnyField, query.Query, false), nil
+	}
+	for _, group := range groups {
+		if !matchesGroup(document, group) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
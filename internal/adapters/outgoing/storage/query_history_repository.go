@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// maxRecordedQueryLength is the length of the longest query kept in the history
+const maxRecordedQueryLength = 255
+
+// QueryHistoryRepository implements the outgoing.QueryHistoryRepository interface using GORM
+type QueryHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewQueryHistoryRepository creates a new query history repository
+func NewQueryHistoryRepository(client *Client) *QueryHistoryRepository {
+	return &QueryHistoryRepository{
+		db: client.DB,
+	}
+}
+
+// Ensure QueryHistoryRepository implements the outgoing.QueryHistoryRepository interface
+var _ outgoing.QueryHistoryRepository = (*QueryHistoryRepository)(nil)
+
+// RecordQuery increments the use count of a query and updates its last use time
+func (q *QueryHistoryRepository) RecordQuery(ctx context.Context, query string, usedAt time.Time) error {
+	normalized := domain.NormalizeQuery(query)
+	if normalized == "" {
+		return errors.New("query cannot be empty")
+	}
+	if len(normalized) > maxRecordedQueryLength {
+		return nil
+	}
+
+	stat := models.QueryStat{
+		Query:      normalized,
+		UseCount:   1,
+		LastUsedAt: usedAt,
+	}
+	err := q.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "query"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"use_count":    gorm.Expr("use_count + ?", 1),
+			"last_used_at": usedAt,
+			"updated_at":   time.Now(),
+		}),
+	}).Create(&stat).Error
+	if err != nil {
+		return fmt.Errorf("failed to record query: %w", err)
+	}
+	return nil
+}
+
+// PopularQueries returns the most used queries starting with the prefix
+func (q *QueryHistoryRepository) PopularQueries(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+
+	var stats []models.QueryStat
+	if err := q.db.WithContext(ctx).
+		Where("query"+likeCondition, escapeLike(domain.NormalizeQuery(prefix))+"%").
+		Order("use_count DESC, last_used_at DESC").
+		Limit(limit).
+		Find(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to get popular queries: %w", err)
+	}
+
+	suggestions := make([]domain.SearchSuggestion, 0, len(stats))
+	for i := range stats {
+		suggestions = append(suggestions, stats[i].ToSuggestion())
+	}
+	return suggestions, nil
+}
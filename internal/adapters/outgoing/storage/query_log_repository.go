@@ -0,0 +1,357 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// sqliteTimeLayouts are the formats SQLite returns times computed by aggregates in
+var sqliteTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// QueryLogRepository implements the outgoing.QueryLogRepository interface using GORM
+type QueryLogRepository struct {
+	db *gorm.DB
+}
+
+// NewQueryLogRepository creates a new query log repository
+func NewQueryLogRepository(client *Client) *QueryLogRepository {
+	return &QueryLogRepository{
+		db: client.DB,
+	}
+}
+
+// Ensure QueryLogRepository implements the outgoing.QueryLogRepository interface
+var _ outgoing.QueryLogRepository = (*QueryLogRepository)(nil)
+
+// queryCountRow is a query with its number of searches
+type queryCountRow struct {
+	Query      string
+	UseCount   int
+	LastUsedAt aggregateTime
+}
+
+// documentCountRow is a document with a number of occurrences
+type documentCountRow struct {
+	DocumentID string
+	Count      int
+}
+
+// aggregateTime scans a time computed by an aggregate, which SQLite returns as text
+type aggregateTime struct {
+	time.Time
+}
+
+// Scan implements the sql.Scanner interface
+func (t *aggregateTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		t.Time = time.Time{}
+		return nil
+	case time.Time:
+		t.Time = v
+		return nil
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	}
+	return fmt.Errorf("cannot scan %T into a time", value)
+}
+
+// Value implements the driver.Valuer interface
+func (t aggregateTime) Value() (driver.Value, error) {
+	return t.Time, nil
+}
+
+// parse reads a time in one of the SQLite formats
+func (t *aggregateTime) parse(value string) error {
+	for _, layout := range sqliteTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid time %q", value)
+}
+
+// LogQuery stores an executed search. Queries too long for the log are stored without their text.
+func (q *QueryLogRepository) LogQuery(ctx context.Context, entry *domain.QueryLogEntry) error {
+	if entry == nil {
+		return errors.New("query log entry cannot be nil")
+	}
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("invalid query log entry: %w", err)
+	}
+	log, err := models.QueryLogFromDomain(entry)
+	if err != nil {
+		return fmt.Errorf("failed to convert domain model to database model: %w", err)
+	}
+	if len(log.Query) > maxRecordedQueryLength {
+		log.Query = ""
+	}
+	results := models.QueryLogResultsFromDomain(entry)
+	return q.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(log).Error; err != nil {
+			return fmt.Errorf("failed to log query: %w", err)
+		}
+		if len(results) > 0 {
+			if err := tx.Create(&results).Error; err != nil {
+				return fmt.Errorf("failed to log query results: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// LogClick stores a click on a search result
+func (q *QueryLogRepository) LogClick(ctx context.Context, click *domain.ResultClick) error {
+	if click == nil {
+		return errors.New("result click cannot be nil")
+	}
+	if err := q.db.WithContext(ctx).Create(models.ResultClickFromDomain(click)).Error; err != nil {
+		return fmt.Errorf("failed to log result click: %w", err)
+	}
+	return nil
+}
+
+// CountQueries returns the number of searches logged within the window
+func (q *QueryLogRepository) CountQueries(ctx context.Context, window domain.AnalyticsWindow) (int, error) {
+	var count int64
+	if err := q.inWindow(ctx, window).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count queries: %w", err)
+	}
+	return int(count), nil
+}
+
+// TopQueries returns the queries searched most often within the window, optionally only
+// among the searches without results
+func (q *QueryLogRepository) TopQueries(ctx context.Context, window domain.AnalyticsWindow, zeroResultsOnly bool, limit int) ([]domain.QueryCount, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	db := q.inWindow(ctx, window).Where("query <> ''")
+	if zeroResultsOnly {
+		db = db.Where("total_hits = 0")
+	}
+
+	var rows []queryCountRow
+	if err := db.
+		Select("query, COUNT(*) AS use_count").
+		Group("query").
+		Order("use_count DESC, query ASC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list top queries: %w", err)
+	}
+
+	counts := make([]domain.QueryCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, domain.QueryCount{Query: row.Query, Count: row.UseCount})
+	}
+	return counts, nil
+}
+
+// ClickStats returns the number of result clicks within the window and their average position
+func (q *QueryLogRepository) ClickStats(ctx context.Context, window domain.AnalyticsWindow) (domain.ClickStats, error) {
+	var row struct {
+		Clicks      int
+		AvgPosition *float64
+	}
+	if err := q.db.WithContext(ctx).
+		Model(&models.ResultClick{}).
+		Select("COUNT(*) AS clicks, AVG(position) AS avg_position").
+		Where("clicked_at >= ? AND clicked_at < ?", window.From, window.To).
+		Scan(&row).Error; err != nil {
+		return domain.ClickStats{}, fmt.Errorf("failed to aggregate result clicks: %w", err)
+	}
+
+	stats := domain.ClickStats{Clicks: row.Clicks}
+	if row.AvgPosition != nil {
+		stats.AvgPosition = *row.AvgPosition
+	}
+	return stats, nil
+}
+
+// LatencyPercentiles returns the nearest-rank percentiles of the search latencies within the
+// window, in milliseconds. All percentiles are zero when nothing was searched.
+func (q *QueryLogRepository) LatencyPercentiles(ctx context.Context, window domain.AnalyticsWindow, percents []float64) ([]float64, error) {
+	var count int64
+	if err := q.inWindow(ctx, window).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count queries: %w", err)
+	}
+
+	latencies := make([]float64, len(percents))
+	if count == 0 {
+		return latencies, nil
+	}
+	for i, percent := range percents {
+		rank := int(math.Ceil(percent / 100 * float64(count)))
+		rank = min(max(rank, 1), int(count))
+
+		var latency int64
+		if err := q.inWindow(ctx, window).
+			Select("latency_ms").
+			Order("latency_ms ASC").
+			Offset(rank - 1).
+			Limit(1).
+			Scan(&latency).Error; err != nil {
+			return nil, fmt.Errorf("failed to compute latency percentile: %w", err)
+		}
+		latencies[i] = float64(latency)
+	}
+	return latencies, nil
+}
+
+// PopularQueries returns the logged queries starting with the prefix that found results,
+// most searched first
+func (q *QueryLogRepository) PopularQueries(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+
+	var rows []queryCountRow
+	if err := q.db.WithContext(ctx).
+		Model(&models.QueryLog{}).
+		Select("query, COUNT(*) AS use_count, MAX(logged_at) AS last_used_at").
+		Where("query"+likeCondition+" AND query <> '' AND total_hits > 0", escapeLike(domain.NormalizeQuery(prefix))+"%").
+		Group("query").
+		Order("use_count DESC, last_used_at DESC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get popular queries: %w", err)
+	}
+
+	suggestions := make([]domain.SearchSuggestion, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, domain.SearchSuggestion{
+			Text:         row.Query,
+			Source:       domain.SuggestionSourcePopular,
+			LastUsedTime: row.LastUsedAt.Time,
+			UseCount:     row.UseCount,
+		})
+	}
+	return suggestions, nil
+}
+
+// DocumentClickStats returns how often each of the documents was returned and clicked across
+// all logged searches. Documents never returned are absent from the map.
+func (q *QueryLogRepository) DocumentClickStats(ctx context.Context, documentIDs []string) (map[string]domain.DocumentClickStats, error) {
+	stats := make(map[string]domain.DocumentClickStats, len(documentIDs))
+	if len(documentIDs) == 0 {
+		return stats, nil
+	}
+
+	var impressions []documentCountRow
+	if err := q.db.WithContext(ctx).
+		Model(&models.QueryLogResult{}).
+		Select("document_id, COUNT(*) AS count").
+		Where("document_id IN ?", documentIDs).
+		Group("document_id").
+		Scan(&impressions).Error; err != nil {
+		return nil, fmt.Errorf("failed to count document impressions: %w", err)
+	}
+	for _, row := range impressions {
+		stats[row.DocumentID] = domain.DocumentClickStats{DocumentID: row.DocumentID, Impressions: row.Count}
+	}
+
+	var clicks []documentCountRow
+	if err := q.db.WithContext(ctx).
+		Model(&models.ResultClick{}).
+		Select("document_id, COUNT(*) AS count").
+		Where("document_id IN ?", documentIDs).
+		Group("document_id").
+		Scan(&clicks).Error; err != nil {
+		return nil, fmt.Errorf("failed to count document clicks: %w", err)
+	}
+	for _, row := range clicks {
+		stat := stats[row.DocumentID]
+		stat.DocumentID = row.DocumentID
+		stat.Clicks = row.Count
+		// Clicks on results logged before impressions were recorded still count as seen
+		stat.Impressions = max(stat.Impressions, stat.Clicks)
+		stats[row.DocumentID] = stat
+	}
+	return stats, nil
+}
+
+// ClickedSearches returns the most recent searches of the window with at least one clicked
+// result, along with the documents they returned and the documents clicked
+func (q *QueryLogRepository) ClickedSearches(ctx context.Context, window domain.AnalyticsWindow, limit int) ([]domain.ClickedSearch, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+
+	var logs []models.QueryLog
+	if err := q.inWindow(ctx, window).
+		Where("query <> ''").
+		Where("query_id IN (?)", q.db.Model(&models.ResultClick{}).Select("query_id")).
+		Order("logged_at DESC").
+		Limit(limit).
+		Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list clicked searches: %w", err)
+	}
+	if len(logs) == 0 {
+		return []domain.ClickedSearch{}, nil
+	}
+
+	queryIDs := make([]string, 0, len(logs))
+	for _, log := range logs {
+		queryIDs = append(queryIDs, log.QueryID)
+	}
+	var results []models.QueryLogResult
+	if err := q.db.WithContext(ctx).
+		Where("query_id IN ?", queryIDs).
+		Order("position ASC").
+		Find(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to load query results: %w", err)
+	}
+	var clicks []models.ResultClick
+	if err := q.db.WithContext(ctx).
+		Where("query_id IN ?", queryIDs).
+		Order("clicked_at ASC").
+		Find(&clicks).Error; err != nil {
+		return nil, fmt.Errorf("failed to load result clicks: %w", err)
+	}
+
+	returned := make(map[string][]string, len(logs))
+	for _, result := range results {
+		returned[result.QueryID] = append(returned[result.QueryID], result.DocumentID)
+	}
+	clicked := make(map[string][]string, len(logs))
+	for _, click := range clicks {
+		clicked[click.QueryID] = append(clicked[click.QueryID], click.DocumentID)
+	}
+
+	searches := make([]domain.ClickedSearch, 0, len(logs))
+	for _, log := range logs {
+		searches = append(searches, domain.ClickedSearch{
+			QueryID:     log.QueryID,
+			Query:       log.Query,
+			DocumentIDs: returned[log.QueryID],
+			Clicked:     clicked[log.QueryID],
+		})
+	}
+	return searches, nil
+}
+
+// inWindow selects the searches logged within the window
+func (q *QueryLogRepository) inWindow(ctx context.Context, window domain.AnalyticsWindow) *gorm.DB {
+	return q.db.WithContext(ctx).
+		Model(&models.QueryLog{}).
+		Where("logged_at >= ? AND logged_at < ?", window.From, window.To)
+}
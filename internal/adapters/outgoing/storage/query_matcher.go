@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// The functions of this file evaluate a search query against a single document in process,
+// mirroring the SQL conditions of buildSearchQuery, expressionCondition and applyFilters. Like
+// patterns match case-insensitively, as they do in SQLite.
+
+// matchesFilters reports whether the document passes the filters and time range of the query
+func matchesFilters(document *domain.Document, query *domain.SearchQuery) bool {
+	switch indexID := query.Filters["index_id"].(type) {
+	case string:
+		if indexID != "" && document.IndexID != indexID {
+			return false
+		}
+	case []string:
+		if len(indexID) > 0 && !slices.Contains(indexID, document.IndexID) {
+			return false
+		}
+	}
+	if contentType, ok := query.Filters["content_type"].(string); ok && contentType != "" {
+		if string(document.ContentType) != contentType {
+			return false
+		}
+	}
+
+	if query.Language != "" && document.Lang != query.Language {
+		return false
+	}
+
+	if query.TimeRange != nil && !query.TimeRange.From.IsZero() {
+		field := "last_crawled"
+		if query.TimeRange.Field != "" {
+			field = query.TimeRange.Field
+		}
+		value, ok := documentTime(document, field)
+		if !ok || value.Before(query.TimeRange.From) {
+			return false
+		}
+		if !query.TimeRange.To.IsZero() && value.After(query.TimeRange.To) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesExpression evaluates the syntax tree of a parsed query string against the document
+func matchesExpression(document *domain.Document, node domain.QueryNode) bool {
+	switch n := node.(type) {
+	case *domain.TermNode:
+		return matchesTerm(document, n.Field, n.Value, n.Wildcard)
+	case *domain.PhraseNode:
+		return matchesTerm(document, n.Field, n.Text, false)
+	case *domain.RangeNode:
+		value, ok := documentTime(document, queryFieldColumns[n.Field])
+		if !ok {
+			return false
+		}
+		return (n.From.IsZero() || !value.Before(n.From)) && (n.To.IsZero() || value.Before(n.To))
+	case *domain.NotNode:
+		return !matchesExpression(document, n.Clause)
+	case *domain.BoolNode:
+		for _, clause := range n.Clauses {
+			matched := matchesExpression(document, clause)
+			if n.Operator == domain.OrOperator && matched {
+				return true
+			}
+			if n.Operator != domain.OrOperator && !matched {
+				return false
+			}
+		}
+		return n.Operator != domain.OrOperator
+	}
+	return true
+}
+
+// matchesTerm reports whether a field of the document holds a value, like termCondition: text
+// fields contain it, filter fields equal it and a site also matches its subdomains
+func matchesTerm(document *domain.Document, field domain.QueryField, value string, wildcard bool) bool {
+	if field.IsFilter() {
+		column := documentColumn(document, queryFieldColumns[field])
+		if wildcard {
+			return likePattern(wildcardPattern(value)).MatchString(column)
+		}
+		if field == domain.SiteField {
+			return column == value || strings.HasSuffix(column, "."+value)
+		}
+		return column == value
+	}
+
+	columns := textSearchColumns
+	if column, ok := queryFieldColumns[field]; ok {
+		columns = []string{column}
+	}
+	var pattern *regexp.Regexp
+	if wildcard {
+		pattern = likePattern("%" + wildcardPattern(value) + "%")
+	}
+	for _, column := range columns {
+		text := documentColumn(document, column)
+		if pattern != nil && pattern.MatchString(text) {
+			return true
+		}
+		if pattern == nil && strings.Contains(strings.ToLower(text), strings.ToLower(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesGroup reports whether the document contains one of the phrases of a group of analyzed terms
+func matchesGroup(document *domain.Document, group []string) bool {
+	for _, phrase := range group {
+		if matchesTerm(document, domain.AnyField, phrase, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// likePattern compiles a LIKE pattern escaped with likeEscape into a case-insensitive regular expression
+func likePattern(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	escaped := false
+	for _, r := range pattern {
+		if escaped {
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+			continue
+		}
+		switch r {
+		case likeEscape:
+			escaped = true
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// documentColumn returns the value of a text column of the document
+func documentColumn(document *domain.Document, column string) string {
+	switch column {
+	case "title":
+		return document.Title
+	case "content":
+		return document.Content
+	case "meta_desc":
+		return document.MetaDesc
+	case "url":
+		return document.URL
+	case "domain":
+		return document.Host()
+	case "lang":
+		return document.Lang
+	case "content_type":
+		return string(document.ContentType)
+	}
+	return ""
+}
+
+// documentTime returns the value of a date column of the document
+func documentTime(document *domain.Document, column string) (time.Time, bool) {
+	switch column {
+	case "last_crawled":
+		return document.LastCrawled, true
+	case "last_modified":
+		return document.LastModified, true
+	case "published_date":
+		return document.PublishedDate, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// QueryRuleRepository implements the outgoing.QueryRuleRepository interface using GORM
+type QueryRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewQueryRuleRepository creates a new query rule repository
+func NewQueryRuleRepository(client *Client) *QueryRuleRepository {
+	return &QueryRuleRepository{
+		db: client.DB,
+	}
+}
+
+// Ensure QueryRuleRepository implements the outgoing.QueryRuleRepository interface
+var _ outgoing.QueryRuleRepository = (*QueryRuleRepository)(nil)
+
+// Save creates or replaces a query rule
+func (q *QueryRuleRepository) Save(ctx context.Context, rule *domain.QueryRule) error {
+	if rule == nil {
+		return errors.New("query rule cannot be nil")
+	}
+	if rule.ID == "" {
+		return errors.New("query rule ID cannot be empty")
+	}
+	dbRule, err := models.QueryRuleFromDomain(rule)
+	if err != nil {
+		return fmt.Errorf("failed to convert domain model to database model: %w", err)
+	}
+	if err := q.db.WithContext(ctx).Save(dbRule).Error; err != nil {
+		return fmt.Errorf("failed to save query rule: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a query rule by ID, or nil when it does not exist
+func (q *QueryRuleRepository) GetByID(ctx context.Context, id string) (*domain.QueryRule, error) {
+	if id == "" {
+		return nil, errors.New("query rule ID cannot be empty")
+	}
+	var dbRule models.QueryRule
+	if err := q.db.WithContext(ctx).First(&dbRule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get query rule by ID: %w", err)
+	}
+	rule, err := dbRule.ToDomain()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert database model to domain model: %w", err)
+	}
+	return rule, nil
+}
+
+// ListByIndex returns the query rules of an index, oldest first
+func (q *QueryRuleRepository) ListByIndex(ctx context.Context, indexID string) ([]*domain.QueryRule, error) {
+	if indexID == "" {
+		return nil, errors.New("index ID cannot be empty")
+	}
+	var dbRules []models.QueryRule
+	if err := q.db.WithContext(ctx).Where("index_id = ?", indexID).Order("created_at, id").Find(&dbRules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list query rules: %w", err)
+	}
+	rules := make([]*domain.QueryRule, 0, len(dbRules))
+	for i := range dbRules {
+		rule, err := dbRules[i].ToDomain()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert database model to domain model: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Delete deletes a query rule by ID
+func (q *QueryRuleRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("query rule ID cannot be empty")
+	}
+	result := q.db.WithContext(ctx).Delete(&models.QueryRule{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete query rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("query rule %s not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"strings"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// queryFieldColumns maps the fields of the query syntax to their document columns
+var queryFieldColumns = map[domain.QueryField]string{
+	domain.TitleField:   "title",
+	domain.ContentField: "content",
+	domain.URLField:     "url",
+	domain.SiteField:    "domain",
+	domain.LangField:    "lang",
+	domain.TypeField:    "content_type",
+	domain.CrawledField: "last_crawled",
+}
+
+// textSearchColumns are the columns matched by unscoped terms and phrases
+var textSearchColumns = []string{"title", "content", "meta_desc"}
+
+// likeEscape is the escape character of the LIKE patterns built from user input. Backslashes
+// are avoided since MySQL also treats them as escapes in string literals.
+const likeEscape = '!'
+
+// likeCondition matches a column with an escaped LIKE pattern argument
+const likeCondition = " LIKE ? ESCAPE '" + string(likeEscape) + "'"
+
+// likeEscaper escapes the LIKE metacharacters of a literal value
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// escapeLike escapes a literal value for a LIKE pattern using likeEscape
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// wildcardPattern translates the * and ? wildcards of a value to a LIKE pattern, its other
+// characters matching literally
+func wildcardPattern(value string) string {
+	return strings.NewReplacer("*", "%", "?", "_").Replace(escapeLike(value))
+}
+
+// expressionCondition converts the syntax tree of a parsed query string into a SQL condition and
+// its arguments. Unscoped terms and phrases are matched with the full-text index when it is given.
+func expressionCondition(node domain.QueryNode, fullText *fullTextSearch) (string, []interface{}) {
+	switch n := node.(type) {
+	case *domain.TermNode:
+		if fullText != nil && n.Field == domain.AnyField && !n.Wildcard {
+			if condition, args, ok := fullText.phraseCondition(n.Value); ok {
+				return condition, args
+			}
+		}
+		return termCondition(n.Field, n.Value, n.Wildcard)
+	case *domain.PhraseNode:
+		if fullText != nil && n.Field == domain.AnyField {
+			if condition, args, ok := fullText.phraseCondition(n.Text); ok {
+				return condition, args
+			}
+		}
+		return termCondition(n.Field, n.Text, false)
+	case *domain.RangeNode:
+		column := queryFieldColumns[n.Field]
+		conditions := make([]string, 0, 2)
+		args := make([]interface{}, 0, 2)
+		if !n.From.IsZero() {
+			conditions = append(conditions, column+" >= ?")
+			args = append(args, n.From)
+		}
+		if !n.To.IsZero() {
+			conditions = append(conditions, column+" < ?")
+			args = append(args, n.To)
+		}
+		return "(" + strings.Join(conditions, " AND ") + ")", args
+	case *domain.NotNode:
+		condition, args := expressionCondition(n.Clause, fullText)
+		return "NOT " + condition, args
+	case *domain.BoolNode:
+		conditions := make([]string, 0, len(n.Clauses))
+		args := make([]interface{}, 0)
+		for _, clause := range n.Clauses {
+			condition, clauseArgs := expressionCondition(clause, fullText)
+			conditions = append(conditions, condition)
+			args = append(args, clauseArgs...)
+		}
+		return "(" + strings.Join(conditions, " "+string(n.Operator)+" ") + ")", args
+	}
+	return "1 = 1", nil
+}
+
+// termCondition matches a value in a field. Text fields match substrings, filter fields match
+// exact values, and wildcards are translated to LIKE patterns. Other LIKE metacharacters of the
+// value match literally.
+func termCondition(field domain.QueryField, value string, wildcard bool) (string, []interface{}) {
+	pattern := escapeLike(value)
+	if wildcard {
+		pattern = wildcardPattern(value)
+	}
+
+	if field.IsFilter() {
+		column := queryFieldColumns[field]
+		if wildcard {
+			return column + likeCondition, []interface{}{pattern}
+		}
+		if field == domain.SiteField {
+			// A site also matches its subdomains
+			return "(" + column + " = ? OR " + column + likeCondition + ")", []interface{}{value, "%." + escapeLike(value)}
+		}
+		return column + " = ?", []interface{}{value}
+	}
+
+	columns := textSearchColumns
+	if column, ok := queryFieldColumns[field]; ok {
+		columns = []string{column}
+	}
+	conditions := make([]string, 0, len(columns))
+	args := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		conditions = append(conditions, column+likeCondition)
+		args = append(args, "%"+pattern+"%")
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// SavedSearchRepository implements the outgoing.SavedSearchRepository interface using GORM
+type SavedSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSavedSearchRepository creates a new saved search repository
+func NewSavedSearchRepository(client *Client) *SavedSearchRepository {
+	return &SavedSearchRepository{
+		db: client.DB,
+	}
+}
+
+// Ensure SavedSearchRepository implements the outgoing.SavedSearchRepository interface
+var _ outgoing.SavedSearchRepository = (*SavedSearchRepository)(nil)
+
+// Save creates or replaces a saved search
+func (s *SavedSearchRepository) Save(ctx context.Context, search *domain.SavedSearch) error {
+	if search == nil {
+		return errors.New("saved search cannot be nil")
+	}
+	if search.ID == "" {
+		return errors.New("saved search ID cannot be empty")
+	}
+	dbSearch, err := models.SavedSearchFromDomain(search)
+	if err != nil {
+		return fmt.Errorf("failed to convert domain model to database model: %w", err)
+	}
+	if err := s.db.WithContext(ctx).Save(dbSearch).Error; err != nil {
+		return fmt.Errorf("failed to save saved search: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a saved search by ID, or nil when it does not exist
+func (s *SavedSearchRepository) GetByID(ctx context.Context, id string) (*domain.SavedSearch, error) {
+	if id == "" {
+		return nil, errors.New("saved search ID cannot be empty")
+	}
+	var dbSearch models.SavedSearch
+	if err := s.db.WithContext(ctx).First(&dbSearch, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get saved search by ID: %w", err)
+	}
+	search, err := dbSearch.ToDomain()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert database model to domain model: %w", err)
+	}
+	return search, nil
+}
+
+// ListByUser returns the saved searches of a user, oldest first
+func (s *SavedSearchRepository) ListByUser(ctx context.Context, userID string) ([]*domain.SavedSearch, error) {
+	if userID == "" {
+		return nil, errors.New("user ID cannot be empty")
+	}
+	return s.find(s.db.WithContext(ctx).Where("user_id = ?", userID))
+}
+
+// List returns every saved search, oldest first
+func (s *SavedSearchRepository) List(ctx context.Context) ([]*domain.SavedSearch, error) {
+	return s.find(s.db.WithContext(ctx))
+}
+
+// find returns the saved searches selected by the query, oldest first
+func (s *SavedSearchRepository) find(db *gorm.DB) ([]*domain.SavedSearch, error) {
+	var dbSearches []models.SavedSearch
+	if err := db.Order("created_at, id").Find(&dbSearches).Error; err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	searches := make([]*domain.SavedSearch, 0, len(dbSearches))
+	for i := range dbSearches {
+		search, err := dbSearches[i].ToDomain()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert database model to domain model: %w", err)
+		}
+		searches = append(searches, search)
+	}
+	return searches, nil
+}
+
+// Delete deletes a saved search by ID
+func (s *SavedSearchRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("saved search ID cannot be empty")
+	}
+	result := s.db.WithContext(ctx).Delete(&models.SavedSearch{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete saved search: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("saved search %s not found", id)
+	}
+	return nil
+}
+
+// MarkRun records when a saved search last ran
+func (s *SavedSearchRepository) MarkRun(ctx context.Context, id string, runAt time.Time) error {
+	if id == "" {
+		return errors.New("saved search ID cannot be empty")
+	}
+	result := s.db.WithContext(ctx).Model(&models.SavedSearch{}).Where("id = ?", id).
+		Update("last_run_at", runAt)
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark saved search as run: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("saved search %s not found", id)
+	}
+	return nil
+}
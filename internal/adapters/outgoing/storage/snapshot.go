@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// snapshot caches a value built from the database. The first read builds it; once the
+// refresh interval has passed, reads keep serving the cached value while it is rebuilt
+// in the background. Concurrent first reads share one build, which outlives their contexts
+// so that a read giving up early does not cancel it for the next ones.
+type snapshot[T any] struct {
+	build    func(ctx context.Context) (T, error)
+	interval time.Duration
+
+	mu         sync.Mutex
+	value      T
+	built      bool
+	builtAt    time.Time
+	rebuilding bool
+	// pending is closed when the first build in progress finishes, or nil when none is running
+	pending chan struct{}
+	// err is the error of the last failed first build
+	err error
+}
+
+// newSnapshot creates a snapshot rebuilt with build every interval
+func newSnapshot[T any](interval time.Duration, build func(ctx context.Context) (T, error)) *snapshot[T] {
+	return &snapshot[T]{
+		build:    build,
+		interval: interval,
+	}
+}
+
+// get returns the cached value, building it first if needed
+func (s *snapshot[T]) get(ctx context.Context) (T, error) {
+	s.mu.Lock()
+	if !s.built {
+		pending := s.pending
+		if pending == nil {
+			pending = make(chan struct{})
+			s.pending = pending
+			go s.buildFirst(context.WithoutCancel(ctx), pending)
+		}
+		s.mu.Unlock()
+		return s.wait(ctx, pending)
+	}
+	value := s.value
+	stale := !s.rebuilding && time.Since(s.builtAt) > s.interval
+	if stale {
+		s.rebuilding = true
+	}
+	s.mu.Unlock()
+
+	if stale {
+		go func() {
+			// On error the previous value is kept and the rebuild is retried after the next interval
+			_ = s.refresh(context.Background())
+			s.mu.Lock()
+			s.rebuilding = false
+			s.builtAt = time.Now()
+			s.mu.Unlock()
+		}()
+	}
+	return value, nil
+}
+
+// buildFirst builds the value for the reads waiting on pending and releases them
+func (s *snapshot[T]) buildFirst(ctx context.Context, pending chan struct{}) {
+	err := s.refresh(ctx)
+	s.mu.Lock()
+	s.err = err
+	s.pending = nil
+	s.mu.Unlock()
+	close(pending)
+}
+
+// wait returns the value once the first build finishes, or the error of the build or context
+func (s *snapshot[T]) wait(ctx context.Context, pending chan struct{}) (T, error) {
+	var zero T
+	select {
+	case <-pending:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.built {
+		return zero, s.err
+	}
+	return s.value, nil
+}
+
+// refresh rebuilds the value immediately
+func (s *snapshot[T]) refresh(ctx context.Context) error {
+	value, err := s.build(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.value = value
+	s.built = true
+	s.builtAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/textutil"
+)
+
+const (
+	// vocabularyRefreshInterval is how long a built vocabulary is served before it is rebuilt
+	vocabularyRefreshInterval = 15 * time.Minute
+	// maxCorrectionDistance is the largest number of edits applied to a single term
+	maxCorrectionDistance = 2
+)
+
+// SpellingCorrector implements the outgoing.SpellingCorrector interface with an in-process
+// vocabulary of the indexed documents, correcting terms by edit distance and document frequency
+type SpellingCorrector struct {
+	db         *gorm.DB
+	vocabulary *snapshot[*textutil.Vocabulary]
+}
+
+// NewSpellingCorrector creates a new spelling corrector
+func NewSpellingCorrector(client *Client) *SpellingCorrector {
+	corrector := &SpellingCorrector{
+		db: client.DB,
+	}
+	corrector.vocabulary = newSnapshot(vocabularyRefreshInterval, corrector.build)
+	return corrector
+}
+
+// Ensure SpellingCorrector implements the outgoing.SpellingCorrector interface
+var _ outgoing.SpellingCorrector = (*SpellingCorrector)(nil)
+
+// Correct returns the query with its misspelled terms replaced, or nil when every term looks right
+func (s *SpellingCorrector) Correct(ctx context.Context, query string) (*domain.SearchSuggestion, error) {
+	vocabulary, err := s.vocabulary.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	corrected, changed := vocabulary.CorrectText(query, maxCorrectionDistance)
+	if !changed {
+		return nil, nil
+	}
+	return &domain.SearchSuggestion{
+		Text:          corrected,
+		Source:        domain.SuggestionSourceCorrection,
+		CorrectedFrom: query,
+	}, nil
+}
+
+// Refresh rebuilds the vocabulary from the documents table
+func (s *SpellingCorrector) Refresh(ctx context.Context) error {
+	return s.vocabulary.refresh(ctx)
+}
+
+// build counts the document frequency of every term of the titles, descriptions and contents of
+// the public documents. The vocabulary is shared by all callers, so restricted documents are left out.
+func (s *SpellingCorrector) build(ctx context.Context) (*textutil.Vocabulary, error) {
+	rows, err := applyDocumentAccess(s.db.WithContext(ctx), &domain.AccessScope{}).
+		Table("documents").
+		Select("title, meta_desc, content").
+		Where("deleted_at IS NULL").
+		Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load document texts: %w", err)
+	}
+	defer rows.Close()
+
+	vocabulary := textutil.NewVocabulary()
+	for rows.Next() {
+		var title, metaDesc, content string
+		if err := rows.Scan(&title, &metaDesc, &content); err != nil {
+			return nil, fmt.Errorf("failed to read document texts: %w", err)
+		}
+		vocabulary.AddText(title + " " + metaDesc + " " + content)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read document texts: %w", err)
+	}
+	return vocabulary, nil
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/textutil"
+)
+
+// completionRefreshInterval is how long a built completion trie is served before it is rebuilt
+const completionRefreshInterval = 5 * time.Minute
+
+// SuggestionCompleter implements the outgoing.SuggestionCompleter interface with an
+// in-process prefix trie built from document titles and keywords
+type SuggestionCompleter struct {
+	db   *gorm.DB
+	trie *snapshot[*textutil.Trie]
+}
+
+// NewSuggestionCompleter creates a new suggestion completer
+func NewSuggestionCompleter(client *Client) *SuggestionCompleter {
+	completer := &SuggestionCompleter{
+		db: client.DB,
+	}
+	completer.trie = newSnapshot(completionRefreshInterval, completer.build)
+	return completer
+}
+
+// Ensure SuggestionCompleter implements the outgoing.SuggestionCompleter interface
+var _ outgoing.SuggestionCompleter = (*SuggestionCompleter)(nil)
+
+// titleRow holds the columns of a document used to build completions
+type titleRow struct {
+	Title          string
+	ImportanceRank float64
+}
+
+// Complete returns the titles and keywords starting with the prefix.
+// The first call builds the trie; later calls rebuild a stale trie in the background.
+func (s *SuggestionCompleter) Complete(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error) {
+	trie, err := s.trie.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	completions := trie.Complete(domain.NormalizeQuery(prefix), limit)
+	suggestions := make([]domain.SearchSuggestion, 0, len(completions))
+	for _, completion := range completions {
+		suggestions = append(suggestions, domain.SearchSuggestion{
+			Text:   completion.Term,
+			Source: domain.SuggestionSourceAutocomplete,
+			Score:  completion.Weight,
+		})
+	}
+	return suggestions, nil
+}
+
+// Refresh rebuilds the completion trie from the documents table
+func (s *SuggestionCompleter) Refresh(ctx context.Context) error {
+	return s.trie.refresh(ctx)
+}
+
+// build loads the titles and keywords of the public documents into a new trie. The trie is shared
+// by all callers, so restricted documents are left out. Titles are weighted by the importance of
+// their document and keywords by how many documents use them.
+func (s *SuggestionCompleter) build(ctx context.Context) (*textutil.Trie, error) {
+	public := &domain.AccessScope{}
+	var titles []titleRow
+	if err := applyDocumentAccess(s.db.WithContext(ctx), public).
+		Table("documents").
+		Select("title, importance_rank").
+		Where("deleted_at IS NULL AND title <> ''").
+		Scan(&titles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load document titles: %w", err)
+	}
+
+	var keywords []string
+	if err := applyDocumentAccess(s.db.WithContext(ctx), public).
+		Table("document_keywords").
+		Joins("JOIN documents ON documents.id = document_keywords.document_id").
+		Where("documents.deleted_at IS NULL").
+		Pluck("document_keywords.keyword", &keywords).Error; err != nil {
+		return nil, fmt.Errorf("failed to load document keywords: %w", err)
+	}
+
+	trie := textutil.NewTrie()
+	for _, row := range titles {
+		trie.Insert(domain.NormalizeQuery(row.Title), 1+row.ImportanceRank)
+	}
+	for _, keyword := range keywords {
+		trie.Insert(domain.NormalizeQuery(keyword), 1)
+	}
+	return trie, nil
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// SuggestionFeedbackRepository implements the outgoing.SuggestionFeedbackRepository interface using GORM
+type SuggestionFeedbackRepository struct {
+	db *gorm.DB
+}
+
+// NewSuggestionFeedbackRepository creates a new suggestion feedback repository
+func NewSuggestionFeedbackRepository(client *Client) *SuggestionFeedbackRepository {
+	return &SuggestionFeedbackRepository{
+		db: client.DB,
+	}
+}
+
+// Ensure SuggestionFeedbackRepository implements the outgoing.SuggestionFeedbackRepository interface
+var _ outgoing.SuggestionFeedbackRepository = (*SuggestionFeedbackRepository)(nil)
+
+// RecordImpressions increments the impression count of each shown suggestion
+func (s *SuggestionFeedbackRepository) RecordImpressions(ctx context.Context, suggestions []domain.SearchSuggestion, shownAt time.Time) error {
+	stats := make([]models.SuggestionStat, 0, len(suggestions))
+	seen := make(map[string]bool, len(suggestions))
+	for _, suggestion := range suggestions {
+		text := domain.NormalizeQuery(suggestion.Text)
+		if text == "" || len(text) > maxRecordedQueryLength || seen[text] {
+			continue
+		}
+		seen[text] = true
+		stats = append(stats, models.SuggestionStat{
+			Text:        text,
+			Impressions: 1,
+			LastShownAt: shownAt,
+		})
+	}
+	if len(stats) == 0 {
+		return nil
+	}
+
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "text"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"impressions":   gorm.Expr("impressions + ?", 1),
+			"last_shown_at": shownAt,
+			"updated_at":    time.Now(),
+		}),
+	}).Create(&stats).Error
+	if err != nil {
+		return fmt.Errorf("failed to record suggestion impressions: %w", err)
+	}
+	return nil
+}
+
+// RecordSelection stores a selection and adds it to the statistics of the suggestion
+func (s *SuggestionFeedbackRepository) RecordSelection(ctx context.Context, selection *domain.SuggestionSelection) error {
+	if selection == nil {
+		return errors.New("selection cannot be nil")
+	}
+	dbSelection := models.SuggestionSelectionFromDomain(selection)
+	if len(dbSelection.Text) > maxRecordedQueryLength {
+		return errors.New("suggestion text is too long")
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dbSelection).Error; err != nil {
+			return fmt.Errorf("failed to save suggestion selection: %w", err)
+		}
+
+		// A selection implies the suggestion was shown, which keeps the CTR at most 1
+		// even when the impression was not recorded
+		stat := models.SuggestionStat{
+			Text:           dbSelection.Text,
+			Impressions:    1,
+			Selections:     1,
+			PositionSum:    int64(dbSelection.Position),
+			LastShownAt:    dbSelection.SelectedAt,
+			LastSelectedAt: dbSelection.SelectedAt,
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "text"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"selections":       gorm.Expr("selections + ?", 1),
+				"impressions":      gorm.Expr("CASE WHEN impressions > selections THEN impressions ELSE selections + 1 END"),
+				"position_sum":     gorm.Expr("position_sum + ?", dbSelection.Position),
+				"last_selected_at": dbSelection.SelectedAt,
+				"updated_at":       time.Now(),
+			}),
+		}).Create(&stat).Error
+		if err != nil {
+			return fmt.Errorf("failed to update suggestion statistics: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetStats returns the statistics of the given suggestions, keyed by normalized text.
+// Suggestions without feedback are absent from the result.
+func (s *SuggestionFeedbackRepository) GetStats(ctx context.Context, texts []string) (map[string]domain.SuggestionStats, error) {
+	normalized := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if text = domain.NormalizeQuery(text); text != "" {
+			normalized = append(normalized, text)
+		}
+	}
+	result := make(map[string]domain.SuggestionStats, len(normalized))
+	if len(normalized) == 0 {
+		return result, nil
+	}
+
+	var stats []models.SuggestionStat
+	if err := s.db.WithContext(ctx).Where("text IN ?", normalized).Find(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to get suggestion statistics: %w", err)
+	}
+	for i := range stats {
+		result[stats[i].Text] = stats[i].ToDomain()
+	}
+	return result, nil
+}
+
+// ListUnselected returns the suggestions shown at least minImpressions times that were never selected
+func (s *SuggestionFeedbackRepository) ListUnselected(ctx context.Context, minImpressions, limit int) ([]domain.SuggestionStats, error) {
+	var stats []models.SuggestionStat
+	if err := s.db.WithContext(ctx).
+		Where("selections = 0 AND impressions >= ?", minImpressions).
+		Order("impressions DESC, text ASC").
+		Limit(limit).
+		Find(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to list unselected suggestions: %w", err)
+	}
+
+	result := make([]domain.SuggestionStats, 0, len(stats))
+	for i := range stats {
+		result = append(result, stats[i].ToDomain())
+	}
+	return result, nil
+}
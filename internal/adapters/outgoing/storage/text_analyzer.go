@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage/models"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/textutil"
+)
+
+// TextAnalyzer implements the outgoing.TextAnalyzer interface. Searches read the synonyms and
+// stopwords of an index from its settings when they run, so there is nothing to apply.
+type TextAnalyzer struct {
+	db *gorm.DB
+}
+
+// NewTextAnalyzer creates a new text analyzer
+func NewTextAnalyzer(client *Client) *TextAnalyzer {
+	return &TextAnalyzer{
+		db: client.DB,
+	}
+}
+
+// Ensure TextAnalyzer implements the outgoing.TextAnalyzer interface
+var _ outgoing.TextAnalyzer = (*TextAnalyzer)(nil)
+
+// ApplyAnalysis does nothing: the saved settings of the index take effect on the next search
+func (t *TextAnalyzer) ApplyAnalysis(ctx context.Context, index *domain.Index) error {
+	if index == nil {
+		return errors.New("index cannot be nil")
+	}
+	return nil
+}
+
+// Analyze returns the terms searches on the index produce from the text
+func (t *TextAnalyzer) Analyze(ctx context.Context, index *domain.Index, text string) ([]domain.AnalyzedToken, error) {
+	if index == nil {
+		return nil, errors.New("index cannot be nil")
+	}
+	terms := newIndexAnalyzer(index.Settings).Analyze(text)
+	tokens := make([]domain.AnalyzedToken, 0, len(terms))
+	for _, term := range terms {
+		tokenType := domain.TokenTypeWord
+		if term.Synonym {
+			tokenType = domain.TokenTypeSynonym
+		}
+		tokens = append(tokens, domain.AnalyzedToken{
+			Token:    term.Text,
+			Position: term.Position,
+			Type:     tokenType,
+		})
+	}
+	return tokens, nil
+}
+
+// newIndexAnalyzer creates the analyzer of the stopwords and synonyms of an index
+func newIndexAnalyzer(settings domain.IndexSettings) *textutil.Analyzer {
+	analyzer := textutil.NewAnalyzer(settings.Stopwords)
+	for _, rule := range settings.Synonyms {
+		if len(rule.Replacements) > 0 {
+			analyzer.AddReplacement(rule.Terms, rule.Replacements)
+		} else {
+			analyzer.AddEquivalent(rule.Terms)
+		}
+	}
+	return analyzer
+}
+
+// queryGroups analyzes the text of a search query into groups of alternative phrases. Searches
+// restricted to an index with stopwords or synonyms use them; others search each word.
+func (d DocumentRepository) queryGroups(ctx context.Context, query *domain.SearchQuery) ([][]string, error) {
+	return analyzeQueryGroups(ctx, d.db, query)
+}
+
+// analyzeQueryGroups analyzes the text of a search query with the settings of its index read from the database
+func analyzeQueryGroups(ctx context.Context, db *gorm.DB, query *domain.SearchQuery) ([][]string, error) {
+	indexID, _ := query.Filters["index_id"].(string)
+	if indexID != "" {
+		var dbIndex models.Index
+		err := db.WithContext(ctx).First(&dbIndex, "id = ?", indexID).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to load index settings: %w", err)
+		}
+		if err == nil {
+			index, err := dbIndex.ToDomain()
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert database model to domain model: %w", err)
+			}
+			if len(index.Settings.Stopwords) > 0 || len(index.Settings.Synonyms) > 0 {
+				return newIndexAnalyzer(index.Settings).Groups(query.Query), nil
+			}
+		}
+	}
+
+	tokens := textutil.Tokenize(query.Query)
+	groups := make([][]string, len(tokens))
+	for i, token := range tokens {
+		groups[i] = []string{token}
+	}
+	return groups, nil
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// ReadAllDocumentsPermission lets a user read every document whatever its access control lists
+const ReadAllDocumentsPermission = "read:all_documents"
+
+// AccessScope is the set of principals a caller reads documents as. A document is readable
+// when it is public or lists one of the principals; unrestricted scopes read every document.
+type AccessScope struct {
+	Unrestricted bool
+	Users        []string
+	Groups       []string
+	Roles        []string
+}
+
+// NewAccessScope returns the scope of a user: its ID, groups and roles. Usernames are left out, as
+// one could equal the ID of another user. Users with the read all documents permission are
+// unrestricted; anonymous callers only read public documents.
+func NewAccessScope(user *User) *AccessScope {
+	if user == nil {
+		return &AccessScope{}
+	}
+	if user.HasPermission(ReadAllDocumentsPermission) {
+		return &AccessScope{Unrestricted: true}
+	}
+	users := make([]string, 0, 1)
+	if user.ID != "" {
+		users = append(users, user.ID)
+	}
+	return &AccessScope{
+		Users:  users,
+		Groups: slices.Clone(user.Groups),
+		Roles:  slices.Clone(user.Roles),
+	}
+}
+
+// CanRead reports whether the scope can read a document. A nil scope reads every document.
+func (s *AccessScope) CanRead(document *Document) bool {
+	if s == nil || s.Unrestricted || document.IsPublic() {
+		return true
+	}
+	return containsAny(document.AllowedUsers, s.Users) ||
+		containsAny(document.AllowedGroups, s.Groups) ||
+		containsAny(document.AllowedRoles, s.Roles)
+}
+
+// CacheKey returns a key identifying the documents the scope can read, so that cached results
+// are only shared between callers reading the same documents
+func (s *AccessScope) CacheKey() string {
+	if s == nil || s.Unrestricted {
+		return "*"
+	}
+	parts := make([]string, 0, 3)
+	for _, principals := range [][]string{s.Users, s.Groups, s.Roles} {
+		sorted := slices.Clone(principals)
+		slices.Sort(sorted)
+		parts = append(parts, strings.Join(sorted, ","))
+	}
+	return strings.Join(parts, "|")
+}
+
+// containsAny reports whether the values share an element with the candidates
+func containsAny(values, candidates []string) bool {
+	for _, candidate := range candidates {
+		if slices.Contains(values, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+type userContextKey struct{}
+
+type accessScopeContextKey struct{}
+
+// ContextWithUser returns a context carrying the user calling a service
+func ContextWithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the user calling a service, or nil for anonymous callers
+func UserFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(userContextKey{}).(*User)
+	return user
+}
+
+// ContextWithAccessScope returns a context restricting the documents repositories return to
+// those the scope can read
+func ContextWithAccessScope(ctx context.Context, scope *AccessScope) context.Context {
+	return context.WithValue(ctx, accessScopeContextKey{}, scope)
+}
+
+// AccessScopeFromContext returns the access scope of a context, or nil when repositories are
+// not restricted, as for internal callers
+func AccessScopeFromContext(ctx context.Context) *AccessScope {
+	scope, _ := ctx.Value(accessScopeContextKey{}).(*AccessScope)
+	return scope
+}
@@ -3,6 +3,9 @@ package domain
 import (
 	"errors"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -16,6 +19,7 @@ type Document struct {
 	ContentFingerprint string
 	LastCrawled        time.Time
 	LastModified       time.Time
+	PublishedDate      time.Time
 	Lang               string
 	MetaDesc           string
 	MetaKeywords       []string
@@ -24,13 +28,24 @@ type Document struct {
 	StatusCode         int
 	ContentLength      int
 	ImportanceRank     float64
+	IncomingLinks      int
+	OutgoingLinks      int
 	IndexID            string
 	IsDuplicate        bool
 	OriginalDocID      string
 	VersionCount       int
 	CurrentVersion     int
 	ParsedContent      map[string]interface{}
+	Embedding          []float32
 	Score              float64
+	Explanation        *ScoreExplanation
+	// Highlights holds the snippets of the highlighted fields of a search hit by field
+	Highlights map[string][]string
+	// AllowedUsers, AllowedGroups and AllowedRoles restrict reading the document to the listed
+	// user IDs, groups and roles. Documents without any are public.
+	AllowedUsers  []string
+	AllowedGroups []string
+	AllowedRoles  []string
 }
 
 // Keyword represents a document keyword with relevance information
@@ -91,10 +106,29 @@ func (d *Document) SetContentFingerprint(fingerprint string) {
 	d.ContentFingerprint = fingerprint
 }
 
+// Host returns the lower-cased host name of the document URL
+func (d *Document) Host() string {
+	parsedURL, err := url.Parse(d.URL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsedURL.Hostname())
+}
+
+// IsPublic reports whether every caller can read the document
+func (d *Document) IsPublic() bool {
+	return len(d.AllowedUsers) == 0 && len(d.AllowedGroups) == 0 && len(d.AllowedRoles) == 0
+}
+
 // Validate ensures the document is valid
 func (d *Document) Validate() error {
 	if d.URL == "" {
 		return errors.New("document URL cannot be empty")
 	}
+	for _, principals := range [][]string{d.AllowedUsers, d.AllowedGroups, d.AllowedRoles} {
+		if slices.Contains(principals, "") {
+			return errors.New("document access control entries cannot be empty")
+		}
+	}
 	return nil
 }
@@ -0,0 +1,29 @@
+package domain
+
+import "math"
+
+// EmbeddingDimensions is the number of dimensions of document and query embeddings
+const EmbeddingDimensions = 256
+
+// IsVectorSearch reports whether the query retrieves documents by embedding similarity
+func (q *SearchQuery) IsVectorSearch() bool {
+	return q.Type == SemanticSearch || q.Type == HybridSearch
+}
+
+// CosineSimilarity returns the cosine of the angle between two vectors,
+// or zero when their dimensions differ or either has no magnitude
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FacetType defines how the buckets of a facet are computed
+type FacetType string
+
+const (
+	TermsFacet         FacetType = "terms"
+	DateHistogramFacet FacetType = "date_histogram"
+	RangeFacet         FacetType = "range"
+)
+
+// Facet fields supported by the document repositories
+const (
+	FacetFieldContentType   = "content_type"
+	FacetFieldLang          = "lang"
+	FacetFieldCategory      = "category"
+	FacetFieldKeywords      = "keywords"
+	FacetFieldDomain        = "domain"
+	FacetFieldLastCrawled   = "last_crawled"
+	FacetFieldContentLength = "content_length"
+)
+
+// Date histogram intervals
+const (
+	IntervalDay   = "day"
+	IntervalMonth = "month"
+	IntervalYear  = "year"
+)
+
+// FacetRequest describes a facet to compute over the documents matching a search
+type FacetRequest struct {
+	Name     string
+	Field    string
+	Type     FacetType
+	Size     int
+	Interval string
+	Ranges   []FacetRange
+}
+
+// FacetRange is a half-open [From, To) bucket of a range facet; a nil bound is unbounded
+type FacetRange struct {
+	Key  string
+	From *float64
+	To   *float64
+}
+
+// FacetBucket holds the number of matching documents for one facet value
+type FacetBucket struct {
+	Key   string
+	Count int
+	From  *float64
+	To    *float64
+}
+
+// FacetResult holds the buckets computed for a facet request
+type FacetResult struct {
+	Name    string
+	Field   string
+	Type    FacetType
+	Buckets []FacetBucket
+}
+
+// NewTermsFacet creates a terms facet returning the most frequent values of a field
+func NewTermsFacet(field string, size int) FacetRequest {
+	return FacetRequest{Name: field, Field: field, Type: TermsFacet, Size: size}
+}
+
+// NewDateHistogramFacet creates a date histogram facet over the last crawled date
+func NewDateHistogramFacet(interval string) FacetRequest {
+	return FacetRequest{Name: FacetFieldLastCrawled, Field: FacetFieldLastCrawled, Type: DateHistogramFacet, Interval: interval}
+}
+
+// NewRangeFacet creates a range facet over the content length
+func NewRangeFacet(ranges ...FacetRange) FacetRequest {
+	return FacetRequest{Name: FacetFieldContentLength, Field: FacetFieldContentLength, Type: RangeFacet, Ranges: ranges}
+}
+
+// BucketSize returns the number of terms buckets to return
+func (f FacetRequest) BucketSize() int {
+	if f.Size < 1 {
+		return 10
+	}
+	return f.Size
+}
+
+// Validate ensures the facet request targets a supported field and is well-formed
+func (f FacetRequest) Validate() error {
+	if f.Name == "" {
+		return errors.New("facet name cannot be empty")
+	}
+	switch f.Type {
+	case TermsFacet:
+		switch f.Field {
+		case FacetFieldContentType, FacetFieldLang, FacetFieldCategory, FacetFieldKeywords, FacetFieldDomain:
+			return nil
+		}
+		return fmt.Errorf("terms facet is not supported on field %q", f.Field)
+	case DateHistogramFacet:
+		if f.Field != FacetFieldLastCrawled {
+			return fmt.Errorf("date histogram facet is not supported on field %q", f.Field)
+		}
+		switch f.Interval {
+		case IntervalDay, IntervalMonth, IntervalYear:
+			return nil
+		}
+		return fmt.Errorf("unsupported date histogram interval %q", f.Interval)
+	case RangeFacet:
+		if f.Field != FacetFieldContentLength {
+			return fmt.Errorf("range facet is not supported on field %q", f.Field)
+		}
+		if len(f.Ranges) == 0 {
+			return errors.New("range facet requires at least one range")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported facet type %q", f.Type)
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FederatedWindow is the number of top results a federated search merges; pages must end within it
+const FederatedWindow = 100
+
+// IndexWeight is an index searched by a federated search with the weight of its hits
+type IndexWeight struct {
+	IndexID string
+	// Weight multiplies the normalized scores of the index hits, 1 when zero
+	Weight float64
+}
+
+// WeightOrDefault returns the weight of the index hits
+func (w IndexWeight) WeightOrDefault() float64 {
+	if w.Weight == 0 {
+		return 1
+	}
+	return w.Weight
+}
+
+// IsFederated reports whether the query searches several indexes at once
+func (q *SearchQuery) IsFederated() bool {
+	return len(q.Indexes) > 0
+}
+
+// IndexIDs returns the IDs of the indexes searched by a federated query
+func (q *SearchQuery) IndexIDs() []string {
+	ids := make([]string, len(q.Indexes))
+	for i, index := range q.Indexes {
+		ids[i] = index.IndexID
+	}
+	return ids
+}
+
+// validateFederation ensures the indexes of a federated query are distinct and weighted, and that
+// the query uses no option federated searches do not support
+func (q *SearchQuery) validateFederation() error {
+	if !q.IsFederated() {
+		return nil
+	}
+	seen := make(map[string]bool, len(q.Indexes))
+	for _, index := range q.Indexes {
+		if index.IndexID == "" {
+			return errors.New("federated index ID cannot be empty")
+		}
+		if seen[index.IndexID] {
+			return fmt.Errorf("index %s is searched more than once", index.IndexID)
+		}
+		seen[index.IndexID] = true
+		if index.Weight < 0 {
+			return fmt.Errorf("weight of index %s cannot be negative", index.IndexID)
+		}
+	}
+	if _, ok := q.Filters["index_id"]; ok {
+		return errors.New("federated searches cannot filter on index_id")
+	}
+	if q.UseSearchAfter {
+		return errors.New("federated searches do not support cursor pagination")
+	}
+	// Hits of the indexes are merged by their normalized scores, which ignore the sort fields
+	if len(q.SortFields) > 0 {
+		return errors.New("federated searches do not support sorting by fields")
+	}
+	if q.IsVectorSearch() {
+		return errors.New("federated searches do not support vector search")
+	}
+	if q.Offset()+q.Limit() > FederatedWindow {
+		return fmt.Errorf("federated searches cannot page beyond the first %d results", FederatedWindow)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DecayFunctionType is the shape of the curve lowering the boost of older documents
+type DecayFunctionType string
+
+const (
+	GaussDecay  DecayFunctionType = "gauss"
+	ExpDecay    DecayFunctionType = "exp"
+	LinearDecay DecayFunctionType = "linear"
+)
+
+// DecayField is the document date a decay function measures the age from
+type DecayField string
+
+const (
+	DecayOnLastModified  DecayField = "last_modified"
+	DecayOnPublishedDate DecayField = "published_date"
+	DecayOnLastCrawled   DecayField = "last_crawled"
+)
+
+// defaultDecay is the boost left at the scale distance when a decay function does not set one
+const defaultDecay = 0.5
+
+// DecayFunction boosts recent documents by the age of one of their dates, following the decay
+// functions of Elasticsearch. Documents younger than Offset get the full Weight; at Offset plus
+// Scale the boost has fallen to Decay times the weight.
+type DecayFunction struct {
+	Function DecayFunctionType
+	Field    DecayField
+	Scale    time.Duration
+	Offset   time.Duration
+	// Decay is the share of the weight left at the scale distance, 0.5 when zero
+	Decay  float64
+	Weight float64
+}
+
+// Validate ensures the decay function has a known shape and field and a positive scale
+func (f DecayFunction) Validate() error {
+	switch f.Function {
+	case GaussDecay, ExpDecay, LinearDecay:
+	default:
+		return fmt.Errorf("unknown decay function %q", f.Function)
+	}
+	switch f.Field {
+	case DecayOnLastModified, DecayOnPublishedDate, DecayOnLastCrawled:
+	default:
+		return fmt.Errorf("unknown decay field %q", f.Field)
+	}
+	if f.Scale <= 0 {
+		return errors.New("decay scale must be positive")
+	}
+	if f.Offset < 0 {
+		return errors.New("decay offset cannot be negative")
+	}
+	if f.Decay < 0 || f.Decay >= 1 {
+		return errors.New("decay must be between 0 and 1")
+	}
+	if f.Weight <= 0 {
+		return errors.New("decay weight must be positive")
+	}
+	return nil
+}
+
+// DecayOrDefault returns the share of the weight left at the scale distance
+func (f DecayFunction) DecayOrDefault() float64 {
+	if f.Decay == 0 {
+		return defaultDecay
+	}
+	return f.Decay
+}
+
+// Value returns the decay, between 0 and 1, of a date at the given time. Documents without
+// the date decay to 0.
+func (f DecayFunction) Value(date, now time.Time) float64 {
+	if date.IsZero() {
+		return 0
+	}
+	age := now.Sub(date)
+	if age < 0 {
+		age = -age
+	}
+	distance := max(0, float64(age-f.Offset)) / float64(f.Scale)
+	decay := f.DecayOrDefault()
+	switch f.Function {
+	case GaussDecay:
+		return math.Pow(decay, distance*distance)
+	case ExpDecay:
+		return math.Pow(decay, distance)
+	case LinearDecay:
+		return max(0, 1-distance*(1-decay))
+	}
+	return 0
+}
+
+// Date returns the date of the document the decay function measures
+func (f DecayFunction) Date(doc *Document) time.Time {
+	switch f.Field {
+	case DecayOnLastModified:
+		return doc.LastModified
+	case DecayOnPublishedDate:
+		return doc.PublishedDate
+	case DecayOnLastCrawled:
+		return doc.LastCrawled
+	}
+	return time.Time{}
+}
+
+// ValidateDecayFunctions validates every decay function of a ranking
+func ValidateDecayFunctions(functions []DecayFunction) error {
+	for _, function := range functions {
+		if err := function.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FreshnessBoost returns the factor a document's relevance is multiplied by: one plus the
+// weighted decays of its dates, so documents without any recent date keep their relevance
+func FreshnessBoost(functions []DecayFunction, doc *Document, now time.Time) float64 {
+	boost := 1.0
+	for _, function := range functions {
+		boost += function.Weight * function.Value(function.Date(doc), now)
+	}
+	return boost
+}
+
+// ExplainFreshness returns the explanation of the freshness boost of a document
+func ExplainFreshness(functions []DecayFunction, doc *Document, now time.Time) *ScoreExplanation {
+	details := []*ScoreExplanation{NewScoreExplanation(1, "base boost")}
+	for _, function := range functions {
+		date := function.Date(doc)
+		age := "no date"
+		if !date.IsZero() {
+			age = "age " + now.Sub(date).Round(time.Hour).String()
+		}
+		description := fmt.Sprintf("%s decay on %s: weight %.4g, scale %s, offset %s, %s",
+			function.Function, function.Field, function.Weight, function.Scale, function.Offset, age)
+		details = append(details, NewScoreExplanation(function.Weight*function.Value(date, now), description))
+	}
+	return NewScoreExplanation(FreshnessBoost(functions, doc, now), "freshness boost, sum of:", details...)
+}
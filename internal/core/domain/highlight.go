@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultHighlightFragments is the number of snippets returned per highlighted field by default
+	DefaultHighlightFragments = 3
+	// DefaultHighlightFragmentSize is the length of a snippet in characters by default
+	DefaultHighlightFragmentSize = 160
+	// MaxHighlightFragments bounds the snippets returned per highlighted field
+	MaxHighlightFragments = 10
+	// MinHighlightFragmentSize and MaxHighlightFragmentSize bound the length of a snippet in characters
+	MinHighlightFragmentSize = 20
+	MaxHighlightFragmentSize = 1000
+	// DefaultHighlightPreTag and DefaultHighlightPostTag surround the matches in snippets by default
+	DefaultHighlightPreTag  = "<em>"
+	DefaultHighlightPostTag = "</em>"
+)
+
+// HighlightableFields are the document fields snippets can be generated from
+var HighlightableFields = map[string]bool{
+	"title":     true,
+	"content":   true,
+	"meta_desc": true,
+}
+
+// HighlightOptions configures the snippets of the highlighted fields of a search. Snippets are
+// the passages of a field that best match the query, HTML-escaped except for the tags
+// surrounding the matches. Zero values use the defaults.
+type HighlightOptions struct {
+	FragmentCount int
+	FragmentSize  int
+	PreTag        string
+	PostTag       string
+}
+
+// Validate checks that the fragment count and size are within bounds and that the tags are set together
+func (o HighlightOptions) Validate() error {
+	if o.FragmentCount < 0 || o.FragmentCount > MaxHighlightFragments {
+		return fmt.Errorf("highlight fragment count must be between 1 and %d", MaxHighlightFragments)
+	}
+	if o.FragmentSize != 0 && (o.FragmentSize < MinHighlightFragmentSize || o.FragmentSize > MaxHighlightFragmentSize) {
+		return fmt.Errorf("highlight fragment size must be between %d and %d", MinHighlightFragmentSize, MaxHighlightFragmentSize)
+	}
+	if (o.PreTag == "") != (o.PostTag == "") {
+		return errors.New("highlight pre and post tags must be set together")
+	}
+	return nil
+}
+
+// WithDefaults returns the options with their unset values replaced by the defaults, using the
+// given fragment size when positive
+func (o HighlightOptions) WithDefaults(fragmentSize int) HighlightOptions {
+	if o.FragmentCount == 0 {
+		o.FragmentCount = DefaultHighlightFragments
+	}
+	if o.FragmentSize == 0 {
+		o.FragmentSize = DefaultHighlightFragmentSize
+		if fragmentSize > 0 {
+			o.FragmentSize = fragmentSize
+		}
+	}
+	if o.PreTag == "" {
+		o.PreTag = DefaultHighlightPreTag
+		o.PostTag = DefaultHighlightPostTag
+	}
+	return o
+}
+
+// validateHighlight checks the highlighted fields and the options of their snippets
+func (q *SearchQuery) validateHighlight() error {
+	for _, field := range q.HighlightFields {
+		if !HighlightableFields[field] {
+			return fmt.Errorf("field %q cannot be highlighted", field)
+		}
+	}
+	return q.Highlight.Validate()
+}
+
+// Highlighting returns the snippets of the documents by document ID and field, leaving out the
+// documents without snippets
+func Highlighting(documents []*Document) map[string]map[string][]string {
+	highlighting := make(map[string]map[string][]string)
+	for _, doc := range documents {
+		if len(doc.Highlights) > 0 {
+			highlighting[doc.ID] = doc.Highlights
+		}
+	}
+	return highlighting
+}
@@ -35,7 +35,10 @@ type IndexSettings struct {
 	RefreshInterval  string
 	AnalyzerSettings map[string]interface{}
 	Stopwords        []string
+	Synonyms         []SynonymRule
 	Languages        []string
+	// Decay boosts recent documents in searches of the index that set no decay functions
+	Decay []DecayFunction
 }
 
 // NewIndex creates a new index with default settings
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// DocumentLinks is a document of an index with the URLs it links to
+type DocumentLinks struct {
+	DocumentID string
+	URL        string
+	Links      []string
+}
+
+// LinkScore holds the link analysis results of a document. IncomingLinks counts the documents of
+// the index linking to it; OutgoingLinks counts the distinct URLs it links to other than
+// its own, in the index or not.
+type LinkScore struct {
+	DocumentID     string
+	ImportanceRank float64
+	IncomingLinks  int
+	OutgoingLinks  int
+}
+
+// PageRankOptions configures the PageRank iteration
+type PageRankOptions struct {
+	// Damping is the probability of following a link rather than jumping to a random document
+	Damping float64
+	// MaxIterations bounds the iterations when the ranks do not converge
+	MaxIterations int
+	// Tolerance is the L1 change of the ranks below which they have converged
+	Tolerance float64
+}
+
+// DefaultPageRankOptions returns the usual PageRank options
+func DefaultPageRankOptions() PageRankOptions {
+	return PageRankOptions{
+		Damping:       0.85,
+		MaxIterations: 100,
+		Tolerance:     1e-6,
+	}
+}
+
+// Validate checks that the options define a converging iteration
+func (o PageRankOptions) Validate() error {
+	if o.Damping <= 0 || o.Damping >= 1 {
+		return errors.New("damping must be between 0 and 1")
+	}
+	if o.MaxIterations <= 0 {
+		return errors.New("max iterations must be positive")
+	}
+	if o.Tolerance <= 0 {
+		return errors.New("tolerance must be positive")
+	}
+	return nil
+}
+
+// LinkAnalysisReport summarizes a PageRank run over an index
+type LinkAnalysisReport struct {
+	IndexID           string
+	Documents         int
+	Links             int
+	DanglingDocuments int
+	Iterations        int
+	Converged         bool
+}
+
+// LinkGraph is the directed graph of links between the documents of an index. Links are resolved
+// by normalized URL; duplicate links and links of a document to itself are dropped.
+type LinkGraph struct {
+	documents []DocumentLinks
+	outgoing  [][]int
+	incoming  []int
+	external  []int
+}
+
+// NewLinkGraph builds the link graph of the documents
+func NewLinkGraph(documents []DocumentLinks) *LinkGraph {
+	byURL := make(map[string]int, len(documents))
+	for i, doc := range documents {
+		byURL[normalizeLinkURL(doc.URL)] = i
+	}
+
+	graph := &LinkGraph{
+		documents: documents,
+		outgoing:  make([][]int, len(documents)),
+		incoming:  make([]int, len(documents)),
+		external:  make([]int, len(documents)),
+	}
+	for i, doc := range documents {
+		targets := make(map[int]bool)
+		seen := make(map[string]bool, len(doc.Links))
+		for _, link := range doc.Links {
+			normalized := normalizeLinkURL(link)
+			if normalized == "" || seen[normalized] {
+				continue
+			}
+			seen[normalized] = true
+			target, ok := byURL[normalized]
+			if !ok {
+				graph.external[i]++
+				continue
+			}
+			if target == i || targets[target] {
+				continue
+			}
+			targets[target] = true
+			graph.outgoing[i] = append(graph.outgoing[i], target)
+			graph.incoming[target]++
+		}
+	}
+	return graph
+}
+
+// normalizeLinkURL normalizes a link the way document URLs are, keeping unparsable links as they are
+func normalizeLinkURL(link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+	url, err := NewURL(link)
+	if err != nil {
+		return link
+	}
+	return url.Normalize()
+}
+
+// Len returns the number of documents of the graph
+func (g *LinkGraph) Len() int {
+	return len(g.documents)
+}
+
+// Links returns the number of resolved links between documents of the graph
+func (g *LinkGraph) Links() int {
+	links := 0
+	for _, targets := range g.outgoing {
+		links += len(targets)
+	}
+	return links
+}
+
+// Dangling returns the number of documents without resolved links
+func (g *LinkGraph) Dangling() int {
+	dangling := 0
+	for _, targets := range g.outgoing {
+		if len(targets) == 0 {
+			dangling++
+		}
+	}
+	return dangling
+}
+
+// PageRank computes the PageRank of every document by power iteration. The rank of dangling
+// documents is spread evenly over all documents, so ranks always sum to one. It returns the ranks
+// in document order, the number of iterations run and whether the ranks converged.
+func (g *LinkGraph) PageRank(options PageRankOptions) ([]float64, int, bool) {
+	n := len(g.documents)
+	if n == 0 {
+		return []float64{}, 0, true
+	}
+	ranks := make([]float64, n)
+	next := make([]float64, n)
+	for i := range ranks {
+		ranks[i] = 1 / float64(n)
+	}
+
+	for iteration := 1; iteration <= options.MaxIterations; iteration++ {
+		dangling := 0.0
+		for i, targets := range g.outgoing {
+			if len(targets) == 0 {
+				dangling += ranks[i]
+			}
+		}
+		base := (1-options.Damping)/float64(n) + options.Damping*dangling/float64(n)
+		for i := range next {
+			next[i] = base
+		}
+		for i, targets := range g.outgoing {
+			if len(targets) == 0 {
+				continue
+			}
+			share := options.Damping * ranks[i] / float64(len(targets))
+			for _, target := range targets {
+				next[target] += share
+			}
+		}
+
+		change := 0.0
+		for i := range ranks {
+			change += math.Abs(next[i] - ranks[i])
+		}
+		ranks, next = next, ranks
+		if change < options.Tolerance {
+			return ranks, iteration, true
+		}
+	}
+	return ranks, options.MaxIterations, false
+}
+
+// Scores returns the link scores of the documents for their ranks. The importance rank is the
+// PageRank scaled so that the top document of the index has a rank of one.
+func (g *LinkGraph) Scores(ranks []float64) []LinkScore {
+	top := 0.0
+	for _, rank := range ranks {
+		top = max(top, rank)
+	}
+	scores := make([]LinkScore, len(g.documents))
+	for i, doc := range g.documents {
+		scores[i] = LinkScore{
+			DocumentID:    doc.DocumentID,
+			IncomingLinks: g.incoming[i],
+			OutgoingLinks: len(g.outgoing[i]) + g.external[i],
+		}
+		if top > 0 {
+			scores[i].ImportanceRank = ranks[i] / top
+		}
+	}
+	return scores
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// PageCursor holds the position of a cursor-based pagination session.
+// It is handed to clients as an opaque token and must be passed back unchanged.
+type PageCursor struct {
+	PointInTime string        `json:"pit,omitempty"`
+	SearchAfter []interface{} `json:"after,omitempty"`
+}
+
+// Encode serializes the cursor into an opaque URL-safe token
+func (c *PageCursor) Encode() string {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodePageCursor parses a token produced by PageCursor.Encode.
+// Numeric sort values are kept as json.Number so they round-trip without losing precision.
+func DecodePageCursor(token string) (*PageCursor, error) {
+	if token == "" {
+		return nil, errors.New("cursor cannot be empty")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("malformed cursor")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var cursor PageCursor
+	if err := decoder.Decode(&cursor); err != nil {
+		return nil, errors.New("malformed cursor")
+	}
+	return &cursor, nil
+}
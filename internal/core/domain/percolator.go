@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StandingQuery is a search query registered to be matched against every document as it is
+// saved, rather than searched over the documents already indexed
+type StandingQuery struct {
+	ID        string
+	Name      string
+	Query     SearchQuery
+	CreatedAt time.Time
+}
+
+// Validate checks that the standing query has a name and a query that can be matched against
+// a single document. Semantic queries rank by similarity and match every document, so they
+// cannot stand.
+func (q *StandingQuery) Validate() error {
+	if q.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if strings.TrimSpace(q.Query.Query) == "" {
+		return errors.New("search query cannot be empty")
+	}
+	if q.Query.IsVectorSearch() {
+		return errors.New("standing queries cannot use semantic search")
+	}
+	if q.Query.UseQuerySyntax {
+		if _, err := ParseQueryString(q.Query.Query); err != nil {
+			return fmt.Errorf("invalid query: %w", err)
+		}
+	}
+	return nil
+}
+
+// Compiled returns a copy of the query of the standing query ready to be matched, with its
+// query string parsed when it uses the query syntax
+func (q *StandingQuery) Compiled() (*SearchQuery, error) {
+	query := q.Query
+	query.Filters = make(map[string]interface{}, len(q.Query.Filters))
+	for name, value := range q.Query.Filters {
+		query.Filters[name] = value
+	}
+	if query.UseQuerySyntax {
+		if err := query.ApplyQuerySyntax(); err != nil {
+			return nil, err
+		}
+	}
+	return &query, nil
+}
+
+// PercolatorMatch is the event of a saved document matching a standing query
+type PercolatorMatch struct {
+	Query     *StandingQuery
+	Document  *Document
+	MatchedAt time.Time
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// resultPriorCTR is the click-through rate assumed for results without clicks
+	resultPriorCTR = 0.05
+	// resultPriorImpressions is the weight of the prior, in impressions
+	resultPriorImpressions = 20
+)
+
+// QueryLogEntry records a search as it was executed, along with the IDs of the documents
+// it returned in rank order. Offset is the number of results ranked before the returned page.
+type QueryLogEntry struct {
+	QueryID     string
+	Query       string
+	Filters     map[string]interface{}
+	TotalHits   int
+	LatencyMs   int64
+	Offset      int
+	DocumentIDs []string
+	LoggedAt    time.Time
+}
+
+// Validate checks that the entry is well-formed
+func (e *QueryLogEntry) Validate() error {
+	if e.QueryID == "" {
+		return errors.New("query ID cannot be empty")
+	}
+	if e.TotalHits < 0 {
+		return errors.New("total hits cannot be negative")
+	}
+	if e.LatencyMs < 0 {
+		return errors.New("latency cannot be negative")
+	}
+	if e.Offset < 0 {
+		return errors.New("offset cannot be negative")
+	}
+	return nil
+}
+
+// ResultClick records a user opening a result of a search
+type ResultClick struct {
+	QueryID    string
+	DocumentID string
+	Position   int
+	ClickedAt  time.Time
+}
+
+// Validate checks that the click is well-formed. Positions are 1-based ranks across pages.
+func (c *ResultClick) Validate() error {
+	if c.QueryID == "" {
+		return errors.New("query ID cannot be empty")
+	}
+	if c.DocumentID == "" {
+		return errors.New("document ID cannot be empty")
+	}
+	if c.Position < 1 {
+		return errors.New("position must be at least 1")
+	}
+	return nil
+}
+
+// DocumentClickStats aggregates how often a document was returned and clicked across all searches
+type DocumentClickStats struct {
+	DocumentID  string
+	Impressions int
+	Clicks      int
+}
+
+// SmoothedCTR returns the click-through rate of the document pulled towards a prior, so that
+// documents returned only a few times are neither promoted nor buried
+func (s DocumentClickStats) SmoothedCTR() float64 {
+	return (float64(s.Clicks) + resultPriorCTR*resultPriorImpressions) /
+		(float64(s.Impressions) + resultPriorImpressions)
+}
+
+// ClickedSearch is a logged search with at least one clicked result
+type ClickedSearch struct {
+	QueryID     string
+	Query       string
+	DocumentIDs []string
+	Clicked     []string
+}
+
+// AnalyticsWindow is the half-open time interval [From, To) covered by a report
+type AnalyticsWindow struct {
+	From time.Time
+	To   time.Time
+}
+
+// Validate checks that the window is not empty
+func (w AnalyticsWindow) Validate() error {
+	if w.From.IsZero() || w.To.IsZero() {
+		return errors.New("window bounds cannot be empty")
+	}
+	if !w.From.Before(w.To) {
+		return errors.New("window start must be before its end")
+	}
+	return nil
+}
+
+// QueryCount is the number of times a normalized query was searched
+type QueryCount struct {
+	Query string
+	Count int
+}
+
+// ClickStats aggregates the result clicks of a window
+type ClickStats struct {
+	Clicks      int
+	AvgPosition float64
+}
+
+// QueryReport summarizes the searches of a window
+type QueryReport struct {
+	Window            AnalyticsWindow
+	TotalQueries      int
+	TopQueries        []QueryCount
+	ZeroResultQueries []QueryCount
+	Clicks            int
+	AvgClickPosition  float64
+	LatencyP50Ms      float64
+	LatencyP95Ms      float64
+}
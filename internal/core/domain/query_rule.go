@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// QueryRuleMatchType is the way the condition of a query rule is compared with the query text
+type QueryRuleMatchType string
+
+const (
+	// ExactRuleMatch matches query texts equal to the pattern
+	ExactRuleMatch QueryRuleMatchType = "exact"
+	// ContainsRuleMatch matches query texts containing the pattern
+	ContainsRuleMatch QueryRuleMatchType = "contains"
+	// RegexRuleMatch matches query texts matching the pattern as a regular expression
+	RegexRuleMatch QueryRuleMatchType = "regex"
+)
+
+// QueryRule is an editorial rule of an index applied to the searches whose text matches its
+// condition: it pins documents to the top of the results, buries documents to the bottom of
+// their page, or redirects the search to a URL. A rule is active between its optional start
+// and end dates.
+type QueryRule struct {
+	ID          string
+	IndexID     string
+	Name        string
+	MatchType   QueryRuleMatchType
+	Pattern     string
+	PinnedIDs   []string
+	BuriedIDs   []string
+	RedirectURL string
+	StartAt     time.Time
+	EndAt       time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Validate checks that the rule belongs to an index, has a valid condition and does something
+func (r *QueryRule) Validate() error {
+	if r.IndexID == "" {
+		return errors.New("index ID cannot be empty")
+	}
+	if r.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if strings.TrimSpace(r.Pattern) == "" {
+		return errors.New("pattern cannot be empty")
+	}
+	switch r.MatchType {
+	case ExactRuleMatch, ContainsRuleMatch:
+	case RegexRuleMatch:
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("invalid regex pattern: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown match type %q", r.MatchType)
+	}
+	if len(r.PinnedIDs) == 0 && len(r.BuriedIDs) == 0 && r.RedirectURL == "" {
+		return errors.New("query rule must pin, bury or redirect")
+	}
+	for _, id := range r.PinnedIDs {
+		if id == "" {
+			return errors.New("pinned document ID cannot be empty")
+		}
+		for _, buried := range r.BuriedIDs {
+			if id == buried {
+				return fmt.Errorf("document %s cannot be both pinned and buried", id)
+			}
+		}
+	}
+	for _, id := range r.BuriedIDs {
+		if id == "" {
+			return errors.New("buried document ID cannot be empty")
+		}
+	}
+	if r.RedirectURL != "" {
+		target, err := url.Parse(r.RedirectURL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("invalid redirect URL %q", r.RedirectURL)
+		}
+	}
+	if !r.StartAt.IsZero() && !r.EndAt.IsZero() && !r.EndAt.After(r.StartAt) {
+		return errors.New("end date must be after start date")
+	}
+	return nil
+}
+
+// Active reports whether the rule applies at a time
+func (r *QueryRule) Active(at time.Time) bool {
+	if !r.StartAt.IsZero() && at.Before(r.StartAt) {
+		return false
+	}
+	return r.EndAt.IsZero() || at.Before(r.EndAt)
+}
+
+// Matches reports whether the condition of the rule matches a query text. Exact and contains
+// conditions ignore case and extra whitespace; regular expressions are matched against the
+// trimmed query text as written.
+func (r *QueryRule) Matches(queryText string) bool {
+	switch r.MatchType {
+	case ExactRuleMatch:
+		return normalizeRuleText(queryText) == normalizeRuleText(r.Pattern)
+	case ContainsRuleMatch:
+		return strings.Contains(normalizeRuleText(queryText), normalizeRuleText(r.Pattern))
+	case RegexRuleMatch:
+		pattern, err := regexp.Compile(r.Pattern)
+		return err == nil && pattern.MatchString(strings.TrimSpace(queryText))
+	}
+	return false
+}
+
+// normalizeRuleText lowercases a text and collapses its whitespace
+func normalizeRuleText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
@@ -0,0 +1,530 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// QueryField is a field a query string term can be scoped to, such as title:golang
+type QueryField string
+
+const (
+	AnyField     QueryField = ""
+	TitleField   QueryField = "title"
+	ContentField QueryField = "content"
+	URLField     QueryField = "url"
+	SiteField    QueryField = "site"
+	LangField    QueryField = "lang"
+	TypeField    QueryField = "type"
+	CrawledField QueryField = "crawled"
+)
+
+// queryFields are the fields accepted by the query string parser
+var queryFields = map[string]QueryField{
+	"title":   TitleField,
+	"content": ContentField,
+	"url":     URLField,
+	"site":    SiteField,
+	"lang":    LangField,
+	"type":    TypeField,
+	"crawled": CrawledField,
+}
+
+// IsFilter reports whether the field restricts the matching documents without contributing to relevance
+func (f QueryField) IsFilter() bool {
+	return f == SiteField || f == LangField || f == TypeField || f == CrawledField
+}
+
+// BoolOperator combines the clauses of a BoolNode
+type BoolOperator string
+
+const (
+	AndOperator BoolOperator = "AND"
+	OrOperator  BoolOperator = "OR"
+)
+
+// QueryNode is a node of the syntax tree of a parsed query string
+type QueryNode interface {
+	queryNode()
+}
+
+// TermNode matches a single word, optionally containing * and ? wildcards
+type TermNode struct {
+	Field    QueryField
+	Value    string
+	Wildcard bool
+}
+
+// PhraseNode matches a quoted sequence of words
+type PhraseNode struct {
+	Field QueryField
+	Text  string
+}
+
+// RangeNode matches the dates in the half-open interval [From, To). A zero bound is unbounded.
+type RangeNode struct {
+	Field QueryField
+	From  time.Time
+	To    time.Time
+}
+
+// BoolNode combines two or more clauses with AND or OR
+type BoolNode struct {
+	Operator BoolOperator
+	Clauses  []QueryNode
+}
+
+// NotNode matches the documents not matched by its clause
+type NotNode struct {
+	Clause QueryNode
+}
+
+func (*TermNode) queryNode()   {}
+func (*PhraseNode) queryNode() {}
+func (*RangeNode) queryNode()  {}
+func (*BoolNode) queryNode()   {}
+func (*NotNode) queryNode()    {}
+
+// QuerySyntaxError reports malformed query string input along with the 1-based column of the problem
+type QuerySyntaxError struct {
+	Position int
+	Message  string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Position, e.Message)
+}
+
+// ParseQueryString parses a query string into a syntax tree. The grammar supports quoted phrases,
+// AND, OR and NOT operators, -term exclusions, field scoping such as title:go or site:example.com,
+// crawl date ranges such as crawled:>2024-01-01 or crawled:2024-01-01..2024-06-30, * and ?
+// wildcards and grouping parentheses. Adjacent clauses are combined with AND, which binds tighter than OR.
+func ParseQueryString(input string) (QueryNode, error) {
+	tokens, err := lexQueryString(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &QuerySyntaxError{Position: 1, Message: "query is empty"}
+	}
+	node, err := p.parseOr(AnyField)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &QuerySyntaxError{Position: tok.pos, Message: fmt.Sprintf("unexpected %s", tok)}
+	}
+	return node, nil
+}
+
+// ApplyQuerySyntax parses the query text into Expression. Top-level filters on the language,
+// content type and crawl date are moved to Language, Filters and TimeRange when those are unset,
+// so that every repository and search mode applies them.
+func (q *SearchQuery) ApplyQuerySyntax() error {
+	node, err := ParseQueryString(q.Query)
+	if err != nil {
+		return err
+	}
+
+	clauses := []QueryNode{node}
+	if b, ok := node.(*BoolNode); ok && b.Operator == AndOperator {
+		clauses = b.Clauses
+	}
+	remaining := make([]QueryNode, 0, len(clauses))
+	for _, clause := range clauses {
+		if !q.hoistFilter(clause) {
+			remaining = append(remaining, clause)
+		}
+	}
+
+	switch len(remaining) {
+	case 0:
+		q.Expression = nil
+	case 1:
+		q.Expression = remaining[0]
+	default:
+		q.Expression = &BoolNode{Operator: AndOperator, Clauses: remaining}
+	}
+	return nil
+}
+
+// hoistFilter moves a filter clause to the matching SearchQuery field and reports whether it did
+func (q *SearchQuery) hoistFilter(node QueryNode) bool {
+	switch n := node.(type) {
+	case *TermNode:
+		if n.Wildcard {
+			return false
+		}
+		switch n.Field {
+		case LangField:
+			if q.Language != "" {
+				return false
+			}
+			q.Language = n.Value
+			return true
+		case TypeField:
+			if !strings.Contains(n.Value, "/") {
+				return false
+			}
+			if q.Filters == nil {
+				q.Filters = make(map[string]interface{})
+			}
+			if current, ok := q.Filters["content_type"].(string); ok && current != "" {
+				return false
+			}
+			q.Filters["content_type"] = n.Value
+			return true
+		}
+	case *RangeNode:
+		// Repositories only apply time ranges with a lower bound
+		if q.TimeRange != nil || n.From.IsZero() {
+			return false
+		}
+		q.TimeRange = &TimeRange{Field: "last_crawled", From: n.From, Included: true}
+		if !n.To.IsZero() {
+			q.TimeRange.To = n.To.Add(-time.Nanosecond)
+		}
+		return true
+	}
+	return false
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenPhrase
+	tokenField
+	tokenLParen
+	tokenRParen
+	tokenMinus
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+// queryToken is a lexical token of a query string; pos is the 1-based column of its first character
+type queryToken struct {
+	kind  tokenKind
+	text  string
+	pos   int
+	glued bool
+}
+
+func (t queryToken) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenPhrase:
+		return fmt.Sprintf("phrase %q", t.text)
+	case tokenField:
+		return fmt.Sprintf("field %q", t.text+":")
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// lexQueryString splits a query string into tokens. A token is glued when it directly follows
+// the previous one without whitespace, which is how a field prefix finds its value.
+func lexQueryString(input string) ([]queryToken, error) {
+	runes := []rune(input)
+	tokens := make([]queryToken, 0)
+	glued := false
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			glued = false
+			continue
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen, text: "(", pos: pos, glued: glued})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen, text: ")", pos: pos, glued: glued})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, &QuerySyntaxError{Position: pos, Message: "unterminated phrase"}
+			}
+			tokens = append(tokens, queryToken{kind: tokenPhrase, text: string(runes[i+1 : end]), pos: pos, glued: glued})
+			i = end + 1
+		case r == '-' && (i == 0 || unicode.IsSpace(runes[i-1]) || runes[i-1] == '(') && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			tokens = append(tokens, queryToken{kind: tokenMinus, text: "-", pos: pos, glued: glued})
+			i++
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			fieldTokens, err := lexWord(word, pos, glued)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, fieldTokens...)
+			i = end
+		}
+		glued = true
+	}
+	return append(tokens, queryToken{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// lexWord classifies a word as an operator, a field prefix with its optional value, or a plain word
+func lexWord(word string, pos int, glued bool) ([]queryToken, error) {
+	switch word {
+	case "AND":
+		return []queryToken{{kind: tokenAnd, text: word, pos: pos, glued: glued}}, nil
+	case "OR":
+		return []queryToken{{kind: tokenOr, text: word, pos: pos, glued: glued}}, nil
+	case "NOT":
+		return []queryToken{{kind: tokenNot, text: word, pos: pos, glued: glued}}, nil
+	}
+
+	name, value, found := strings.Cut(word, ":")
+	// URLs such as https://example.com are searched as plain words
+	if !found || name == "" || strings.HasPrefix(value, "//") || !isFieldName(name) {
+		return []queryToken{{kind: tokenWord, text: word, pos: pos, glued: glued}}, nil
+	}
+	if _, ok := queryFields[strings.ToLower(name)]; !ok {
+		return nil, &QuerySyntaxError{Position: pos, Message: fmt.Sprintf("unknown field %q", name)}
+	}
+
+	tokens := []queryToken{{kind: tokenField, text: strings.ToLower(name), pos: pos, glued: glued}}
+	if value != "" {
+		tokens = append(tokens, queryToken{kind: tokenWord, text: value, pos: pos + len([]rune(name)) + 1, glued: true})
+	}
+	return tokens, nil
+}
+
+// isFieldName reports whether a word prefix has the shape of a field name
+func isFieldName(name string) bool {
+	for _, r := range name {
+		if !unicode.IsLetter(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// queryParser is a recursive descent parser over the tokens of a query string
+type queryParser struct {
+	tokens []queryToken
+	index  int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.index]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.index]
+	if tok.kind != tokenEOF {
+		p.index++
+	}
+	return tok
+}
+
+// parseOr parses clauses separated by OR
+func (p *queryParser) parseOr(field QueryField) (QueryNode, error) {
+	clauses := make([]QueryNode, 0, 1)
+	for {
+		clause, err := p.parseAnd(field)
+		if err != nil {
+			return nil, err
+		}
+		clauses = appendClause(clauses, clause, OrOperator)
+		if p.peek().kind != tokenOr {
+			break
+		}
+		p.next()
+	}
+	return combineClauses(clauses, OrOperator), nil
+}
+
+// parseAnd parses clauses separated by AND or by nothing at all
+func (p *queryParser) parseAnd(field QueryField) (QueryNode, error) {
+	clauses := make([]QueryNode, 0, 1)
+	for {
+		clause, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		clauses = appendClause(clauses, clause, AndOperator)
+
+		tok := p.peek()
+		if tok.kind == tokenAnd {
+			p.next()
+			continue
+		}
+		if tok.kind == tokenEOF || tok.kind == tokenOr || tok.kind == tokenRParen {
+			break
+		}
+	}
+	return combineClauses(clauses, AndOperator), nil
+}
+
+// parseUnary parses an optionally negated clause
+func (p *queryParser) parseUnary(field QueryField) (QueryNode, error) {
+	tok := p.peek()
+	if tok.kind == tokenNot || tok.kind == tokenMinus {
+		p.next()
+		clause, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Clause: clause}, nil
+	}
+	return p.parsePrimary(field)
+}
+
+// parsePrimary parses a group, a field scoped clause, a phrase or a word
+func (p *queryParser) parsePrimary(field QueryField) (QueryNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		node, err := p.parseOr(field)
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, &QuerySyntaxError{Position: tok.pos, Message: "missing closing parenthesis"}
+		}
+		p.next()
+		return node, nil
+	case tokenField:
+		if field != AnyField {
+			return nil, &QuerySyntaxError{Position: tok.pos, Message: fmt.Sprintf("field %q cannot be nested in field %q", tok.text, field)}
+		}
+		value := p.peek()
+		if !value.glued || (value.kind != tokenWord && value.kind != tokenPhrase && value.kind != tokenLParen) {
+			return nil, &QuerySyntaxError{Position: tok.pos, Message: fmt.Sprintf("field %q requires a value", tok.text)}
+		}
+		return p.parsePrimary(queryFields[tok.text])
+	case tokenPhrase:
+		return newPhraseNode(field, tok)
+	case tokenWord:
+		return newTermNode(field, tok)
+	case tokenEOF:
+		return nil, &QuerySyntaxError{Position: tok.pos, Message: "unexpected end of query"}
+	default:
+		return nil, &QuerySyntaxError{Position: tok.pos, Message: fmt.Sprintf("unexpected %s", tok)}
+	}
+}
+
+// newTermNode creates the node of a word, parsing it as a date range for the crawled field
+func newTermNode(field QueryField, tok queryToken) (QueryNode, error) {
+	if field == CrawledField {
+		return parseDateRange(tok.text, tok.pos)
+	}
+	value := tok.text
+	if field == SiteField || field == LangField || field == TypeField {
+		value = strings.ToLower(value)
+	}
+	wildcard := strings.ContainsAny(value, "*?")
+	// Bare content types such as type:pdf match every MIME type with that subtype
+	if field == TypeField && !strings.Contains(value, "/") {
+		value = "*/" + value
+		wildcard = true
+	}
+	return &TermNode{Field: field, Value: value, Wildcard: wildcard}, nil
+}
+
+// newPhraseNode creates the node of a quoted phrase
+func newPhraseNode(field QueryField, tok queryToken) (QueryNode, error) {
+	text := strings.TrimSpace(tok.text)
+	if text == "" {
+		return nil, &QuerySyntaxError{Position: tok.pos, Message: "phrase cannot be empty"}
+	}
+	if field.IsFilter() {
+		return newTermNode(field, queryToken{kind: tokenWord, text: text, pos: tok.pos + 1})
+	}
+	return &PhraseNode{Field: field, Text: text}, nil
+}
+
+// parseDateRange parses a crawl date condition: >date, >=date, <date, <=date, from..to or a single date
+func parseDateRange(value string, pos int) (*RangeNode, error) {
+	if from, to, ok := strings.Cut(value, ".."); ok {
+		node := &RangeNode{Field: CrawledField}
+		var err error
+		if from != "" {
+			if node.From, err = parseQueryDate(from, pos); err != nil {
+				return nil, err
+			}
+		}
+		if to != "" {
+			if node.To, err = parseQueryDate(to, pos+len(from)+2); err != nil {
+				return nil, err
+			}
+			node.To = node.To.AddDate(0, 0, 1)
+		}
+		if node.From.IsZero() && node.To.IsZero() {
+			return nil, &QuerySyntaxError{Position: pos, Message: "date range needs at least one bound"}
+		}
+		if !node.To.IsZero() && !node.From.Before(node.To) {
+			return nil, &QuerySyntaxError{Position: pos, Message: "date range ends before it starts"}
+		}
+		return node, nil
+	}
+
+	operator := ""
+	for _, candidate := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(value, candidate) {
+			operator = candidate
+			break
+		}
+	}
+	date, err := parseQueryDate(value[len(operator):], pos+len(operator))
+	if err != nil {
+		return nil, err
+	}
+
+	// Dates cover whole days, so each condition is converted to a half-open interval of days
+	nextDay := date.AddDate(0, 0, 1)
+	switch operator {
+	case ">":
+		return &RangeNode{Field: CrawledField, From: nextDay}, nil
+	case ">=":
+		return &RangeNode{Field: CrawledField, From: date}, nil
+	case "<":
+		return &RangeNode{Field: CrawledField, To: date}, nil
+	case "<=":
+		return &RangeNode{Field: CrawledField, To: nextDay}, nil
+	default:
+		return &RangeNode{Field: CrawledField, From: date, To: nextDay}, nil
+	}
+}
+
+// parseQueryDate parses a YYYY-MM-DD date in UTC
+func parseQueryDate(value string, pos int) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, &QuerySyntaxError{Position: pos, Message: fmt.Sprintf("invalid date %q, expected YYYY-MM-DD", value)}
+	}
+	return date, nil
+}
+
+// appendClause adds a clause, flattening nested clauses combined with the same operator
+func appendClause(clauses []QueryNode, clause QueryNode, operator BoolOperator) []QueryNode {
+	if b, ok := clause.(*BoolNode); ok && b.Operator == operator {
+		return append(clauses, b.Clauses...)
+	}
+	return append(clauses, clause)
+}
+
+// combineClauses returns the single clause, or a BoolNode combining several
+func combineClauses(clauses []QueryNode, operator BoolOperator) QueryNode {
+	if len(clauses) == 1 {
+		return clauses[0]
+	}
+	return &BoolNode{Operator: operator, Clauses: clauses}
+}
@@ -0,0 +1,251 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Features computed for each candidate of a re-ranked search
+const (
+	FeatureBM25Title      = "bm25_title"
+	FeatureBM25MetaDesc   = "bm25_meta_desc"
+	FeatureBM25Content    = "bm25_content"
+	FeatureRetrievalScore = "retrieval_score"
+	FeatureImportanceRank = "importance_rank"
+	FeatureFreshness      = "freshness"
+	FeatureTitleMatch     = "title_match"
+	FeatureKeywordScore   = "keyword_score"
+	FeatureClickThrough   = "ctr"
+)
+
+// RankingFeatures lists every feature a ranking model can use
+var RankingFeatures = []string{
+	FeatureBM25Title,
+	FeatureBM25MetaDesc,
+	FeatureBM25Content,
+	FeatureRetrievalScore,
+	FeatureImportanceRank,
+	FeatureFreshness,
+	FeatureTitleMatch,
+	FeatureKeywordScore,
+	FeatureClickThrough,
+}
+
+// FeatureVector holds the feature values of a candidate by feature name
+type FeatureVector map[string]float64
+
+// Values returns the values of the named features, in order
+func (v FeatureVector) Values(names []string) []float64 {
+	values := make([]float64, len(names))
+	for i, name := range names {
+		values[i] = v[name]
+	}
+	return values
+}
+
+// RankingModelType defines the kind of function a ranking model applies
+type RankingModelType string
+
+const (
+	LinearModel       RankingModelType = "linear"
+	TreeEnsembleModel RankingModelType = "gbdt"
+)
+
+// RankingModel scores candidates from their features. A linear model sums the weighted
+// features; a tree ensemble sums the outputs of its regression trees. Both add the bias.
+type RankingModel struct {
+	Type     RankingModelType `json:"type"`
+	Features []string         `json:"features"`
+	Weights  []float64        `json:"weights,omitempty"`
+	Trees    []RegressionTree `json:"trees,omitempty"`
+	Bias     float64          `json:"bias"`
+}
+
+// RegressionTree is a binary tree stored as a node array whose root is the first node
+type RegressionTree struct {
+	Nodes []TreeNode `json:"nodes"`
+}
+
+// TreeNode is a split sending feature values up to the threshold left, or a leaf holding an output
+type TreeNode struct {
+	Feature   int     `json:"feature"`
+	Threshold float64 `json:"threshold"`
+	Left      int     `json:"left"`
+	Right     int     `json:"right"`
+	Leaf      bool    `json:"leaf"`
+	Value     float64 `json:"value"`
+}
+
+// ParseRankingModel reads a ranking model from its JSON form and validates it
+func ParseRankingModel(data []byte) (*RankingModel, error) {
+	var model RankingModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("invalid ranking model: %w", err)
+	}
+	if err := model.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ranking model: %w", err)
+	}
+	return &model, nil
+}
+
+// Validate checks that the model only uses known features and that its trees are well-formed
+func (m *RankingModel) Validate() error {
+	if len(m.Features) == 0 {
+		return errors.New("model must use at least one feature")
+	}
+	known := make(map[string]bool, len(RankingFeatures))
+	for _, name := range RankingFeatures {
+		known[name] = true
+	}
+	for _, name := range m.Features {
+		if !known[name] {
+			return fmt.Errorf("unknown feature %q", name)
+		}
+	}
+
+	switch m.Type {
+	case LinearModel:
+		if len(m.Weights) != len(m.Features) {
+			return errors.New("linear model needs one weight per feature")
+		}
+	case TreeEnsembleModel:
+		if len(m.Trees) == 0 {
+			return errors.New("tree ensemble needs at least one tree")
+		}
+		for i, tree := range m.Trees {
+			if err := tree.validate(len(m.Features)); err != nil {
+				return fmt.Errorf("tree %d: %w", i, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported model type %q", m.Type)
+	}
+	return nil
+}
+
+// Score returns the score of a candidate whose feature values are ordered as the model features
+func (m *RankingModel) Score(values []float64) float64 {
+	score := m.Bias
+	switch m.Type {
+	case LinearModel:
+		for i, weight := range m.Weights {
+			score += weight * values[i]
+		}
+	case TreeEnsembleModel:
+		for _, tree := range m.Trees {
+			score += tree.Predict(values)
+		}
+	}
+	return score
+}
+
+// Explain returns the contributions of the features or trees of the model to the score of
+// a candidate whose feature values are ordered as the model features
+func (m *RankingModel) Explain(values []float64) *ScoreExplanation {
+	details := []*ScoreExplanation{NewScoreExplanation(m.Bias, "bias")}
+	switch m.Type {
+	case LinearModel:
+		for i, weight := range m.Weights {
+			details = append(details, NewScoreExplanation(weight*values[i],
+				fmt.Sprintf("%s: weight %.4g × value %.4g", m.Features[i], weight, values[i])))
+		}
+	case TreeEnsembleModel:
+		for i, tree := range m.Trees {
+			details = append(details, NewScoreExplanation(tree.Predict(values), fmt.Sprintf("tree %d", i)))
+		}
+	}
+	return NewScoreExplanation(m.Score(values), fmt.Sprintf("%s model score, sum of:", m.Type), details...)
+}
+
+// Predict returns the output of the leaf the feature values fall into
+func (t RegressionTree) Predict(values []float64) float64 {
+	node := t.Nodes[0]
+	for !node.Leaf {
+		if values[node.Feature] <= node.Threshold {
+			node = t.Nodes[node.Left]
+		} else {
+			node = t.Nodes[node.Right]
+		}
+	}
+	return node.Value
+}
+
+// validate checks that every split uses a model feature and only points to later nodes,
+// so that prediction always reaches a leaf
+func (t RegressionTree) validate(features int) error {
+	if len(t.Nodes) == 0 {
+		return errors.New("tree has no nodes")
+	}
+	for i, node := range t.Nodes {
+		if node.Leaf {
+			continue
+		}
+		if node.Feature < 0 || node.Feature >= features {
+			return fmt.Errorf("node %d splits on unknown feature %d", i, node.Feature)
+		}
+		if node.Left <= i || node.Left >= len(t.Nodes) || node.Right <= i || node.Right >= len(t.Nodes) {
+			return fmt.Errorf("node %d has invalid children", i)
+		}
+	}
+	return nil
+}
+
+// RankingSample is a training example: the features of a candidate of a query and its target
+// relevance. Samples of the same group are ranked against each other.
+type RankingSample struct {
+	Group    string        `json:"group"`
+	Document string        `json:"document"`
+	Features FeatureVector `json:"features"`
+	Label    float64       `json:"label"`
+}
+
+// RankingTrainingOptions configures the training of a ranking model. Iterations counts epochs
+// over the training pairs of a linear model and trees of a tree ensemble.
+type RankingTrainingOptions struct {
+	Type           RankingModelType
+	Features       []string
+	Iterations     int
+	LearningRate   float64
+	MaxDepth       int
+	MinLeafSamples int
+}
+
+// DefaultRankingTrainingOptions returns the training options of a model type, using every feature
+func DefaultRankingTrainingOptions(modelType RankingModelType) RankingTrainingOptions {
+	options := RankingTrainingOptions{
+		Type:     modelType,
+		Features: RankingFeatures,
+	}
+	switch modelType {
+	case TreeEnsembleModel:
+		options.Iterations = 50
+		options.LearningRate = 0.1
+		options.MaxDepth = 3
+		options.MinLeafSamples = 5
+	default:
+		options.Iterations = 100
+		options.LearningRate = 0.05
+	}
+	return options
+}
+
+// Validate checks that the options can train a model
+func (o RankingTrainingOptions) Validate() error {
+	if o.Type != LinearModel && o.Type != TreeEnsembleModel {
+		return fmt.Errorf("unsupported model type %q", o.Type)
+	}
+	if len(o.Features) == 0 {
+		return errors.New("training needs at least one feature")
+	}
+	if o.Iterations <= 0 {
+		return errors.New("iterations must be positive")
+	}
+	if o.LearningRate <= 0 {
+		return errors.New("learning rate must be positive")
+	}
+	if o.Type == TreeEnsembleModel && (o.MaxDepth <= 0 || o.MinLeafSamples <= 0) {
+		return errors.New("tree depth and leaf size must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Judgment grades how relevant a document is to a query. The document is referenced by
+// its ID or its URL; a grade of zero marks a document judged not relevant.
+type Judgment struct {
+	Query    string
+	Document string
+	Grade    int
+}
+
+// Validate checks that the judgment is well-formed
+func (j Judgment) Validate() error {
+	if strings.TrimSpace(j.Query) == "" {
+		return errors.New("judgment query cannot be empty")
+	}
+	if strings.TrimSpace(j.Document) == "" {
+		return errors.New("judged document cannot be empty")
+	}
+	if j.Grade < 0 {
+		return errors.New("judgment grade cannot be negative")
+	}
+	return nil
+}
+
+// RankingConfig is a named set of search options whose ranking is evaluated
+type RankingConfig struct {
+	Name                string
+	Type                SearchType
+	SearchFields        map[string]float32
+	MinimumShouldMatch  string
+	SkipDiversification bool
+	SkipReranking       bool
+	SkipFreshness       bool
+	DecayFunctions      []DecayFunction
+}
+
+// Apply sets the options of the configuration on a search query
+func (c RankingConfig) Apply(query *SearchQuery) {
+	if c.Type != "" {
+		query.Type = c.Type
+	}
+	if len(c.SearchFields) > 0 {
+		query.SearchFields = c.SearchFields
+	}
+	query.MinimumShouldMatch = c.MinimumShouldMatch
+	query.SkipDiversification = c.SkipDiversification
+	query.SkipReranking = c.SkipReranking
+	query.SkipFreshness = c.SkipFreshness
+	if len(c.DecayFunctions) > 0 {
+		query.DecayFunctions = c.DecayFunctions
+	}
+}
+
+// JudgedQuery holds the grades of the documents judged for a query, by document reference
+type JudgedQuery struct {
+	Query  string
+	Grades map[string]int
+}
+
+// Grade returns the grade of a document judged by ID or URL, and whether it was judged
+func (q JudgedQuery) Grade(document *Document) (int, bool) {
+	if grade, ok := q.Grades[document.ID]; ok {
+		return grade, true
+	}
+	grade, ok := q.Grades[document.URL]
+	return grade, ok
+}
+
+// GroupJudgments groups judgments by query, keeping the order in which queries first appear.
+// A document judged twice for a query keeps its last grade.
+func GroupJudgments(judgments []Judgment) ([]JudgedQuery, error) {
+	if len(judgments) == 0 {
+		return nil, errors.New("judgments cannot be empty")
+	}
+	queries := make([]JudgedQuery, 0)
+	positions := make(map[string]int)
+	for i, judgment := range judgments {
+		if err := judgment.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid judgment %d: %w", i+1, err)
+		}
+		query := strings.TrimSpace(judgment.Query)
+		position, ok := positions[query]
+		if !ok {
+			position = len(queries)
+			positions[query] = position
+			queries = append(queries, JudgedQuery{Query: query, Grades: make(map[string]int)})
+		}
+		queries[position].Grades[strings.TrimSpace(judgment.Document)] = judgment.Grade
+	}
+	return queries, nil
+}
+
+// RelevanceMetrics measures the quality of a ranking at a cutoff k
+type RelevanceMetrics struct {
+	NDCG      float64
+	MRR       float64
+	Precision float64
+	Recall    float64
+}
+
+// Sub returns the difference between two sets of metrics
+func (m RelevanceMetrics) Sub(other RelevanceMetrics) RelevanceMetrics {
+	return RelevanceMetrics{
+		NDCG:      m.NDCG - other.NDCG,
+		MRR:       m.MRR - other.MRR,
+		Precision: m.Precision - other.Precision,
+		Recall:    m.Recall - other.Recall,
+	}
+}
+
+// QueryEvaluation holds the ranking returned for a judged query and its metrics
+type QueryEvaluation struct {
+	Query   string
+	Ranking []string
+	Metrics RelevanceMetrics
+}
+
+// EvaluationReport holds the metrics of a ranking configuration over a judgment list.
+// Mean averages the metrics of all queries.
+type EvaluationReport struct {
+	Config  string
+	K       int
+	Queries []QueryEvaluation
+	Mean    RelevanceMetrics
+}
+
+// QueryComparison holds the change of the metrics of one query between two configurations
+type QueryComparison struct {
+	Query     string
+	Baseline  RelevanceMetrics
+	Candidate RelevanceMetrics
+	Delta     RelevanceMetrics
+}
+
+// EvaluationComparison diffs the evaluations of two ranking configurations
+type EvaluationComparison struct {
+	Baseline  *EvaluationReport
+	Candidate *EvaluationReport
+	Delta     RelevanceMetrics
+	Queries   []QueryComparison
+}
+
+// EvaluateRanking computes the metrics of a ranking at cutoff k. The ranking lists document
+// references in rank order; grades maps the judged references of the query to their grade.
+// Gains are graded exponentially (2^grade - 1) for NDCG, and any positive grade counts as
+// relevant for the other metrics.
+func EvaluateRanking(ranking []string, grades map[string]int, k int) RelevanceMetrics {
+	if k <= 0 {
+		return RelevanceMetrics{}
+	}
+	if len(ranking) > k {
+		ranking = ranking[:k]
+	}
+
+	var metrics RelevanceMetrics
+	dcg := 0.0
+	relevantRetrieved := 0
+	for i, ref := range ranking {
+		grade := grades[ref]
+		if grade <= 0 {
+			continue
+		}
+		dcg += gain(grade) / math.Log2(float64(i+2))
+		relevantRetrieved++
+		if metrics.MRR == 0 {
+			metrics.MRR = 1 / float64(i+1)
+		}
+	}
+
+	ideal := make([]int, 0, len(grades))
+	relevant := 0
+	for _, grade := range grades {
+		if grade > 0 {
+			ideal = append(ideal, grade)
+			relevant++
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ideal)))
+	idcg := 0.0
+	for i, grade := range ideal {
+		if i == k {
+			break
+		}
+		idcg += gain(grade) / math.Log2(float64(i+2))
+	}
+
+	if idcg > 0 {
+		metrics.NDCG = dcg / idcg
+	}
+	metrics.Precision = float64(relevantRetrieved) / float64(k)
+	if relevant > 0 {
+		metrics.Recall = float64(relevantRetrieved) / float64(relevant)
+	}
+	return metrics
+}
+
+// MeanMetrics averages the metrics of the evaluated queries
+func MeanMetrics(evaluations []QueryEvaluation) RelevanceMetrics {
+	var mean RelevanceMetrics
+	if len(evaluations) == 0 {
+		return mean
+	}
+	for _, evaluation := range evaluations {
+		mean.NDCG += evaluation.Metrics.NDCG
+		mean.MRR += evaluation.Metrics.MRR
+		mean.Precision += evaluation.Metrics.Precision
+		mean.Recall += evaluation.Metrics.Recall
+	}
+	n := float64(len(evaluations))
+	return RelevanceMetrics{
+		NDCG:      mean.NDCG / n,
+		MRR:       mean.MRR / n,
+		Precision: mean.Precision / n,
+		Recall:    mean.Recall / n,
+	}
+}
+
+// gain returns the exponential gain of a grade
+func gain(grade int) float64 {
+	return math.Pow(2, float64(grade)) - 1
+}
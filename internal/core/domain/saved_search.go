@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+)
+
+// AlertChannelType is the way the new matches of a saved search are delivered
+type AlertChannelType string
+
+const (
+	WebhookChannel AlertChannelType = "webhook"
+	EmailChannel   AlertChannelType = "email"
+	FileChannel    AlertChannelType = "file"
+)
+
+// AlertChannel is a destination of the alerts of a saved search. The target is the URL of a
+// webhook or the address of an email; file channels write to the file of their notifier and
+// have no target.
+type AlertChannel struct {
+	Type   AlertChannelType
+	Target string
+}
+
+// Validate checks that the channel has a known type and a target it can be delivered to
+func (c AlertChannel) Validate() error {
+	switch c.Type {
+	case WebhookChannel:
+		target, err := url.Parse(c.Target)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("invalid webhook URL %q", c.Target)
+		}
+	case EmailChannel:
+		if _, err := mail.ParseAddress(c.Target); err != nil {
+			return fmt.Errorf("invalid email address %q", c.Target)
+		}
+	case FileChannel:
+		if c.Target != "" {
+			return errors.New("file alert channels cannot set a target")
+		}
+	default:
+		return fmt.Errorf("unknown alert channel %q", c.Type)
+	}
+	return nil
+}
+
+// SavedSearch is a search query of a user evaluated periodically for documents crawled since
+// it last ran, whose new matches are delivered to its channels
+type SavedSearch struct {
+	ID        string
+	UserID    string
+	Name      string
+	Query     SearchQuery
+	Channels  []AlertChannel
+	LastRunAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Validate checks that the saved search belongs to a user, has a valid query and can alert.
+// Saved searches are restricted to the documents crawled since they last ran, so their query
+// cannot set its own time range or paginate.
+func (s *SavedSearch) Validate() error {
+	if s.UserID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	if s.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if s.Query.TimeRange != nil {
+		return errors.New("saved search queries cannot set a time range")
+	}
+	if s.Query.UseSearchAfter || s.Query.Cursor != "" {
+		return errors.New("saved search queries cannot use search after")
+	}
+	if err := s.Query.Validate(); err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	if len(s.Channels) == 0 {
+		return errors.New("saved search needs at least one alert channel")
+	}
+	for _, channel := range s.Channels {
+		if err := channel.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Since returns the time new matches are searched from: the last run, or the creation of the
+// saved search when it never ran
+func (s *SavedSearch) Since() time.Time {
+	if s.LastRunAt.IsZero() {
+		return s.CreatedAt
+	}
+	return s.LastRunAt
+}
+
+// CrawledBetween returns the first page of the query of the saved search restricted to the
+// documents crawled after since and until until. The page size of the query bounds the
+// documents of an alert.
+func (s *SavedSearch) CrawledBetween(since, until time.Time) *SearchQuery {
+	query := s.Query
+	query.Page = 1
+	query.TimeRange = &TimeRange{Field: "last_crawled", From: since, To: until}
+	// Scheduled runs are not searches of users, so they stay out of the analytics and suggestions
+	query.SkipAnalytics = true
+	return &query
+}
+
+// SavedSearchAlert holds the documents newly matching a saved search within a crawl window
+type SavedSearchAlert struct {
+	SavedSearch *SavedSearch
+	Since       time.Time
+	Until       time.Time
+	TotalHits   int
+	Documents   []*Document
+}
+
+// SavedSearchFailure records a saved search that could not be evaluated or alerted
+type SavedSearchFailure struct {
+	SavedSearchID string
+	Name          string
+	Error         string
+}
+
+// SavedSearchReport summarizes an evaluation of the saved searches
+type SavedSearchReport struct {
+	Evaluated int
+	Alerted   int
+	Failures  []SavedSearchFailure
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScoreExplanation is a node of the tree explaining how the score of a search hit was computed.
+// The value of a node combines the values of its details as its description says.
+type ScoreExplanation struct {
+	Value       float64
+	Description string
+	Details     []*ScoreExplanation
+}
+
+// NewScoreExplanation creates an explanation node
+func NewScoreExplanation(value float64, description string, details ...*ScoreExplanation) *ScoreExplanation {
+	return &ScoreExplanation{
+		Value:       value,
+		Description: description,
+		Details:     details,
+	}
+}
+
+// String renders the explanation as an indented tree, one node per line
+func (e *ScoreExplanation) String() string {
+	var b strings.Builder
+	e.write(&b, 0)
+	return b.String()
+}
+
+// write renders the node and its details at the given depth
+func (e *ScoreExplanation) write(b *strings.Builder, depth int) {
+	if e == nil {
+		return
+	}
+	fmt.Fprintf(b, "%s%.6g %s\n", strings.Repeat("  ", depth), e.Value, e.Description)
+	for _, detail := range e.Details {
+		detail.write(b, depth+1)
+	}
+}
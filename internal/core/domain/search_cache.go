@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// CacheStats reports the activity of the search result cache
+type CacheStats struct {
+	Hits          int64
+	Misses        int64
+	Evictions     int64
+	Invalidations int64
+	Entries       int
+	Bytes         int64
+}
+
+// HitRate returns the share of lookups answered from the cache
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// CacheKey returns a key identifying the results of the query. Queries differing only by the
+// case and spacing of their text, or by options that do not change the results, share a key.
+func (q *SearchQuery) CacheKey() string {
+	canonical := *q
+	if !canonical.UseQuerySyntax {
+		canonical.Query = NormalizeQuery(q.Query)
+	} else {
+		canonical.Query = strings.Join(strings.Fields(q.Query), " ")
+	}
+	// The expression and vector are derived from the query text; metadata does not affect results
+	canonical.Expression = nil
+	canonical.Vector = nil
+	canonical.Metadata = nil
+	if len(canonical.Filters) == 0 {
+		canonical.Filters = nil
+	}
+	if len(canonical.EntityFilters) == 0 {
+		canonical.EntityFilters = nil
+	}
+	if len(canonical.SearchFields) == 0 {
+		canonical.SearchFields = nil
+	}
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// CacheIndexIDs returns the indexes the results of the query come from, or nil when the query
+// searches every index
+func (q *SearchQuery) CacheIndexIDs() []string {
+	if q.IsFederated() {
+		return q.IndexIDs()
+	}
+	switch indexID := q.Filters["index_id"].(type) {
+	case string:
+		if indexID != "" {
+			return []string{indexID}
+		}
+	case []string:
+		if len(indexID) > 0 {
+			return indexID
+		}
+	}
+	return nil
+}
+
+// Clone returns a copy of the result whose documents, facets and counts can be changed without
+// affecting the original
+func (r *SearchResult) Clone() *SearchResult {
+	clone := *r
+	clone.Documents = make([]*Document, len(r.Documents))
+	for i, doc := range r.Documents {
+		copied := *doc
+		clone.Documents[i] = &copied
+	}
+	clone.Facets = append([]FacetResult(nil), r.Facets...)
+	clone.AppliedRules = append([]string(nil), r.AppliedRules...)
+	if r.Highlighting != nil {
+		clone.Highlighting = make(map[string]map[string][]string, len(r.Highlighting))
+		for documentID, highlights := range r.Highlighting {
+			clone.Highlighting[documentID] = highlights
+		}
+	}
+	if r.IndexHits != nil {
+		clone.IndexHits = make(map[string]int, len(r.IndexHits))
+		for indexID, hits := range r.IndexHits {
+			clone.IndexHits[indexID] = hits
+		}
+	}
+	return &clone
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -11,6 +12,7 @@ type SearchQuery struct {
 	Query               string
 	Type                SearchType
 	Filters             map[string]interface{}
+	Indexes             []IndexWeight
 	Page                int
 	PageSize            int
 	SortFields          []string
@@ -18,6 +20,7 @@ type SearchQuery struct {
 	IncludeFields       []string
 	ExcludeFields       []string
 	HighlightFields     []string
+	Highlight           HighlightOptions
 	TimeRange           *TimeRange
 	Language            string
 	FuzzyLevel          int
@@ -27,7 +30,18 @@ type SearchQuery struct {
 	EntityFilters       map[EntityType][]string
 	SearchFields        map[string]float32
 	SkipDiversification bool
+	SkipReranking       bool
+	SkipFreshness       bool
+	SkipAnalytics       bool
+	Explain             bool
+	AutoCorrect         bool
 	UseSearchAfter      bool
+	Cursor              string
+	UseQuerySyntax      bool
+	Expression          QueryNode
+	Vector              []float32
+	Facets              []FacetRequest
+	DecayFunctions      []DecayFunction
 	Metadata            map[string]interface{}
 }
 
@@ -39,6 +53,7 @@ const (
 	ExactMatchSearch SearchType = "exact"
 	FuzzySearch      SearchType = "fuzzy"
 	SemanticSearch   SearchType = "semantic"
+	HybridSearch     SearchType = "hybrid"
 )
 
 // SortOrder defines the order of search results
@@ -57,6 +72,9 @@ type TimeRange struct {
 	Included bool
 }
 
+// MaxPageSize is the largest number of results a search query can request
+const MaxPageSize = 100
+
 // NewSearchQuery creates a new search query with default values and validation
 func NewSearchQuery(queryText string) (*SearchQuery, error) {
 	if strings.TrimSpace(queryText) == "" {
@@ -89,8 +107,22 @@ func (q *SearchQuery) Validate() error {
 	if q.PageSize < 1 {
 		return errors.New("pageSize must be greater than 0")
 	}
-	if q.PageSize > 100 {
-		return errors.New("pageSize cannot exeed 100")
+	if q.PageSize > MaxPageSize {
+		return fmt.Errorf("pageSize cannot exeed %d", MaxPageSize)
+	}
+	if err := q.validateFederation(); err != nil {
+		return err
+	}
+	if err := ValidateDecayFunctions(q.DecayFunctions); err != nil {
+		return fmt.Errorf("invalid decay function: %w", err)
+	}
+	for _, facet := range q.Facets {
+		if err := facet.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := q.validateHighlight(); err != nil {
+		return fmt.Errorf("invalid highlight: %w", err)
 	}
 	return nil
 }
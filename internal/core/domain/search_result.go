@@ -2,13 +2,22 @@ package domain
 
 // SearchResult represents the result of a search query
 type SearchResult struct {
-	TotalHits    int
-	Documents    []*Document
-	Page         int
-	PageSize     int
-	TotalPages   int
-	Took         int64
-	Suggestions  []string
-	Highlighting map[string]map[string][]string
-	QueryID      string
+	TotalHits     int
+	Documents     []*Document
+	Page          int
+	PageSize      int
+	TotalPages    int
+	Took          int64
+	Suggestions   []string
+	CorrectedFrom string
+	Highlighting  map[string]map[string][]string
+	QueryID       string
+	NextCursor    string
+	Facets        []FacetResult
+	// IndexHits counts the hits of each index of a federated search
+	IndexHits map[string]int
+	// RedirectURL is set instead of documents when a query rule redirects the search
+	RedirectURL string
+	// AppliedRules lists the IDs of the query rules applied to the results
+	AppliedRules []string
 }
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type SearchSuggestion struct {
 	Text          string
@@ -19,4 +22,10 @@ const (
 	SuggestionSourcePopular      SuggestionSource = "popular"
 	SuggestionSourceAutocomplete SuggestionSource = "autocomplete"
 	SuggestionSourceRelated      SuggestionSource = "related"
+	SuggestionSourceCorrection   SuggestionSource = "correction"
 )
+
+// NormalizeQuery returns the canonical form of a query string used to group equivalent queries
+func NormalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
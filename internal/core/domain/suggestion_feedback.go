@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// suggestionPriorCTR is the click-through rate assumed for suggestions without feedback
+	suggestionPriorCTR = 0.1
+	// suggestionPriorImpressions is the weight of the prior, in impressions
+	suggestionPriorImpressions = 20
+)
+
+// SuggestionSelection represents a user picking a suggestion while typing a query
+type SuggestionSelection struct {
+	Text         string
+	Source       SuggestionSource
+	PartialQuery string
+	Position     int
+	TimeTakenMs  int64
+	SelectedAt   time.Time
+}
+
+// Validate checks that the selection is well-formed
+func (s *SuggestionSelection) Validate() error {
+	if NormalizeQuery(s.Text) == "" {
+		return errors.New("suggestion text cannot be empty")
+	}
+	if s.Position < 0 {
+		return errors.New("position cannot be negative")
+	}
+	if s.TimeTakenMs < 0 {
+		return errors.New("time taken cannot be negative")
+	}
+	return nil
+}
+
+// SuggestionStats aggregates how often a suggestion was shown and picked
+type SuggestionStats struct {
+	Text           string
+	Impressions    int
+	Selections     int
+	AvgPosition    float64
+	LastShownAt    time.Time
+	LastSelectedAt time.Time
+}
+
+// CTR returns the raw click-through rate of the suggestion
+func (s SuggestionStats) CTR() float64 {
+	if s.Impressions == 0 {
+		return 0
+	}
+	return float64(s.Selections) / float64(s.Impressions)
+}
+
+// SmoothedCTR returns the click-through rate pulled towards a prior, so that
+// suggestions shown only a few times are neither promoted nor buried
+func (s SuggestionStats) SmoothedCTR() float64 {
+	return (float64(s.Selections) + suggestionPriorCTR*suggestionPriorImpressions) /
+		(float64(s.Impressions) + suggestionPriorImpressions)
+}
+
+// CTRBoost returns the ranking multiplier derived from the smoothed click-through rate,
+// 1 for a suggestion performing like the prior
+func (s SuggestionStats) CTRBoost() float64 {
+	return s.SmoothedCTR() / suggestionPriorCTR
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// SynonymRule declares terms that match each other in searches on an index.
+// Without replacements every term is equivalent to the others; with replacements
+// the terms are rewritten to the replacements, as in "ipod, i-pod => ipod".
+type SynonymRule struct {
+	Terms        []string
+	Replacements []string
+}
+
+// Validate ensures the rule has enough terms and that no term contains the rule separators
+func (r SynonymRule) Validate() error {
+	if len(r.Replacements) == 0 && len(r.Terms) < 2 {
+		return errors.New("synonym rule needs at least two equivalent terms")
+	}
+	if len(r.Terms) == 0 {
+		return errors.New("synonym rule needs at least one term to replace")
+	}
+	for _, term := range append(append([]string{}, r.Terms...), r.Replacements...) {
+		if strings.TrimSpace(term) == "" {
+			return errors.New("synonym terms cannot be empty")
+		}
+		if strings.Contains(term, ",") || strings.Contains(term, "=>") {
+			return errors.New("synonym terms cannot contain ',' or '=>'")
+		}
+	}
+	return nil
+}
+
+// String returns the rule in the Solr synonym format
+func (r SynonymRule) String() string {
+	rule := strings.Join(r.Terms, ", ")
+	if len(r.Replacements) > 0 {
+		rule += " => " + strings.Join(r.Replacements, ", ")
+	}
+	return rule
+}
+
+// TokenType tells whether an analyzed token comes from the text or from a synonym rule
+type TokenType string
+
+const (
+	TokenTypeWord    TokenType = "word"
+	TokenTypeSynonym TokenType = "synonym"
+)
+
+// AnalyzedToken is a search term produced by analyzing a piece of text for an index.
+// Synonyms share the position of the words they were expanded from.
+type AnalyzedToken struct {
+	Token    string
+	Position int
+	Type     TokenType
+}
+
+// ValidateStopwords ensures no stopword is blank or contains the synonym rule separators, as
+// stopwords may be stored alongside synonym rules
+func ValidateStopwords(stopwords []string) error {
+	for _, stopword := range stopwords {
+		if strings.TrimSpace(stopword) == "" {
+			return errors.New("stopwords cannot be empty")
+		}
+		if strings.Contains(stopword, ",") || strings.Contains(stopword, "=>") {
+			return errors.New("stopwords cannot contain ',' or '=>'")
+		}
+	}
+	return nil
+}
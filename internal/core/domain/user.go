@@ -9,6 +9,7 @@ type User struct {
 	Email       string
 	Password    string
 	Roles       []string
+	Groups      []string
 	Permissions []string
 	DisplayName string
 	APIKeys     []APIKey
@@ -0,0 +1,13 @@
+package incoming
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// AnalyticsService defines the primary port for search analytics
+type AnalyticsService interface {
+	UnselectedSuggestions(ctx context.Context, minImpressions, limit int) ([]domain.SuggestionStats, error)
+	QueryReport(ctx context.Context, window domain.AnalyticsWindow, limit int) (*domain.QueryReport, error)
+}
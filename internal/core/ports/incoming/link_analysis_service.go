@@ -0,0 +1,12 @@
+package incoming
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// LinkAnalysisService defines the primary port for ranking the documents of an index by their links
+type LinkAnalysisService interface {
+	RankIndex(ctx context.Context, indexID string, options domain.PageRankOptions) (*domain.LinkAnalysisReport, error)
+}
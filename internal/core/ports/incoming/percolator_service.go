@@ -0,0 +1,16 @@
+package incoming
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// PercolatorService defines the primary port for managing standing queries and matching saved
+// documents against them
+type PercolatorService interface {
+	RegisterQuery(ctx context.Context, query *domain.StandingQuery) (*domain.StandingQuery, error)
+	UnregisterQuery(ctx context.Context, id string) error
+	ListQueries(ctx context.Context) ([]*domain.StandingQuery, error)
+	PercolateDocument(ctx context.Context, document *domain.Document) ([]*domain.PercolatorMatch, error)
+}
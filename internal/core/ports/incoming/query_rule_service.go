@@ -0,0 +1,17 @@
+package incoming
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// QueryRuleService defines the primary port for managing the query rules pinning, burying and
+// redirecting the results of searches
+type QueryRuleService interface {
+	CreateQueryRule(ctx context.Context, rule *domain.QueryRule) (*domain.QueryRule, error)
+	UpdateQueryRule(ctx context.Context, rule *domain.QueryRule) (*domain.QueryRule, error)
+	GetQueryRule(ctx context.Context, id string) (*domain.QueryRule, error)
+	ListQueryRules(ctx context.Context, indexID string) ([]*domain.QueryRule, error)
+	DeleteQueryRule(ctx context.Context, id string) error
+}
@@ -0,0 +1,14 @@
+package incoming
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// RankingTrainingService defines the primary port for building training data and training ranking models
+type RankingTrainingService interface {
+	SamplesFromJudgments(ctx context.Context, judgments []domain.Judgment, candidates int) ([]domain.RankingSample, error)
+	SamplesFromClicks(ctx context.Context, window domain.AnalyticsWindow, limit, candidates int) ([]domain.RankingSample, error)
+	Train(ctx context.Context, samples []domain.RankingSample, options domain.RankingTrainingOptions) (*domain.RankingModel, error)
+}
@@ -0,0 +1,13 @@
+package incoming
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// RelevanceEvaluationService defines the primary port for measuring search ranking against judgment lists
+type RelevanceEvaluationService interface {
+	Evaluate(ctx context.Context, judgments []domain.Judgment, config domain.RankingConfig, k int) (*domain.EvaluationReport, error)
+	Compare(ctx context.Context, judgments []domain.Judgment, baseline, candidate domain.RankingConfig, k int) (*domain.EvaluationComparison, error)
+}
@@ -0,0 +1,18 @@
+package incoming
+
+import (
+	"context"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// SavedSearchService defines the primary port for managing saved searches and alerting their
+// users of new matches
+type SavedSearchService interface {
+	CreateSavedSearch(ctx context.Context, search *domain.SavedSearch) (*domain.SavedSearch, error)
+	GetSavedSearch(ctx context.Context, id string) (*domain.SavedSearch, error)
+	ListSavedSearches(ctx context.Context, userID string) ([]*domain.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id string) error
+	EvaluateSavedSearches(ctx context.Context, now time.Time) (*domain.SavedSearchReport, error)
+}
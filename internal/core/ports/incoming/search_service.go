@@ -9,6 +9,9 @@ import (
 type SearchService interface {
 	Search(ctx context.Context, query *domain.SearchQuery) (*domain.SearchResult, error)
 	GetDocument(ctx context.Context, id string) (*domain.Document, error)
+	RelatedDocuments(ctx context.Context, documentID string, limit int) ([]*domain.Document, error)
 	SuggestQueries(ctx context.Context, partialQuery string, maxSuggestions int) ([]domain.SearchSuggestion, error)
 	TrackSuggestionSelection(ctx context.Context, suggestion domain.SearchSuggestion, partialQuery string, position int, timeTakenMs int64) error
+	TrackResultClick(ctx context.Context, queryID, documentID string, position int) error
+	CacheStats() domain.CacheStats
 }
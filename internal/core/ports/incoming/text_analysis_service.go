@@ -0,0 +1,14 @@
+package incoming
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// TextAnalysisService defines the primary port for managing the synonyms and stopwords of an index
+type TextAnalysisService interface {
+	UpdateSynonyms(ctx context.Context, indexID string, rules []domain.SynonymRule) error
+	UpdateStopwords(ctx context.Context, indexID string, stopwords []string) error
+	Analyze(ctx context.Context, indexID string, text string) ([]domain.AnalyzedToken, error)
+}
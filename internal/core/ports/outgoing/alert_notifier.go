@@ -0,0 +1,14 @@
+package outgoing
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// AlertNotifier defines the interface for delivering the new matches of a saved search to
+// the alert channels of one type
+type AlertNotifier interface {
+	Channel() domain.AlertChannelType
+	Notify(ctx context.Context, alert *domain.SavedSearchAlert, channel domain.AlertChannel) error
+}
@@ -13,6 +13,11 @@ type DocumentRepository interface {
 	Delete(ctx context.Context, id string) error
 	Update(ctx context.Context, document *domain.Document) error
 	List(ctx context.Context, page, pageSize int) ([]*domain.Document, int, error)
+	ListAfter(ctx context.Context, cursor string, pageSize int) ([]*domain.Document, string, error)
 	Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.Document, int, error)
+	SearchAfter(ctx context.Context, query *domain.SearchQuery) ([]*domain.Document, int, string, error)
+	Facets(ctx context.Context, query *domain.SearchQuery) ([]domain.FacetResult, error)
+	SearchByVector(ctx context.Context, query *domain.SearchQuery, k int) ([]*domain.Document, error)
+	MoreLikeThis(ctx context.Context, document *domain.Document, limit int) ([]*domain.Document, error)
 	CountByIndexID(ctx context.Context, indexID string) (int, error)
 }
@@ -0,0 +1,9 @@
+package outgoing
+
+import "context"
+
+// Embedder defines the interface for turning text into a dense vector
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+	Dimensions() int
+}
@@ -0,0 +1,14 @@
+package outgoing
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// LinkGraphRepository defines the interface for reading the links between the documents of an
+// index and storing the results of their link analysis
+type LinkGraphRepository interface {
+	ListDocumentLinks(ctx context.Context, indexID string) ([]domain.DocumentLinks, error)
+	UpdateLinkScores(ctx context.Context, scores []domain.LinkScore) error
+}
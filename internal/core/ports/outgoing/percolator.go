@@ -0,0 +1,22 @@
+package outgoing
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// Percolator defines the interface for registering standing queries and finding the ones a
+// document matches
+type Percolator interface {
+	Register(ctx context.Context, query *domain.StandingQuery) error
+	Unregister(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*domain.StandingQuery, error)
+	Percolate(ctx context.Context, document *domain.Document) ([]*domain.StandingQuery, error)
+}
+
+// MatchSubscriber defines the interface for receiving the matches of saved documents against
+// standing queries as they happen
+type MatchSubscriber interface {
+	OnMatch(ctx context.Context, match *domain.PercolatorMatch) error
+}
@@ -0,0 +1,21 @@
+package outgoing
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// QueryLogRepository defines the interface for storing executed searches and result clicks,
+// and for aggregating them over time windows
+type QueryLogRepository interface {
+	LogQuery(ctx context.Context, entry *domain.QueryLogEntry) error
+	LogClick(ctx context.Context, click *domain.ResultClick) error
+	CountQueries(ctx context.Context, window domain.AnalyticsWindow) (int, error)
+	TopQueries(ctx context.Context, window domain.AnalyticsWindow, zeroResultsOnly bool, limit int) ([]domain.QueryCount, error)
+	ClickStats(ctx context.Context, window domain.AnalyticsWindow) (domain.ClickStats, error)
+	LatencyPercentiles(ctx context.Context, window domain.AnalyticsWindow, percents []float64) ([]float64, error)
+	PopularQueries(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error)
+	DocumentClickStats(ctx context.Context, documentIDs []string) (map[string]domain.DocumentClickStats, error)
+	ClickedSearches(ctx context.Context, window domain.AnalyticsWindow, limit int) ([]domain.ClickedSearch, error)
+}
@@ -0,0 +1,15 @@
+package outgoing
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// QueryRuleRepository defines the interface for storing the query rules of indexes
+type QueryRuleRepository interface {
+	Save(ctx context.Context, rule *domain.QueryRule) error
+	GetByID(ctx context.Context, id string) (*domain.QueryRule, error)
+	ListByIndex(ctx context.Context, indexID string) ([]*domain.QueryRule, error)
+	Delete(ctx context.Context, id string) error
+}
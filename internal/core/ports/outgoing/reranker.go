@@ -0,0 +1,13 @@
+package outgoing
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// Reranker defines the interface for re-ordering the top candidates of a search.
+// It returns the same documents in their new order.
+type Reranker interface {
+	Rerank(ctx context.Context, query *domain.SearchQuery, documents []*domain.Document) ([]*domain.Document, error)
+}
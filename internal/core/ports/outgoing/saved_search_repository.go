@@ -0,0 +1,19 @@
+package outgoing
+
+import (
+	"context"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// SavedSearchRepository defines the interface for storing the saved searches of users and
+// tracking when they last ran
+type SavedSearchRepository interface {
+	Save(ctx context.Context, search *domain.SavedSearch) error
+	GetByID(ctx context.Context, id string) (*domain.SavedSearch, error)
+	ListByUser(ctx context.Context, userID string) ([]*domain.SavedSearch, error)
+	List(ctx context.Context) ([]*domain.SavedSearch, error)
+	Delete(ctx context.Context, id string) error
+	MarkRun(ctx context.Context, id string, runAt time.Time) error
+}
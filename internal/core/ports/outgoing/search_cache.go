@@ -0,0 +1,17 @@
+package outgoing
+
+import (
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// SearchCache defines the interface for caching search results. Entries are scoped to the
+// indexes their results come from, so that changing a document only drops the affected entries.
+type SearchCache interface {
+	// Get returns a copy of the cached result of a query key
+	Get(key string) (*domain.SearchResult, bool)
+	// Put caches a copy of the result of a query key; nil index IDs mean every index
+	Put(key string, indexIDs []string, result *domain.SearchResult)
+	// InvalidateIndex drops the entries whose results may contain documents of the index
+	InvalidateIndex(indexID string)
+	Stats() domain.CacheStats
+}
@@ -0,0 +1,13 @@
+package outgoing
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// SpellingCorrector defines the interface for correcting misspelled queries against the index vocabulary
+type SpellingCorrector interface {
+	// Correct returns the corrected query, or nil when the query needs no correction
+	Correct(ctx context.Context, query string) (*domain.SearchSuggestion, error)
+}
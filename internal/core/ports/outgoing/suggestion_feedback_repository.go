@@ -0,0 +1,16 @@
+package outgoing
+
+import (
+	"context"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// SuggestionFeedbackRepository defines the interface for storing suggestion impressions and selections
+type SuggestionFeedbackRepository interface {
+	RecordImpressions(ctx context.Context, suggestions []domain.SearchSuggestion, shownAt time.Time) error
+	RecordSelection(ctx context.Context, selection *domain.SuggestionSelection) error
+	GetStats(ctx context.Context, texts []string) (map[string]domain.SuggestionStats, error)
+	ListUnselected(ctx context.Context, minImpressions, limit int) ([]domain.SuggestionStats, error)
+}
@@ -0,0 +1,19 @@
+package outgoing
+
+import (
+	"context"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// SuggestionCompleter defines the interface for completing partial queries from indexed documents
+type SuggestionCompleter interface {
+	Complete(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error)
+}
+
+// QueryHistoryRepository defines the interface for storing and retrieving past search queries
+type QueryHistoryRepository interface {
+	RecordQuery(ctx context.Context, query string, usedAt time.Time) error
+	PopularQueries(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error)
+}
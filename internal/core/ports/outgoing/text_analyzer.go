@@ -0,0 +1,15 @@
+package outgoing
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// TextAnalyzer defines the interface for applying the synonyms and stopwords of an index to searches
+type TextAnalyzer interface {
+	// ApplyAnalysis makes the current synonyms and stopwords of the index effective for searches
+	ApplyAnalysis(ctx context.Context, index *domain.Index) error
+	// Analyze returns the search terms produced from text by the analysis of the index
+	Analyze(ctx context.Context, index *domain.Index, text string) ([]domain.AnalyzedToken, error)
+}
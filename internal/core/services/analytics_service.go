@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// analyticsService implements the incoming.AnalyticsService interface
+type analyticsService struct {
+	feedbackRepo outgoing.SuggestionFeedbackRepository
+	queryLog     outgoing.QueryLogRepository
+}
+
+// NewAnalyticsService creates a new analytics service with the provided dependencies
+func NewAnalyticsService(feedbackRepo outgoing.SuggestionFeedbackRepository, queryLog outgoing.QueryLogRepository) incoming.AnalyticsService {
+	return &analyticsService{
+		feedbackRepo: feedbackRepo,
+		queryLog:     queryLog,
+	}
+}
+
+// UnselectedSuggestions returns the suggestions shown at least minImpressions times but never picked,
+// most shown first
+func (a analyticsService) UnselectedSuggestions(ctx context.Context, minImpressions, limit int) ([]domain.SuggestionStats, error) {
+	if minImpressions < 1 {
+		return nil, errors.New("minimum impressions must be at least 1")
+	}
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+	stats, err := a.feedbackRepo.ListUnselected(ctx, minImpressions, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unselected suggestions: %w", err)
+	}
+	return stats, nil
+}
+
+// QueryReport summarizes the searches logged within the window: the most searched queries, those
+// finding nothing, the average position of clicked results and the median and 95th percentile latency
+func (a analyticsService) QueryReport(ctx context.Context, window domain.AnalyticsWindow, limit int) (*domain.QueryReport, error) {
+	if err := window.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid window: %w", err)
+	}
+	if limit <= 0 {
+		return nil, errors.New("limit must be positive")
+	}
+
+	total, err := a.queryLog.CountQueries(ctx, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count queries: %w", err)
+	}
+	topQueries, err := a.queryLog.TopQueries(ctx, window, false, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top queries: %w", err)
+	}
+	zeroResultQueries, err := a.queryLog.TopQueries(ctx, window, true, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list zero-result queries: %w", err)
+	}
+	clicks, err := a.queryLog.ClickStats(ctx, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate result clicks: %w", err)
+	}
+	latencies, err := a.queryLog.LatencyPercentiles(ctx, window, []float64{50, 95})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute latency percentiles: %w", err)
+	}
+
+	return &domain.QueryReport{
+		Window:            window,
+		TotalQueries:      total,
+		TopQueries:        topQueries,
+		ZeroResultQueries: zeroResultQueries,
+		Clicks:            clicks.Clicks,
+		AvgClickPosition:  clicks.AvgPosition,
+		LatencyP50Ms:      latencies[0],
+		LatencyP95Ms:      latencies[1],
+	}, nil
+}
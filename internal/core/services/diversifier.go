@@ -0,0 +1,143 @@
+package services
+
+import (
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/textutil"
+)
+
+const (
+	// diversificationWindow is the number of top results re-ranked by the diversifier
+	diversificationWindow = 50
+	// similarityTokens bounds the number of content tokens compared per document
+	similarityTokens = 200
+)
+
+// Diversifier re-ranks search results with maximal marginal relevance (MMR)
+// so that no single host or near-duplicate cluster dominates a page
+type Diversifier struct {
+	// Lambda balances relevance (1) against novelty (0)
+	Lambda float64
+	// MaxPerHost caps the results taken from one host before other hosts are exhausted
+	MaxPerHost int
+	// HostSimilarity is the similarity assumed between two results from the same host
+	HostSimilarity float64
+}
+
+// NewDiversifier creates a diversifier with default settings
+func NewDiversifier() *Diversifier {
+	return &Diversifier{
+		Lambda:         0.7,
+		MaxPerHost:     2,
+		HostSimilarity: 0.5,
+	}
+}
+
+// candidate holds the precomputed features of a document being diversified
+type candidate struct {
+	document  *domain.Document
+	relevance float64
+	host      string
+	cluster   string
+	tokens    map[string]struct{}
+}
+
+// Diversify returns the documents in diversified order.
+// The input is expected in relevance order and is not modified.
+func (d *Diversifier) Diversify(documents []*domain.Document) []*domain.Document {
+	if len(documents) < 2 {
+		return documents
+	}
+
+	remaining := d.candidates(documents)
+	selected := make([]*candidate, 0, len(remaining))
+	hostCounts := make(map[string]int)
+
+	for len(remaining) > 0 {
+		best := -1
+		bestScore := 0.0
+		capped := true
+		for i, c := range remaining {
+			withinCap := d.MaxPerHost <= 0 || hostCounts[c.host] < d.MaxPerHost
+			// Prefer any candidate within its host cap over a capped one
+			if capped && withinCap {
+				best = -1
+				capped = false
+			}
+			if !capped && !withinCap {
+				continue
+			}
+			score := d.Lambda*c.relevance - (1-d.Lambda)*d.maxSimilarity(c, selected)
+			if best == -1 || score > bestScore {
+				best = i
+				bestScore = score
+			}
+		}
+
+		chosen := remaining[best]
+		selected = append(selected, chosen)
+		hostCounts[chosen.host]++
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+
+	diversified := make([]*domain.Document, len(selected))
+	for i, c := range selected {
+		diversified[i] = c.document
+	}
+	return diversified
+}
+
+// candidates precomputes relevance, host, cluster and tokens of the documents.
+// Relevance is the normalized score, or the normalized rank when no scores are available.
+func (d *Diversifier) candidates(documents []*domain.Document) []*candidate {
+	maxScore := 0.0
+	for _, doc := range documents {
+		if doc.Score > maxScore {
+			maxScore = doc.Score
+		}
+	}
+
+	candidates := make([]*candidate, len(documents))
+	for i, doc := range documents {
+		relevance := 1 - float64(i)/float64(len(documents))
+		if maxScore > 0 {
+			relevance = doc.Score / maxScore
+		}
+		cluster := doc.ID
+		if doc.OriginalDocID != "" {
+			cluster = doc.OriginalDocID
+		}
+		candidates[i] = &candidate{
+			document:  doc,
+			relevance: relevance,
+			host:      doc.Host(),
+			cluster:   cluster,
+			tokens:    textutil.TokenSet(doc.Title+" "+doc.MetaDesc+" "+doc.Content, similarityTokens),
+		}
+	}
+	return candidates
+}
+
+// maxSimilarity returns the highest similarity between a candidate and the already selected results
+func (d *Diversifier) maxSimilarity(c *candidate, selected []*candidate) float64 {
+	highest := 0.0
+	for _, s := range selected {
+		similarity := d.similarity(c, s)
+		if similarity > highest {
+			highest = similarity
+		}
+	}
+	return highest
+}
+
+// similarity returns how redundant two results are: duplicates are identical,
+// otherwise the higher of the content overlap and the same-host similarity
+func (d *Diversifier) similarity(a, b *candidate) float64 {
+	if a.cluster != "" && a.cluster == b.cluster {
+		return 1
+	}
+	similarity := textutil.Jaccard(a.tokens, b.tokens)
+	if a.host != "" && a.host == b.host && d.HostSimilarity > similarity {
+		similarity = d.HostSimilarity
+	}
+	return similarity
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// withAccessScope returns a context restricting the documents repositories return to those the
+// calling user can read, or to the public documents for anonymous callers. Contexts already
+// carrying an access scope keep it.
+func withAccessScope(ctx context.Context) context.Context {
+	if domain.AccessScopeFromContext(ctx) != nil {
+		return ctx
+	}
+	return domain.ContextWithAccessScope(ctx, domain.NewAccessScope(domain.UserFromContext(ctx)))
+}
+
+// withOwnerScope returns a context restricting the documents repositories return to those the
+// owner of a saved resource can read, whoever runs it. Only the ID of the owner is known, so the
+// documents shared with its groups or roles alone are left out rather than risking a leak.
+func withOwnerScope(ctx context.Context, userID string) context.Context {
+	return domain.ContextWithAccessScope(ctx, domain.NewAccessScope(&domain.User{ID: userID}))
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// indexHits holds the top hits of one index of a federated search
+type indexHits struct {
+	position  int
+	documents []*domain.Document
+	total     int
+	err       error
+}
+
+// retrieveFederated searches every index of the query concurrently and merges their top hits.
+// Scores are normalized per index before being weighted, so that indexes scoring on different
+// scales compete fairly. It returns the requested page, the total and the hits of each index.
+func (s searchService) retrieveFederated(ctx context.Context, query *domain.SearchQuery) ([]*domain.Document, int, map[string]int, error) {
+	diversify := s.shouldDiversify(query)
+	rerank := s.shouldRerank(query)
+	window := query.Offset() + query.Limit()
+	if diversify {
+		window = max(window, diversificationWindow)
+	}
+	if rerank {
+		window = max(window, s.rerankWindow)
+	}
+	window = min(window, domain.FederatedWindow)
+
+	results := make(chan indexHits, len(query.Indexes))
+	for i, index := range query.Indexes {
+		go func(position int, indexID string) {
+			indexQuery, err := s.withFreshness(ctx, restrictToIndexes(query, indexID, window))
+			if err != nil {
+				results <- indexHits{position: position, err: err}
+				return
+			}
+			documents, total, err := s.docRepo.Search(ctx, indexQuery)
+			results <- indexHits{position, documents, total, err}
+		}(i, index.IndexID)
+	}
+	hits := make([]indexHits, len(query.Indexes))
+	for range query.Indexes {
+		result := <-results
+		hits[result.position] = result
+	}
+
+	merged := make([]*domain.Document, 0, window*len(hits))
+	counts := make(map[string]int, len(hits))
+	total := 0
+	for i, result := range hits {
+		index := query.Indexes[i]
+		if result.err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to search index %s: %w", index.IndexID, result.err)
+		}
+		normalizeScores(result.documents, index.WeightOrDefault())
+		merged = append(merged, result.documents...)
+		counts[index.IndexID] = result.total
+		total += result.total
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Score > merged[j].Score
+	})
+
+	if rerank {
+		merged = s.rerank(ctx, query, merged)
+	}
+	if diversify {
+		merged = s.diversifyWindow(merged)
+	}
+	return paginate(merged, query), total, counts, nil
+}
+
+// restrictToIndexes returns a copy of a federated query searching the given indexes only,
+// fetching the top hits up to the window
+func restrictToIndexes(query *domain.SearchQuery, indexIDs interface{}, window int) *domain.SearchQuery {
+	restricted := *query
+	restricted.Indexes = nil
+	restricted.Filters = make(map[string]interface{}, len(query.Filters)+1)
+	for name, value := range query.Filters {
+		restricted.Filters[name] = value
+	}
+	restricted.Filters["index_id"] = indexIDs
+	if window > 0 {
+		restricted.Page = 1
+		restricted.PageSize = window
+	}
+	return &restricted
+}
+
+// normalizeScores scales the scores of the ranked hits of an index so that its top hit scores
+// the weight. Hits of an index that does not score them are scored by their rank instead.
+// Explained hits keep their index explanation below the normalization.
+func normalizeScores(documents []*domain.Document, weight float64) {
+	top := 0.0
+	for _, doc := range documents {
+		top = max(top, doc.Score)
+	}
+	for i, doc := range documents {
+		score := weight * float64(len(documents)-i) / float64(len(documents))
+		description := fmt.Sprintf("index weight %.4g × rank share %d/%d", weight, len(documents)-i, len(documents))
+		if top > 0 {
+			score = weight * doc.Score / top
+			description = fmt.Sprintf("index weight %.4g × score / top score of the index %.4g, from:", weight, top)
+		}
+		if doc.Explanation != nil {
+			doc.Explanation = domain.NewScoreExplanation(score, description, doc.Explanation)
+		}
+		doc.Score = score
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// withFreshness returns the query with the decay functions boosting recent documents. Queries
+// setting none use those of the index they are restricted to; SkipFreshness turns boosting off.
+func (s searchService) withFreshness(ctx context.Context, query *domain.SearchQuery) (*domain.SearchQuery, error) {
+	if query.SkipFreshness {
+		if len(query.DecayFunctions) == 0 {
+			return query, nil
+		}
+		fresh := *query
+		fresh.DecayFunctions = nil
+		return &fresh, nil
+	}
+	indexID, _ := query.Filters["index_id"].(string)
+	if len(query.DecayFunctions) > 0 || indexID == "" || s.indexRepo == nil {
+		return query, nil
+	}
+
+	// Index settings are best effort: an index that cannot be read ranks by relevance alone
+	index, err := s.indexRepo.GetByID(ctx, indexID)
+	if err != nil || index == nil || len(index.Settings.Decay) == 0 {
+		return query, nil
+	}
+	if err := domain.ValidateDecayFunctions(index.Settings.Decay); err != nil {
+		return nil, fmt.Errorf("invalid decay function of index %s: %w", indexID, err)
+	}
+	fresh := *query
+	fresh.DecayFunctions = index.Settings.Decay
+	return &fresh, nil
+}
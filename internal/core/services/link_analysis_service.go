@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// linkAnalysisService implements the incoming.LinkAnalysisService interface
+type linkAnalysisService struct {
+	indexRepo outgoing.IndexRepository
+	links     outgoing.LinkGraphRepository
+}
+
+// NewLinkAnalysisService creates a new link analysis service with the provided dependencies
+func NewLinkAnalysisService(indexRepo outgoing.IndexRepository, links outgoing.LinkGraphRepository) incoming.LinkAnalysisService {
+	return &linkAnalysisService{
+		indexRepo: indexRepo,
+		links:     links,
+	}
+}
+
+// RankIndex builds the link graph of the documents of an index, computes their PageRank and
+// stores it as their importance rank along with their link counts
+func (l linkAnalysisService) RankIndex(ctx context.Context, indexID string, options domain.PageRankOptions) (*domain.LinkAnalysisReport, error) {
+	if indexID == "" {
+		return nil, errors.New("index ID cannot be empty")
+	}
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid PageRank options: %w", err)
+	}
+	index, err := l.indexRepo.GetByID(ctx, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index: %w", err)
+	}
+	if index == nil {
+		return nil, fmt.Errorf("index with ID %s does not exist", indexID)
+	}
+
+	documents, err := l.links.ListDocumentLinks(ctx, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list document links: %w", err)
+	}
+	graph := domain.NewLinkGraph(documents)
+	ranks, iterations, converged := graph.PageRank(options)
+	if err := l.links.UpdateLinkScores(ctx, graph.Scores(ranks)); err != nil {
+		return nil, fmt.Errorf("failed to update link scores: %w", err)
+	}
+
+	return &domain.LinkAnalysisReport{
+		IndexID:           indexID,
+		Documents:         graph.Len(),
+		Links:             graph.Links(),
+		DanglingDocuments: graph.Dangling(),
+		Iterations:        iterations,
+		Converged:         converged,
+	}, nil
+}
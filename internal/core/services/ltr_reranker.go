@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// LTRReranker re-ranks candidates with a trained learning-to-rank model
+type LTRReranker struct {
+	model     *domain.RankingModel
+	extractor *FeatureExtractor
+}
+
+// NewLTRReranker creates a re-ranker scoring candidates with the model
+func NewLTRReranker(model *domain.RankingModel, extractor *FeatureExtractor) (*LTRReranker, error) {
+	if model == nil {
+		return nil, errors.New("ranking model cannot be nil")
+	}
+	if err := model.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ranking model: %w", err)
+	}
+	if extractor == nil {
+		return nil, errors.New("feature extractor cannot be nil")
+	}
+	return &LTRReranker{
+		model:     model,
+		extractor: extractor,
+	}, nil
+}
+
+// Ensure LTRReranker implements the outgoing.Reranker interface
+var _ outgoing.Reranker = (*LTRReranker)(nil)
+
+// Rerank orders the documents by model score, keeping the retrieval order among ties. The score
+// of each document becomes its model score mapped into (0, 1); explained searches keep the
+// retrieval explanation below the contributions of the model.
+func (r *LTRReranker) Rerank(ctx context.Context, query *domain.SearchQuery, documents []*domain.Document) ([]*domain.Document, error) {
+	vectors, err := r.extractor.Extract(ctx, query.Query, documents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract ranking features: %w", err)
+	}
+
+	scores := make(map[*domain.Document]float64, len(documents))
+	explanations := make(map[*domain.Document]*domain.ScoreExplanation)
+	for i, doc := range documents {
+		values := vectors[i].Values(r.model.Features)
+		scores[doc] = r.model.Score(values)
+		if query.Explain {
+			explanations[doc] = r.model.Explain(values)
+		}
+	}
+	reranked := make([]*domain.Document, len(documents))
+	copy(reranked, documents)
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return scores[reranked[i]] > scores[reranked[j]]
+	})
+	for _, doc := range reranked {
+		score := 1 / (1 + math.Exp(-scores[doc]))
+		if query.Explain {
+			retrieval := domain.NewScoreExplanation(doc.Score, "retrieval score, replaced by the re-ranker", doc.Explanation)
+			doc.Explanation = domain.NewScoreExplanation(score, "re-ranked: 1 / (1 + exp(-model score)), from:",
+				explanations[doc], retrieval)
+		}
+		doc.Score = score
+	}
+	return reranked, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// percolatorService implements the incoming.PercolatorService interface
+type percolatorService struct {
+	percolator  outgoing.Percolator
+	subscribers []outgoing.MatchSubscriber
+}
+
+// NewPercolatorService creates a percolator service matching documents with the percolator and
+// publishing the matches to the subscribers
+func NewPercolatorService(percolator outgoing.Percolator, subscribers ...outgoing.MatchSubscriber) incoming.PercolatorService {
+	return &percolatorService{
+		percolator:  percolator,
+		subscribers: subscribers,
+	}
+}
+
+// RegisterQuery validates and registers a standing query. Every document saved from then on is
+// matched against it.
+func (s percolatorService) RegisterQuery(ctx context.Context, query *domain.StandingQuery) (*domain.StandingQuery, error) {
+	if query == nil {
+		return nil, errors.New("standing query cannot be nil")
+	}
+	// The expression is derived from the query text when the query is matched
+	query.Query.Expression = nil
+	query.Query.Vector = nil
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid standing query: %w", err)
+	}
+
+	query.ID = uuid.NewString()
+	query.CreatedAt = time.Now()
+	if err := s.percolator.Register(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to register standing query: %w", err)
+	}
+	return query, nil
+}
+
+// UnregisterQuery removes a standing query by ID
+func (s percolatorService) UnregisterQuery(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("standing query ID cannot be empty")
+	}
+	return s.percolator.Unregister(ctx, id)
+}
+
+// ListQueries returns the registered standing queries
+func (s percolatorService) ListQueries(ctx context.Context) ([]*domain.StandingQuery, error) {
+	return s.percolator.List(ctx)
+}
+
+// PercolateDocument matches a document against the standing queries and publishes every match to
+// the subscribers. The matches are returned along with the errors of the subscribers that could
+// not receive them.
+func (s percolatorService) PercolateDocument(ctx context.Context, document *domain.Document) ([]*domain.PercolatorMatch, error) {
+	if document == nil {
+		return nil, errors.New("document cannot be nil")
+	}
+	queries, err := s.percolator.Percolate(ctx, document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to percolate document: %w", err)
+	}
+
+	now := time.Now()
+	matches := make([]*domain.PercolatorMatch, 0, len(queries))
+	var errs []error
+	for _, query := range queries {
+		match := &domain.PercolatorMatch{
+			Query:     query,
+			Document:  document,
+			MatchedAt: now,
+		}
+		matches = append(matches, match)
+		for _, subscriber := range s.subscribers {
+			if err := subscriber.OnMatch(ctx, match); err != nil {
+				errs = append(errs, fmt.Errorf("failed to publish match of standing query %s: %w", query.ID, err))
+			}
+		}
+	}
+	return matches, errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// WithQueryLog sets the repository used to log searches and result clicks for analytics.
+// Logged searches also feed the popular query suggestions, in place of the query history.
+func WithQueryLog(queryLog outgoing.QueryLogRepository) SearchServiceOption {
+	return func(s *searchService) {
+		s.queryLog = queryLog
+	}
+}
+
+// popularSource returns the source of popular query suggestions. Every search is both logged
+// and recorded in the query history, so only one of them is used not to count searches twice:
+// the query log is authoritative when configured, as it keeps every search with its time and
+// hits, and the query history serves deployments without one.
+func (s searchService) popularSource() suggestionSource {
+	if s.queryLog != nil {
+		return s.queryLog.PopularQueries
+	}
+	if s.queryHistory != nil {
+		return s.queryHistory.PopularQueries
+	}
+	return nil
+}
+
+// logQuery adds a search to the query log under the query ID of its result, with the offset of
+// its page. Searches skipping analytics are not logged. Cursor pages do not know how many results precede them and are logged from the top.
+// Logging is best effort and never fails the search.
+func (s searchService) logQuery(ctx context.Context, query *domain.SearchQuery, result *domain.SearchResult) {
+	if s.queryLog == nil || query.SkipAnalytics {
+		return
+	}
+	documentIDs := make([]string, 0, len(result.Documents))
+	for _, doc := range result.Documents {
+		documentIDs = append(documentIDs, doc.ID)
+	}
+	_ = s.queryLog.LogQuery(ctx, &domain.QueryLogEntry{
+		QueryID:     result.QueryID,
+		Query:       query.Query,
+		Filters:     query.Filters,
+		TotalHits:   result.TotalHits,
+		LatencyMs:   result.Took,
+		Offset:      query.Offset(),
+		DocumentIDs: documentIDs,
+		LoggedAt:    time.Now(),
+	})
+}
+
+func (s searchService) TrackResultClick(ctx context.Context, queryID, documentID string, position int) error {
+	if s.queryLog == nil {
+		return errors.New("query logging is not configured")
+	}
+	click := &domain.ResultClick{
+		QueryID:    queryID,
+		DocumentID: documentID,
+		Position:   position,
+		ClickedAt:  time.Now(),
+	}
+	if err := click.Validate(); err != nil {
+		return fmt.Errorf("invalid result click: %w", err)
+	}
+	if err := s.queryLog.LogClick(ctx, click); err != nil {
+		return fmt.Errorf("failed to log result click: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// queryRuleService implements the incoming.QueryRuleService interface
+type queryRuleService struct {
+	repo      outgoing.QueryRuleRepository
+	indexRepo outgoing.IndexRepository
+}
+
+// NewQueryRuleService creates a service managing the query rules of the indexes of the repository
+func NewQueryRuleService(repo outgoing.QueryRuleRepository, indexRepo outgoing.IndexRepository) incoming.QueryRuleService {
+	return &queryRuleService{
+		repo:      repo,
+		indexRepo: indexRepo,
+	}
+}
+
+// CreateQueryRule validates and stores a new query rule of an existing index
+func (s queryRuleService) CreateQueryRule(ctx context.Context, rule *domain.QueryRule) (*domain.QueryRule, error) {
+	if rule == nil {
+		return nil, errors.New("query rule cannot be nil")
+	}
+	if err := s.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rule.ID = uuid.NewString()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	if err := s.repo.Save(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to save query rule: %w", err)
+	}
+	return rule, nil
+}
+
+// UpdateQueryRule validates and replaces an existing query rule, keeping its creation date
+func (s queryRuleService) UpdateQueryRule(ctx context.Context, rule *domain.QueryRule) (*domain.QueryRule, error) {
+	if rule == nil {
+		return nil, errors.New("query rule cannot be nil")
+	}
+	existing, err := s.GetQueryRule(ctx, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = time.Now()
+	if err := s.repo.Save(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to save query rule: %w", err)
+	}
+	return rule, nil
+}
+
+// GetQueryRule returns a query rule by ID
+func (s queryRuleService) GetQueryRule(ctx context.Context, id string) (*domain.QueryRule, error) {
+	if id == "" {
+		return nil, errors.New("query rule ID cannot be empty")
+	}
+	rule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get query rule: %w", err)
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("query rule %s not found", id)
+	}
+	return rule, nil
+}
+
+// ListQueryRules returns the query rules of an index
+func (s queryRuleService) ListQueryRules(ctx context.Context, indexID string) ([]*domain.QueryRule, error) {
+	if indexID == "" {
+		return nil, errors.New("index ID cannot be empty")
+	}
+	return s.repo.ListByIndex(ctx, indexID)
+}
+
+// DeleteQueryRule deletes a query rule by ID
+func (s queryRuleService) DeleteQueryRule(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("query rule ID cannot be empty")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// validate checks the rule and that its index exists
+func (s queryRuleService) validate(ctx context.Context, rule *domain.QueryRule) error {
+	if err := rule.Validate(); err != nil {
+		return fmt.Errorf("invalid query rule: %w", err)
+	}
+	index, err := s.indexRepo.GetByID(ctx, rule.IndexID)
+	if err != nil {
+		return fmt.Errorf("failed to get index: %w", err)
+	}
+	if index == nil {
+		return fmt.Errorf("index %s not found", rule.IndexID)
+	}
+	return nil
+}
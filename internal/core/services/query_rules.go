@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// WithQueryRules sets the repository of the query rules applied to the searches of an index.
+// Rules are read on every search so that edits and their start and end dates apply right away.
+func WithQueryRules(rules outgoing.QueryRuleRepository) SearchServiceOption {
+	return func(s *searchService) {
+		s.queryRules = rules
+	}
+}
+
+// matchingRules returns the rules of the searched indexes active at a time whose condition
+// matches the query text. Searches across every index apply no rule.
+func (s searchService) matchingRules(ctx context.Context, query *domain.SearchQuery, at time.Time) ([]*domain.QueryRule, error) {
+	if s.queryRules == nil || query.Query == "" {
+		return nil, nil
+	}
+	matching := make([]*domain.QueryRule, 0)
+	for _, indexID := range query.CacheIndexIDs() {
+		rules, err := s.queryRules.ListByIndex(ctx, indexID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list query rules: %w", err)
+		}
+		for _, rule := range rules {
+			if rule.Active(at) && rule.Matches(query.Query) {
+				matching = append(matching, rule)
+			}
+		}
+	}
+	return matching, nil
+}
+
+// redirectResult returns the result of a search redirected by the first matching rule with a
+// redirect URL, or nil when no rule redirects. Redirected searches are not run.
+func redirectResult(query *domain.SearchQuery, rules []*domain.QueryRule) *domain.SearchResult {
+	for _, rule := range rules {
+		if rule.RedirectURL != "" {
+			return &domain.SearchResult{
+				Documents:    make([]*domain.Document, 0),
+				Page:         query.Page,
+				PageSize:     query.PageSize,
+				QueryID:      uuid.NewString(),
+				RedirectURL:  rule.RedirectURL,
+				AppliedRules: []string{rule.ID},
+			}
+		}
+	}
+	return nil
+}
+
+// applyQueryRules pins and buries the documents of a page of results. Pinned documents lead the
+// first page in the order of their rules and are left out of every page of the ranked results;
+// buried documents are moved to the end of their page. The total hits count the ranked results.
+func (s searchService) applyQueryRules(ctx context.Context, query *domain.SearchQuery, result *domain.SearchResult, rules []*domain.QueryRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	pinnedIDs := make([]string, 0)
+	pinnedBy := make(map[string]*domain.QueryRule)
+	buried := make(map[string]bool)
+	applied := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		for _, id := range rule.PinnedIDs {
+			if _, ok := pinnedBy[id]; !ok {
+				pinnedIDs = append(pinnedIDs, id)
+				pinnedBy[id] = rule
+			}
+		}
+		for _, id := range rule.BuriedIDs {
+			buried[id] = true
+		}
+		applied = append(applied, rule.ID)
+	}
+
+	documents := make([]*domain.Document, 0, len(result.Documents)+len(pinnedIDs))
+	if query.Page <= 1 && query.Cursor == "" {
+		pinned, err := s.pinnedDocuments(ctx, query, pinnedIDs, pinnedBy)
+		if err != nil {
+			return err
+		}
+		documents = append(documents, pinned...)
+	}
+	sunk := make([]*domain.Document, 0)
+	for _, doc := range result.Documents {
+		if _, ok := pinnedBy[doc.ID]; ok {
+			continue
+		}
+		if buried[doc.ID] {
+			sunk = append(sunk, doc)
+			continue
+		}
+		documents = append(documents, doc)
+	}
+	result.Documents = append(documents, sunk...)
+	result.AppliedRules = applied
+	return nil
+}
+
+// pinnedDocuments loads the pinned documents that exist in the searched indexes, in order.
+// Explained searches record the rule that pinned each of them.
+func (s searchService) pinnedDocuments(ctx context.Context, query *domain.SearchQuery, ids []string, pinnedBy map[string]*domain.QueryRule) ([]*domain.Document, error) {
+	indexIDs := query.CacheIndexIDs()
+	documents := make([]*domain.Document, 0, len(ids))
+	for _, id := range ids {
+		doc, err := s.docRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pinned document: %w", err)
+		}
+		if doc == nil || !slices.Contains(indexIDs, doc.IndexID) {
+			continue
+		}
+		if query.Explain {
+			doc.Explanation = domain.NewScoreExplanation(doc.Score, fmt.Sprintf("pinned by query rule %q", pinnedBy[id].Name))
+		}
+		documents = append(documents, doc)
+	}
+	return documents, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/internal/pkg/textutil"
+)
+
+const (
+	// bm25K1 controls how quickly repeated terms stop adding to the BM25 score
+	bm25K1 = 1.2
+	// bm25B controls how much BM25 normalizes scores by field length
+	bm25B = 0.75
+	// freshnessHalfLife is the age at which the freshness feature halves
+	freshnessHalfLife = 30 * 24 * time.Hour
+)
+
+// FeatureExtractor computes the ranking features of the candidates of a search. BM25 statistics
+// are taken over the candidates themselves, so features compare candidates of the same query.
+type FeatureExtractor struct {
+	queryLog outgoing.QueryLogRepository
+	now      func() time.Time
+}
+
+// NewFeatureExtractor creates a feature extractor. Without a query log every candidate gets
+// the prior click-through rate.
+func NewFeatureExtractor(queryLog outgoing.QueryLogRepository) *FeatureExtractor {
+	return &FeatureExtractor{
+		queryLog: queryLog,
+		now:      time.Now,
+	}
+}
+
+// fieldStats holds the tokenized field of each candidate and the statistics BM25 needs
+type fieldStats struct {
+	terms         []map[string]int
+	lengths       []int
+	avgLength     float64
+	documentFreqs map[string]int
+}
+
+// Extract returns the feature vectors of the documents, in order
+func (e *FeatureExtractor) Extract(ctx context.Context, query string, documents []*domain.Document) ([]domain.FeatureVector, error) {
+	clickStats, err := e.clickStats(ctx, documents)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := distinctTokens(query)
+	fields := map[string]*fieldStats{
+		domain.FeatureBM25Title:    newFieldStats(documents, func(d *domain.Document) string { return d.Title }),
+		domain.FeatureBM25MetaDesc: newFieldStats(documents, func(d *domain.Document) string { return d.MetaDesc }),
+		domain.FeatureBM25Content:  newFieldStats(documents, func(d *domain.Document) string { return d.Content }),
+	}
+	now := e.now()
+
+	vectors := make([]domain.FeatureVector, len(documents))
+	for i, doc := range documents {
+		vector := domain.FeatureVector{
+			domain.FeatureRetrievalScore: doc.Score,
+			domain.FeatureImportanceRank: doc.ImportanceRank,
+			domain.FeatureFreshness:      freshness(doc, now),
+			domain.FeatureTitleMatch:     titleMatch(terms, fields[domain.FeatureBM25Title].terms[i]),
+			domain.FeatureKeywordScore:   keywordScore(terms, doc.EnhancedKeywords),
+			domain.FeatureClickThrough:   clickStats[doc.ID].SmoothedCTR(),
+		}
+		for name, stats := range fields {
+			vector[name] = stats.bm25(terms, i, len(documents))
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+// clickStats loads the click statistics of the documents from the query log
+func (e *FeatureExtractor) clickStats(ctx context.Context, documents []*domain.Document) (map[string]domain.DocumentClickStats, error) {
+	if e.queryLog == nil {
+		return map[string]domain.DocumentClickStats{}, nil
+	}
+	ids := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		ids = append(ids, doc.ID)
+	}
+	stats, err := e.queryLog.DocumentClickStats(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load click statistics: %w", err)
+	}
+	return stats, nil
+}
+
+// newFieldStats tokenizes a field of every document
+func newFieldStats(documents []*domain.Document, field func(*domain.Document) string) *fieldStats {
+	stats := &fieldStats{
+		terms:         make([]map[string]int, len(documents)),
+		lengths:       make([]int, len(documents)),
+		documentFreqs: make(map[string]int),
+	}
+	total := 0
+	for i, doc := range documents {
+		tokens := textutil.Tokenize(field(doc))
+		counts := make(map[string]int, len(tokens))
+		for _, token := range tokens {
+			counts[token]++
+		}
+		for token := range counts {
+			stats.documentFreqs[token]++
+		}
+		stats.terms[i] = counts
+		stats.lengths[i] = len(tokens)
+		total += len(tokens)
+	}
+	if len(documents) > 0 {
+		stats.avgLength = float64(total) / float64(len(documents))
+	}
+	return stats
+}
+
+// bm25 returns the BM25 score of the field of the i-th document for the query terms
+func (f *fieldStats) bm25(terms []string, i, documents int) float64 {
+	if f.avgLength == 0 {
+		return 0
+	}
+	norm := bm25K1 * (1 - bm25B + bm25B*float64(f.lengths[i])/f.avgLength)
+	score := 0.0
+	for _, term := range terms {
+		tf := float64(f.terms[i][term])
+		if tf == 0 {
+			continue
+		}
+		df := float64(f.documentFreqs[term])
+		idf := math.Log(1 + (float64(documents)-df+0.5)/(df+0.5))
+		score += idf * tf * (bm25K1 + 1) / (tf + norm)
+	}
+	return score
+}
+
+// freshness decays from 1 for a document modified now, halving every half-life.
+// Documents without a modification time use their crawl time.
+func freshness(doc *domain.Document, now time.Time) float64 {
+	updated := doc.LastModified
+	if updated.IsZero() {
+		updated = doc.LastCrawled
+	}
+	if updated.IsZero() {
+		return 0
+	}
+	age := max(now.Sub(updated), 0)
+	return math.Pow(0.5, float64(age)/float64(freshnessHalfLife))
+}
+
+// titleMatch returns the fraction of the query terms found in the title
+func titleMatch(terms []string, title map[string]int) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+	matched := 0
+	for _, term := range terms {
+		if title[term] > 0 {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(terms))
+}
+
+// keywordScore sums the scores of the extracted keywords of the document that match a query term
+func keywordScore(terms []string, keywords []domain.Keyword) float64 {
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+	score := 0.0
+	for _, keyword := range keywords {
+		for _, token := range textutil.Tokenize(keyword.Text) {
+			if wanted[token] {
+				score += keyword.Score
+				break
+			}
+		}
+	}
+	return score
+}
+
+// distinctTokens returns the distinct tokens of the text in order of appearance
+func distinctTokens(text string) []string {
+	seen := make(map[string]bool)
+	tokens := make([]string, 0)
+	for _, token := range textutil.Tokenize(text) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
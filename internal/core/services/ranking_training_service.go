@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// maxTrainingCandidates bounds the candidates retrieved per training query, as for searches
+const maxTrainingCandidates = 100
+
+// rankingTrainingService implements the incoming.RankingTrainingService interface
+type rankingTrainingService struct {
+	docRepo   outgoing.DocumentRepository
+	extractor *FeatureExtractor
+	queryLog  outgoing.QueryLogRepository
+}
+
+// NewRankingTrainingService creates a ranking training service. Candidates are retrieved from the
+// document repository directly, so training searches are neither logged nor re-ranked.
+func NewRankingTrainingService(docRepo outgoing.DocumentRepository, extractor *FeatureExtractor, queryLog outgoing.QueryLogRepository) incoming.RankingTrainingService {
+	return &rankingTrainingService{
+		docRepo:   docRepo,
+		extractor: extractor,
+		queryLog:  queryLog,
+	}
+}
+
+// SamplesFromJudgments retrieves the candidates of each judged query and labels them with their
+// grade. Candidates that were not judged are labeled as not relevant.
+func (r rankingTrainingService) SamplesFromJudgments(ctx context.Context, judgments []domain.Judgment, candidates int) ([]domain.RankingSample, error) {
+	queries, err := domain.GroupJudgments(judgments)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.RankingSample, 0)
+	for _, judged := range queries {
+		documents, vectors, err := r.candidates(ctx, judged.Query, candidates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect candidates of query %q: %w", judged.Query, err)
+		}
+		for i, doc := range documents {
+			grade, _ := judged.Grade(doc)
+			samples = append(samples, domain.RankingSample{
+				Group:    judged.Query,
+				Document: doc.ID,
+				Features: vectors[i],
+				Label:    float64(grade),
+			})
+		}
+	}
+	return samples, nil
+}
+
+// SamplesFromClicks retrieves the candidates of the logged searches with clicked results and
+// labels the clicked candidates as relevant. Searches none of whose clicked documents are still
+// retrieved are skipped.
+func (r rankingTrainingService) SamplesFromClicks(ctx context.Context, window domain.AnalyticsWindow, limit, candidates int) ([]domain.RankingSample, error) {
+	if r.queryLog == nil {
+		return nil, errors.New("query logging is not configured")
+	}
+	if err := window.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid window: %w", err)
+	}
+	searches, err := r.queryLog.ClickedSearches(ctx, window, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clicked searches: %w", err)
+	}
+
+	samples := make([]domain.RankingSample, 0)
+	for _, search := range searches {
+		documents, vectors, err := r.candidates(ctx, search.Query, candidates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect candidates of query %q: %w", search.Query, err)
+		}
+		clicked := make(map[string]bool, len(search.Clicked))
+		for _, id := range search.Clicked {
+			clicked[id] = true
+		}
+
+		group := make([]domain.RankingSample, 0, len(documents))
+		hasClick := false
+		for i, doc := range documents {
+			label := 0.0
+			if clicked[doc.ID] {
+				label = 1
+				hasClick = true
+			}
+			group = append(group, domain.RankingSample{
+				Group:    search.QueryID,
+				Document: doc.ID,
+				Features: vectors[i],
+				Label:    label,
+			})
+		}
+		if hasClick {
+			samples = append(samples, group...)
+		}
+	}
+	return samples, nil
+}
+
+// candidates retrieves the top documents of a query in repository order and extracts their features
+func (r rankingTrainingService) candidates(ctx context.Context, text string, candidates int) ([]*domain.Document, []domain.FeatureVector, error) {
+	if candidates <= 0 || candidates > maxTrainingCandidates {
+		return nil, nil, fmt.Errorf("candidates must be between 1 and %d", maxTrainingCandidates)
+	}
+	query, err := domain.NewSearchQuery(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	query.PageSize = candidates
+
+	documents, _, err := r.docRepo.Search(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	vectors, err := r.extractor.Extract(ctx, query.Query, documents)
+	if err != nil {
+		return nil, nil, err
+	}
+	return documents, vectors, nil
+}
+
+// Train fits a ranking model to the samples
+func (r rankingTrainingService) Train(ctx context.Context, samples []domain.RankingSample, options domain.RankingTrainingOptions) (*domain.RankingModel, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid training options: %w", err)
+	}
+	if len(samples) == 0 {
+		return nil, errors.New("samples cannot be empty")
+	}
+
+	features := make([][]float64, len(samples))
+	labels := make([]float64, len(samples))
+	for i, sample := range samples {
+		features[i] = sample.Features.Values(options.Features)
+		labels[i] = sample.Label
+	}
+
+	var model *domain.RankingModel
+	switch options.Type {
+	case domain.LinearModel:
+		pairs := trainingPairs(samples)
+		if len(pairs) == 0 {
+			return nil, errors.New("samples need at least one query with differently labeled candidates")
+		}
+		model = trainLinearModel(features, pairs, options)
+	case domain.TreeEnsembleModel:
+		model = trainTreeEnsemble(features, labels, options)
+	}
+	if err := model.Validate(); err != nil {
+		return nil, fmt.Errorf("trained an invalid model: %w", err)
+	}
+	return model, nil
+}
+
+// trainingPair is a pair of samples of the same group where the first is more relevant
+type trainingPair struct {
+	better int
+	worse  int
+}
+
+// trainingPairs lists the pairs of samples of each group with different labels
+func trainingPairs(samples []domain.RankingSample) []trainingPair {
+	groups := make(map[string][]int)
+	order := make([]string, 0)
+	for i, sample := range samples {
+		if _, ok := groups[sample.Group]; !ok {
+			order = append(order, sample.Group)
+		}
+		groups[sample.Group] = append(groups[sample.Group], i)
+	}
+
+	pairs := make([]trainingPair, 0)
+	for _, group := range order {
+		members := groups[group]
+		for _, i := range members {
+			for _, j := range members {
+				if samples[i].Label > samples[j].Label {
+					pairs = append(pairs, trainingPair{better: i, worse: j})
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+// trainLinearModel fits weights with pairwise logistic loss (RankNet) by gradient descent on
+// standardized features, then folds the standardization back into the weights and bias
+func trainLinearModel(features [][]float64, pairs []trainingPair, options domain.RankingTrainingOptions) *domain.RankingModel {
+	means, scales := featureScaling(features)
+	standardized := make([][]float64, len(features))
+	for i, values := range features {
+		standardized[i] = make([]float64, len(values))
+		for f, value := range values {
+			standardized[i][f] = (value - means[f]) / scales[f]
+		}
+	}
+
+	weights := make([]float64, len(options.Features))
+	diff := make([]float64, len(weights))
+	for epoch := 0; epoch < options.Iterations; epoch++ {
+		for _, pair := range pairs {
+			margin := 0.0
+			for f := range weights {
+				diff[f] = standardized[pair.better][f] - standardized[pair.worse][f]
+				margin += weights[f] * diff[f]
+			}
+			// Gradient of log(1 + e^-margin) with respect to the margin
+			step := options.LearningRate / (1 + math.Exp(margin))
+			for f := range weights {
+				weights[f] += step * diff[f]
+			}
+		}
+	}
+
+	model := &domain.RankingModel{
+		Type:     domain.LinearModel,
+		Features: options.Features,
+		Weights:  make([]float64, len(weights)),
+	}
+	for f, weight := range weights {
+		model.Weights[f] = weight / scales[f]
+		model.Bias -= weight * means[f] / scales[f]
+	}
+	return model
+}
+
+// featureScaling returns the mean and standard deviation of each feature. Constant features,
+// up to rounding, get a unit scale so that they stay near zero once standardized.
+func featureScaling(features [][]float64) ([]float64, []float64) {
+	n := len(features[0])
+	means := make([]float64, n)
+	scales := make([]float64, n)
+	for _, values := range features {
+		for f, value := range values {
+			means[f] += value
+		}
+	}
+	for f := range means {
+		means[f] /= float64(len(features))
+	}
+	for _, values := range features {
+		for f, value := range values {
+			scales[f] += (value - means[f]) * (value - means[f])
+		}
+	}
+	for f := range scales {
+		scales[f] = math.Sqrt(scales[f] / float64(len(features)))
+		if scales[f] < 1e-6 {
+			scales[f] = 1
+		}
+	}
+	return means, scales
+}
+
+// trainTreeEnsemble fits gradient-boosted regression trees to the labels with squared loss.
+// The bias starts at the mean label and each tree fits the residuals left by the previous ones,
+// with the learning rate folded into its leaves.
+func trainTreeEnsemble(features [][]float64, labels []float64, options domain.RankingTrainingOptions) *domain.RankingModel {
+	model := &domain.RankingModel{
+		Type:     domain.TreeEnsembleModel,
+		Features: options.Features,
+	}
+	for _, label := range labels {
+		model.Bias += label
+	}
+	model.Bias /= float64(len(labels))
+
+	predictions := make([]float64, len(labels))
+	residuals := make([]float64, len(labels))
+	for i := range predictions {
+		predictions[i] = model.Bias
+	}
+	samples := make([]int, len(labels))
+	for i := range samples {
+		samples[i] = i
+	}
+
+	for tree := 0; tree < options.Iterations; tree++ {
+		for i, label := range labels {
+			residuals[i] = label - predictions[i]
+		}
+		builder := &treeBuilder{
+			features:  features,
+			residuals: residuals,
+			options:   options,
+		}
+		builder.build(samples, 0)
+		fitted := domain.RegressionTree{Nodes: builder.nodes}
+		for i := range predictions {
+			predictions[i] += fitted.Predict(features[i])
+		}
+		model.Trees = append(model.Trees, fitted)
+	}
+	return model
+}
+
+// treeBuilder grows a regression tree on residuals, storing nodes in pre-order so that
+// children always follow their parent
+type treeBuilder struct {
+	features  [][]float64
+	residuals []float64
+	options   domain.RankingTrainingOptions
+	nodes     []domain.TreeNode
+}
+
+// build adds the subtree fitted to the samples and returns the index of its root
+func (b *treeBuilder) build(samples []int, depth int) int {
+	index := len(b.nodes)
+	b.nodes = append(b.nodes, domain.TreeNode{})
+
+	feature, threshold, ok := 0, 0.0, false
+	if depth < b.options.MaxDepth {
+		feature, threshold, ok = b.bestSplit(samples)
+	}
+	if !ok {
+		sum := 0.0
+		for _, i := range samples {
+			sum += b.residuals[i]
+		}
+		b.nodes[index] = domain.TreeNode{
+			Leaf:  true,
+			Value: b.options.LearningRate * sum / float64(len(samples)),
+		}
+		return index
+	}
+
+	left := make([]int, 0, len(samples))
+	right := make([]int, 0, len(samples))
+	for _, i := range samples {
+		if b.features[i][feature] <= threshold {
+			left = append(left, i)
+		} else {
+			right = append(right, i)
+		}
+	}
+	node := domain.TreeNode{Feature: feature, Threshold: threshold}
+	node.Left = b.build(left, depth+1)
+	node.Right = b.build(right, depth+1)
+	b.nodes[index] = node
+	return index
+}
+
+// bestSplit finds the split of the samples that most reduces the squared error of the residuals
+// while leaving enough samples on both sides
+func (b *treeBuilder) bestSplit(samples []int) (int, float64, bool) {
+	minLeaf := b.options.MinLeafSamples
+	if len(samples) < 2*minLeaf {
+		return 0, 0, false
+	}
+	total := 0.0
+	for _, i := range samples {
+		total += b.residuals[i]
+	}
+	n := float64(len(samples))
+	baseline := total * total / n
+
+	bestGain := 1e-12
+	bestFeature, bestThreshold, found := 0, 0.0, false
+	sorted := make([]int, len(samples))
+	for feature := range b.options.Features {
+		copy(sorted, samples)
+		sort.SliceStable(sorted, func(x, y int) bool {
+			return b.features[sorted[x]][feature] < b.features[sorted[y]][feature]
+		})
+
+		leftSum := 0.0
+		for k := 0; k < len(sorted)-1; k++ {
+			leftSum += b.residuals[sorted[k]]
+			current, next := b.features[sorted[k]][feature], b.features[sorted[k+1]][feature]
+			leftCount := k + 1
+			if current == next || leftCount < minLeaf || len(sorted)-leftCount < minLeaf {
+				continue
+			}
+			rightSum := total - leftSum
+			gain := leftSum*leftSum/float64(leftCount) + rightSum*rightSum/(n-float64(leftCount)) - baseline
+			if gain > bestGain {
+				bestGain = gain
+				bestFeature = feature
+				bestThreshold = (current + next) / 2
+				found = true
+			}
+		}
+	}
+	return bestFeature, bestThreshold, found
+}
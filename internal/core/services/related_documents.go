@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+const (
+	// defaultMaxRelated is the number of related documents returned when no limit is given
+	defaultMaxRelated = 5
+	// maxRelated bounds the number of related documents of a request
+	maxRelated = 50
+)
+
+// RelatedDocuments returns the documents most similar to a document, best first. Duplicates
+// and documents at the same URL are left out.
+func (s searchService) RelatedDocuments(ctx context.Context, documentID string, limit int) ([]*domain.Document, error) {
+	if documentID == "" {
+		return nil, errors.New("document ID cannot be empty")
+	}
+	if limit <= 0 {
+		limit = defaultMaxRelated
+	}
+	limit = min(limit, maxRelated)
+	ctx = withAccessScope(ctx)
+
+	document, err := s.docRepo.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	if document == nil {
+		return nil, fmt.Errorf("document with ID %s does not exist", documentID)
+	}
+	related, err := s.docRepo.MoreLikeThis(ctx, document, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find related documents: %w", err)
+	}
+	return related, nil
+}
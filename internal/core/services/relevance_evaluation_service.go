@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+)
+
+// relevanceEvaluationService implements the incoming.RelevanceEvaluationService interface
+type relevanceEvaluationService struct {
+	search incoming.SearchService
+}
+
+// NewRelevanceEvaluationService creates a relevance evaluation service running judged queries through the search service
+func NewRelevanceEvaluationService(search incoming.SearchService) incoming.RelevanceEvaluationService {
+	return &relevanceEvaluationService{
+		search: search,
+	}
+}
+
+// Evaluate searches each judged query with the configuration and measures the top k results
+func (r relevanceEvaluationService) Evaluate(ctx context.Context, judgments []domain.Judgment, config domain.RankingConfig, k int) (*domain.EvaluationReport, error) {
+	if k <= 0 {
+		return nil, errors.New("k must be positive")
+	}
+	queries, err := domain.GroupJudgments(judgments)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.EvaluationReport{
+		Config:  config.Name,
+		K:       k,
+		Queries: make([]domain.QueryEvaluation, 0, len(queries)),
+	}
+	for _, judged := range queries {
+		ranking, err := r.rank(ctx, judged, config, k)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate query %q: %w", judged.Query, err)
+		}
+		report.Queries = append(report.Queries, domain.QueryEvaluation{
+			Query:   judged.Query,
+			Ranking: ranking,
+			Metrics: domain.EvaluateRanking(ranking, judged.Grades, k),
+		})
+	}
+	report.Mean = domain.MeanMetrics(report.Queries)
+	return report, nil
+}
+
+// Compare evaluates two configurations over the same judgments and diffs their metrics,
+// candidate minus baseline
+func (r relevanceEvaluationService) Compare(ctx context.Context, judgments []domain.Judgment, baseline, candidate domain.RankingConfig, k int) (*domain.EvaluationComparison, error) {
+	baselineReport, err := r.Evaluate(ctx, judgments, baseline, k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate baseline: %w", err)
+	}
+	candidateReport, err := r.Evaluate(ctx, judgments, candidate, k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate candidate: %w", err)
+	}
+
+	comparison := &domain.EvaluationComparison{
+		Baseline:  baselineReport,
+		Candidate: candidateReport,
+		Delta:     candidateReport.Mean.Sub(baselineReport.Mean),
+		Queries:   make([]domain.QueryComparison, 0, len(baselineReport.Queries)),
+	}
+	// Both reports list the queries in judgment order
+	for i, before := range baselineReport.Queries {
+		after := candidateReport.Queries[i]
+		comparison.Queries = append(comparison.Queries, domain.QueryComparison{
+			Query:     before.Query,
+			Baseline:  before.Metrics,
+			Candidate: after.Metrics,
+			Delta:     after.Metrics.Sub(before.Metrics),
+		})
+	}
+	return comparison, nil
+}
+
+// rank searches a judged query and returns the references of the top k documents. A document
+// is referenced by its URL when the judgments do, by its ID otherwise.
+func (r relevanceEvaluationService) rank(ctx context.Context, judged domain.JudgedQuery, config domain.RankingConfig, k int) ([]string, error) {
+	query, err := domain.NewSearchQuery(judged.Query)
+	if err != nil {
+		return nil, err
+	}
+	config.Apply(query)
+	query.PageSize = k
+
+	result, err := r.search.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	ranking := make([]string, 0, len(result.Documents))
+	for _, document := range result.Documents {
+		ref := document.ID
+		if _, judgedByID := judged.Grades[document.ID]; !judgedByID {
+			if _, judgedByURL := judged.Grades[document.URL]; judgedByURL {
+				ref = document.URL
+			}
+		}
+		ranking = append(ranking, ref)
+	}
+	return ranking, nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// defaultRerankWindow is the number of top candidates re-ranked when no window is set
+const defaultRerankWindow = domain.MaxPageSize
+
+// WithReranker sets the re-ranker applied to the top candidates of relevance-ordered searches.
+// A window of zero uses the default number of candidates. The candidates are fetched as a single
+// page, so windows are capped at the maximum page size.
+func WithReranker(reranker outgoing.Reranker, window int) SearchServiceOption {
+	return func(s *searchService) {
+		s.reranker = reranker
+		s.rerankWindow = min(window, domain.MaxPageSize)
+		if s.rerankWindow <= 0 {
+			s.rerankWindow = defaultRerankWindow
+		}
+	}
+}
+
+// shouldRerank reports whether the requested page can be re-ranked.
+// Explicit sorting and pages beyond the window keep the repository order.
+func (s searchService) shouldRerank(query *domain.SearchQuery) bool {
+	return s.reranker != nil &&
+		!query.SkipReranking &&
+		len(query.SortFields) == 0 &&
+		query.Offset()+query.Limit() <= s.rerankWindow
+}
+
+// rerank re-orders the top of a ranking, leaving the documents beyond the window in place.
+// Re-ranking is best effort: the ranking is kept as is when the re-ranker fails.
+func (s searchService) rerank(ctx context.Context, query *domain.SearchQuery, documents []*domain.Document) []*domain.Document {
+	n := min(len(documents), s.rerankWindow)
+	if n < 2 {
+		return documents
+	}
+	reranked, err := s.reranker.Rerank(ctx, query, documents[:n])
+	if err != nil || len(reranked) != n {
+		return documents
+	}
+	ranking := make([]*domain.Document, 0, len(documents))
+	ranking = append(ranking, reranked...)
+	return append(ranking, documents[n:]...)
+}
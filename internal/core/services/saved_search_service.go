@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// defaultAlertDocuments is the number of documents an alert lists when the saved query does not
+// set a page size
+const defaultAlertDocuments = 20
+
+// savedSearchService implements the incoming.SavedSearchService interface
+type savedSearchService struct {
+	repo      outgoing.SavedSearchRepository
+	search    incoming.SearchService
+	notifiers map[domain.AlertChannelType]outgoing.AlertNotifier
+}
+
+// NewSavedSearchService creates a saved search service running saved queries through the search
+// service and delivering their alerts with the notifiers of their channels
+func NewSavedSearchService(repo outgoing.SavedSearchRepository, search incoming.SearchService, notifiers ...outgoing.AlertNotifier) incoming.SavedSearchService {
+	byChannel := make(map[domain.AlertChannelType]outgoing.AlertNotifier, len(notifiers))
+	for _, notifier := range notifiers {
+		byChannel[notifier.Channel()] = notifier
+	}
+	return &savedSearchService{
+		repo:      repo,
+		search:    search,
+		notifiers: byChannel,
+	}
+}
+
+// CreateSavedSearch validates and stores a saved search. Its first run alerts of the documents
+// crawled since its creation.
+func (s savedSearchService) CreateSavedSearch(ctx context.Context, search *domain.SavedSearch) (*domain.SavedSearch, error) {
+	if search == nil {
+		return nil, errors.New("saved search cannot be nil")
+	}
+	// Saved searches belong to the calling user, so that they alert of the documents it can read
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	search.UserID = userID
+	search.Query.Page = 1
+	if search.Query.PageSize == 0 {
+		search.Query.PageSize = defaultAlertDocuments
+	}
+	// The expression and vector are derived from the query text when the search runs
+	search.Query.Expression = nil
+	search.Query.Vector = nil
+	if err := search.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid saved search: %w", err)
+	}
+	for _, channel := range search.Channels {
+		if _, ok := s.notifiers[channel.Type]; !ok {
+			return nil, fmt.Errorf("alert channel %q is not configured", channel.Type)
+		}
+	}
+
+	now := time.Now()
+	search.ID = uuid.NewString()
+	search.LastRunAt = time.Time{}
+	search.CreatedAt = now
+	search.UpdatedAt = now
+	if err := s.repo.Save(ctx, search); err != nil {
+		return nil, fmt.Errorf("failed to save saved search: %w", err)
+	}
+	return search, nil
+}
+
+// GetSavedSearch returns a saved search of the calling user by ID. Saved searches of other users
+// are not found.
+func (s savedSearchService) GetSavedSearch(ctx context.Context, id string) (*domain.SavedSearch, error) {
+	if id == "" {
+		return nil, errors.New("saved search ID cannot be empty")
+	}
+	userID, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	search, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+	if search == nil || search.UserID != userID {
+		return nil, fmt.Errorf("saved search %s not found", id)
+	}
+	return search, nil
+}
+
+// ListSavedSearches returns the saved searches of a user, who must be the calling user
+func (s savedSearchService) ListSavedSearches(ctx context.Context, userID string) ([]*domain.SavedSearch, error) {
+	if userID == "" {
+		return nil, errors.New("user ID cannot be empty")
+	}
+	caller, err := callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userID != caller {
+		return nil, fmt.Errorf("saved searches of user %s not found", userID)
+	}
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// DeleteSavedSearch deletes a saved search of the calling user by ID
+func (s savedSearchService) DeleteSavedSearch(ctx context.Context, id string) error {
+	if _, err := s.GetSavedSearch(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// callerID returns the ID of the user calling the service, whom saved searches belong to
+func callerID(ctx context.Context) (string, error) {
+	user := domain.UserFromContext(ctx)
+	if user == nil || user.ID == "" {
+		return "", errors.New("saved searches require an authenticated user")
+	}
+	return user.ID, nil
+}
+
+// EvaluateSavedSearches runs every saved search over the documents crawled since its last run
+// and alerts its channels of the matches. A saved search is marked as run only once all of its
+// channels were notified, so failed deliveries are retried, with the same matches, next time.
+func (s savedSearchService) EvaluateSavedSearches(ctx context.Context, now time.Time) (*domain.SavedSearchReport, error) {
+	searches, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+
+	report := &domain.SavedSearchReport{Failures: make([]domain.SavedSearchFailure, 0)}
+	for _, search := range searches {
+		report.Evaluated++
+		alerted, err := s.evaluate(ctx, search, now)
+		if err == nil {
+			err = s.repo.MarkRun(ctx, search.ID, now)
+		}
+		if err != nil {
+			report.Failures = append(report.Failures, domain.SavedSearchFailure{
+				SavedSearchID: search.ID,
+				Name:          search.Name,
+				Error:         err.Error(),
+			})
+			continue
+		}
+		if alerted {
+			report.Alerted++
+		}
+	}
+	return report, nil
+}
+
+// evaluate searches the documents newly matching a saved search and notifies its channels of
+// them. It reports whether there were matches to alert of.
+func (s savedSearchService) evaluate(ctx context.Context, search *domain.SavedSearch, now time.Time) (bool, error) {
+	since := search.Since()
+	result, err := s.search.Search(withOwnerScope(ctx, search.UserID), search.CrawledBetween(since, now))
+	if err != nil {
+		return false, fmt.Errorf("failed to search: %w", err)
+	}
+	if result.TotalHits == 0 {
+		return false, nil
+	}
+
+	alert := &domain.SavedSearchAlert{
+		SavedSearch: search,
+		Since:       since,
+		Until:       now,
+		TotalHits:   result.TotalHits,
+		Documents:   result.Documents,
+	}
+	var errs []error
+	for _, channel := range search.Channels {
+		notifier, ok := s.notifiers[channel.Type]
+		if !ok {
+			errs = append(errs, fmt.Errorf("alert channel %q is not configured", channel.Type))
+			continue
+		}
+		if err := notifier.Notify(ctx, alert, channel); err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify %s channel: %w", channel.Type, err))
+		}
+	}
+	return true, errors.Join(errs...)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// WithSearchCache sets the cache answering repeated searches. The documents written through the
+// repository must invalidate it; index settings changes only apply once entries expire.
+func WithSearchCache(cache outgoing.SearchCache) SearchServiceOption {
+	return func(s *searchService) {
+		s.cache = cache
+	}
+}
+
+// cachedResult returns the cached result of a query as a new search, with its own query ID
+func (s searchService) cachedResult(ctx context.Context, query *domain.SearchQuery) (*domain.SearchResult, bool) {
+	if !s.cacheable(query) {
+		return nil, false
+	}
+	result, ok := s.cache.Get(cacheKey(ctx, query))
+	if !ok {
+		return nil, false
+	}
+	result.QueryID = uuid.NewString()
+	return result, true
+}
+
+// cacheResult caches the result of a query
+func (s searchService) cacheResult(ctx context.Context, query *domain.SearchQuery, result *domain.SearchResult) {
+	if s.cacheable(query) {
+		s.cache.Put(cacheKey(ctx, query), query.CacheIndexIDs(), result)
+	}
+}
+
+// cacheKey returns the cache key of a query run with the access scope of the context, so that
+// callers reading different documents never share results
+func cacheKey(ctx context.Context, query *domain.SearchQuery) string {
+	return query.CacheKey() + ":" + domain.AccessScopeFromContext(ctx).CacheKey()
+}
+
+// cacheable reports whether the results of a query can be cached. Cursor pagination walks
+// point in time snapshots and is never cached.
+func (s searchService) cacheable(query *domain.SearchQuery) bool {
+	return s.cache != nil && !query.UseSearchAfter
+}
+
+// CacheStats returns the activity of the search cache, or empty stats without a cache
+func (s searchService) CacheStats() domain.CacheStats {
+	if s.cache == nil {
+		return domain.CacheStats{}
+	}
+	return s.cache.Stats()
+}
@@ -3,6 +3,10 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
 	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
@@ -11,15 +15,41 @@ import (
 
 // searchService implements the incoming.SearchService interface
 type searchService struct {
-	docRepo   outgoing.DocumentRepository
-	indexRepo outgoing.IndexRepository
+	docRepo      outgoing.DocumentRepository
+	indexRepo    outgoing.IndexRepository
+	diversifier  *Diversifier
+	completer    outgoing.SuggestionCompleter
+	queryHistory outgoing.QueryHistoryRepository
+	feedbackRepo outgoing.SuggestionFeedbackRepository
+	corrector    outgoing.SpellingCorrector
+	embedder     outgoing.Embedder
+	queryLog     outgoing.QueryLogRepository
+	reranker     outgoing.Reranker
+	rerankWindow int
+	cache        outgoing.SearchCache
+	queryRules   outgoing.QueryRuleRepository
 }
 
+// SearchServiceOption is a function that configures a search service
+type SearchServiceOption func(*searchService)
+
 // NewSearchService creates a new search service with the provided dependencies
-func NewSearchService(docRepo outgoing.DocumentRepository, indexRepo outgoing.IndexRepository) incoming.SearchService {
-	return &searchService{
-		docRepo:   docRepo,
-		indexRepo: indexRepo,
+func NewSearchService(docRepo outgoing.DocumentRepository, indexRepo outgoing.IndexRepository, options ...SearchServiceOption) incoming.SearchService {
+	service := &searchService{
+		docRepo:     docRepo,
+		indexRepo:   indexRepo,
+		diversifier: NewDiversifier(),
+	}
+	for _, option := range options {
+		option(service)
+	}
+	return service
+}
+
+// WithDiversifier replaces the diversifier used to re-rank the top results
+func WithDiversifier(diversifier *Diversifier) SearchServiceOption {
+	return func(s *searchService) {
+		s.diversifier = diversifier
 	}
 }
 
@@ -27,23 +57,279 @@ func NewSearchService(docRepo outgoing.DocumentRepository, indexRepo outgoing.In
 var _ incoming.SearchService = (*searchService)(nil)
 
 func (s searchService) Search(ctx context.Context, query *domain.SearchQuery) (*domain.SearchResult, error) {
-	//TODO implement me
-	panic("implement me")
+	if query == nil {
+		return nil, errors.New("search query cannot be nil")
+	}
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid search query: %w", err)
+	}
+	if query.UseQuerySyntax {
+		parsed, err := parseQuerySyntax(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search query: %w", err)
+		}
+		query = parsed
+	}
+	ctx = withAccessScope(ctx)
+	start := time.Now()
+	rules, err := s.matchingRules(ctx, query, start)
+	if err != nil {
+		return nil, err
+	}
+	if result := redirectResult(query, rules); result != nil {
+		result.Took = time.Since(start).Milliseconds()
+		return result, nil
+	}
+	if result, ok := s.cachedResult(ctx, query); ok {
+		if err := s.applyQueryRules(ctx, query, result, rules); err != nil {
+			return nil, err
+		}
+		result.Took = time.Since(start).Milliseconds()
+		// Auto-corrected results were recorded under their corrected text when first searched
+		if result.CorrectedFrom == "" {
+			s.recordQuery(ctx, query, result)
+		}
+		s.logQuery(ctx, query, result)
+		return result, nil
+	}
+	requested := query
+	query, err = s.withFreshness(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.execute(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// The executed query differs from the requested one when a correction was applied
+	executed := query
+	if s.needsCorrection(query, result) {
+		executed, result, err = s.correct(ctx, query, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The cache holds the ranked results, so that rules apply as they stand when it is read
+	s.cacheResult(ctx, requested, result)
+	if err := s.applyQueryRules(ctx, query, result, rules); err != nil {
+		return nil, err
+	}
+	result.Took = time.Since(start).Milliseconds()
+	s.recordQuery(ctx, executed, result)
+	s.logQuery(ctx, query, result)
+	return result, nil
+}
+
+// execute runs the query against the repository and assembles its result
+func (s searchService) execute(ctx context.Context, query *domain.SearchQuery) (*domain.SearchResult, error) {
+	result := &domain.SearchResult{
+		Page:     query.Page,
+		PageSize: query.PageSize,
+		QueryID:  uuid.NewString(),
+	}
+
+	if query.UseSearchAfter {
+		documents, total, nextCursor, err := s.docRepo.SearchAfter(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search documents: %w", err)
+		}
+		result.Documents = documents
+		result.TotalHits = total
+		result.NextCursor = nextCursor
+	} else if query.IsFederated() {
+		documents, total, indexHits, err := s.retrieveFederated(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search indexes: %w", err)
+		}
+		result.Documents = documents
+		result.TotalHits = total
+		result.IndexHits = indexHits
+	} else if query.IsVectorSearch() {
+		documents, total, err := s.retrieveByVector(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search documents by vector: %w", err)
+		}
+		result.Documents = documents
+		result.TotalHits = total
+	} else {
+		documents, total, err := s.retrieve(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search documents: %w", err)
+		}
+		result.Documents = documents
+		result.TotalHits = total
+	}
+
+	if len(query.HighlightFields) > 0 {
+		result.Highlighting = domain.Highlighting(result.Documents)
+	}
+
+	if len(query.Facets) > 0 {
+		facetQuery := query
+		if query.IsFederated() {
+			facetQuery = restrictToIndexes(query, query.IndexIDs(), 0)
+		}
+		facets, err := s.docRepo.Facets(ctx, facetQuery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute facets: %w", err)
+		}
+		result.Facets = facets
+	}
+
+	result.TotalPages = (result.TotalHits + query.PageSize - 1) / query.PageSize
+	return result, nil
+}
+
+// parseQuerySyntax returns a copy of the query with its query string parsed into an expression,
+// leaving the filters of the original query untouched
+func parseQuerySyntax(query *domain.SearchQuery) (*domain.SearchQuery, error) {
+	parsed := *query
+	parsed.Filters = make(map[string]interface{}, len(query.Filters))
+	for name, value := range query.Filters {
+		parsed.Filters[name] = value
+	}
+	if err := parsed.ApplyQuerySyntax(); err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// recordQuery adds a successful first-page query to the query history, unless it skips analytics.
+// Recording is best effort and never fails the search.
+func (s searchService) recordQuery(ctx context.Context, query *domain.SearchQuery, result *domain.SearchResult) {
+	if s.queryHistory == nil || query.SkipAnalytics || result.TotalHits == 0 || query.Page > 1 || query.Cursor != "" {
+		return
+	}
+	_ = s.queryHistory.RecordQuery(ctx, query.Query, time.Now())
+}
+
+// retrieve fetches a page of results. Pages within the re-ranking window are re-ranked, then
+// pages within the diversification window are diversified.
+func (s searchService) retrieve(ctx context.Context, query *domain.SearchQuery) ([]*domain.Document, int, error) {
+	diversify := s.shouldDiversify(query)
+	rerank := s.shouldRerank(query)
+	if !diversify && !rerank {
+		return s.docRepo.Search(ctx, query)
+	}
+
+	window := 0
+	if diversify {
+		window = diversificationWindow
+	}
+	if rerank {
+		window = max(window, s.rerankWindow)
+	}
+	windowQuery := *query
+	windowQuery.Page = 1
+	windowQuery.PageSize = window
+	documents, total, err := s.docRepo.Search(ctx, &windowQuery)
+	if err != nil {
+		return nil, 0, err
+	}
+	if rerank {
+		documents = s.rerank(ctx, query, documents)
+	}
+	if diversify {
+		documents = s.diversifyWindow(documents)
+	}
+	return paginate(documents, query), total, nil
+}
+
+// shouldDiversify reports whether the requested page can be diversified.
+// Explicit sorting and pages beyond the window keep the repository order.
+func (s searchService) shouldDiversify(query *domain.SearchQuery) bool {
+	return s.diversifier != nil &&
+		!query.SkipDiversification &&
+		len(query.SortFields) == 0 &&
+		query.Offset()+query.Limit() <= diversificationWindow
+}
+
+// paginate returns the page of documents requested by the query
+func paginate(documents []*domain.Document, query *domain.SearchQuery) []*domain.Document {
+	start := query.Offset()
+	if start >= len(documents) {
+		return []*domain.Document{}
+	}
+	end := start + query.Limit()
+	if end > len(documents) {
+		end = len(documents)
+	}
+	return documents[start:end]
 }
 
 func (s searchService) GetDocument(ctx context.Context, id string) (*domain.Document, error) {
 	if id == "" {
 		return nil, errors.New("document ID cannot be empty")
 	}
-	return s.docRepo.GetByID(ctx, id)
+	return s.docRepo.GetByID(withAccessScope(ctx), id)
 }
 
 func (s searchService) SuggestQueries(ctx context.Context, partialQuery string, maxSuggestions int) ([]domain.SearchSuggestion, error) {
-	//TODO implement me
-	panic("implement me")
+	prefix := domain.NormalizeQuery(partialQuery)
+	if prefix == "" {
+		return nil, errors.New("partial query cannot be empty")
+	}
+	if maxSuggestions <= 0 {
+		maxSuggestions = defaultMaxSuggestions
+	}
+
+	sources := make([]suggestionSource, 0, 2)
+	if s.completer != nil {
+		sources = append(sources, s.completer.Complete)
+	}
+	if source := s.popularSource(); source != nil {
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		return []domain.SearchSuggestion{}, nil
+	}
+
+	// Fetch more candidates than requested so that blending can reorder them
+	candidates, err := gatherSuggestions(ctx, sources, prefix, maxSuggestions*2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest queries: %w", err)
+	}
+	if s.feedbackRepo == nil {
+		return rankSuggestions(candidates, time.Now(), maxSuggestions), nil
+	}
+
+	// Rank all candidates so that click feedback can promote those just below the cut
+	suggestions := rankSuggestions(candidates, time.Now(), 0)
+	texts := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		texts[i] = suggestion.Text
+	}
+	// Feedback is best effort: without it the blended ranking is kept
+	if stats, err := s.feedbackRepo.GetStats(ctx, texts); err == nil {
+		applyFeedback(suggestions, stats)
+	}
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	_ = s.feedbackRepo.RecordImpressions(ctx, suggestions, time.Now())
+	return suggestions, nil
 }
 
 func (s searchService) TrackSuggestionSelection(ctx context.Context, suggestion domain.SearchSuggestion, partialQuery string, position int, timeTakenMs int64) error {
-	//TODO implement me
-	panic("implement me")
+	if s.feedbackRepo == nil {
+		return errors.New("suggestion feedback tracking is not configured")
+	}
+	selection := &domain.SuggestionSelection{
+		Text:         suggestion.Text,
+		Source:       suggestion.Source,
+		PartialQuery: partialQuery,
+		Position:     position,
+		TimeTakenMs:  timeTakenMs,
+		SelectedAt:   time.Now(),
+	}
+	if err := selection.Validate(); err != nil {
+		return fmt.Errorf("invalid suggestion selection: %w", err)
+	}
+	if err := s.feedbackRepo.RecordSelection(ctx, selection); err != nil {
+		return fmt.Errorf("failed to track suggestion selection: %w", err)
+	}
+	return nil
 }
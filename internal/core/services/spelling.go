@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// correctionHitThreshold is the number of hits below which a query is checked for misspellings
+const correctionHitThreshold = 3
+
+// WithSpellingCorrector sets the corrector used to suggest "did you mean" queries
+func WithSpellingCorrector(corrector outgoing.SpellingCorrector) SearchServiceOption {
+	return func(s *searchService) {
+		s.corrector = corrector
+	}
+}
+
+// needsCorrection reports whether a first page with few hits should be checked for misspellings.
+// Query strings are not corrected, as the corrector would treat their operators and fields as words.
+func (s searchService) needsCorrection(query *domain.SearchQuery, result *domain.SearchResult) bool {
+	return s.corrector != nil &&
+		!query.UseQuerySyntax &&
+		result.TotalHits < correctionHitThreshold &&
+		query.Page == 1 &&
+		query.Cursor == ""
+}
+
+// correct looks up a correction of the query. The correction is offered in the suggestions of the
+// result, or, when the query allows auto-correction, searched instead if it finds more hits.
+// It returns the query whose result is returned along with that result.
+func (s searchService) correct(ctx context.Context, query *domain.SearchQuery, result *domain.SearchResult) (*domain.SearchQuery, *domain.SearchResult, error) {
+	correction, err := s.corrector.Correct(ctx, query.Query)
+	// Spelling correction is best effort and never fails the search
+	if err != nil || correction == nil || domain.NormalizeQuery(correction.Text) == domain.NormalizeQuery(query.Query) {
+		return query, result, nil
+	}
+
+	if !query.AutoCorrect {
+		result.Suggestions = append(result.Suggestions, correction.Text)
+		return query, result, nil
+	}
+
+	corrected := *query
+	corrected.Query = correction.Text
+	correctedResult, err := s.execute(ctx, &corrected)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search corrected query: %w", err)
+	}
+	if correctedResult.TotalHits <= result.TotalHits {
+		result.Suggestions = append(result.Suggestions, correction.Text)
+		return query, result, nil
+	}
+
+	correctedResult.CorrectedFrom = query.Query
+	return &corrected, correctedResult, nil
+}
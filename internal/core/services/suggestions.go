@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	// defaultMaxSuggestions is the number of suggestions returned when the caller does not specify one
+	defaultMaxSuggestions = 10
+	// suggestionBudget bounds the time spent gathering suggestions; slower sources are dropped
+	suggestionBudget = 100 * time.Millisecond
+	// popularityHalfLife is the age at which the weight of a past query is halved
+	popularityHalfLife = 7 * 24 * time.Hour
+	// popularWeight and autocompleteWeight balance past queries against document completions
+	popularWeight      = 0.6
+	autocompleteWeight = 0.4
+	// minCTRBoost and maxCTRBoost bound how far click feedback can move a suggestion
+	minCTRBoost = 0.5
+	maxCTRBoost = 2
+)
+
+// suggestionSource fetches candidate suggestions for a prefix
+type suggestionSource func(ctx context.Context, prefix string, limit int) ([]domain.SearchSuggestion, error)
+
+// sourceResult holds the candidates returned by one suggestion source
+type sourceResult struct {
+	suggestions []domain.SearchSuggestion
+	err         error
+}
+
+// gatherSuggestions queries all sources concurrently and returns the candidates received within the budget.
+// An error is returned only when no source answered successfully.
+func gatherSuggestions(ctx context.Context, sources []suggestionSource, prefix string, limit int) ([]domain.SearchSuggestion, error) {
+	ctx, cancel := context.WithTimeout(ctx, suggestionBudget)
+	defer cancel()
+
+	results := make(chan sourceResult, len(sources))
+	for _, source := range sources {
+		go func(source suggestionSource) {
+			suggestions, err := source(ctx, prefix, limit)
+			results <- sourceResult{suggestions, err}
+		}(source)
+	}
+
+	var candidates []domain.SearchSuggestion
+	var lastErr error
+	answered := 0
+	for range sources {
+		select {
+		case result := <-results:
+			if result.err != nil {
+				lastErr = result.err
+				continue
+			}
+			answered++
+			candidates = append(candidates, result.suggestions...)
+		case <-ctx.Done():
+			if answered == 0 && lastErr == nil {
+				lastErr = ctx.Err()
+			}
+			return candidates, noAnswerError(answered, lastErr)
+		}
+	}
+	return candidates, noAnswerError(answered, lastErr)
+}
+
+// noAnswerError returns the last source error when no source answered
+func noAnswerError(answered int, lastErr error) error {
+	if answered > 0 {
+		return nil
+	}
+	return lastErr
+}
+
+// rankSuggestions blends the candidates of all sources into a single ranking of at most limit
+// suggestions, or of all candidates when limit is zero.
+// Popular queries are scored by log(1+UseCount) decayed by the age of their last use,
+// completions by their source score; each source is normalized before weighting.
+func rankSuggestions(candidates []domain.SearchSuggestion, now time.Time, limit int) []domain.SearchSuggestion {
+	raw := make([]float64, len(candidates))
+	maxBySource := make(map[domain.SuggestionSource]float64)
+	for i, candidate := range candidates {
+		raw[i] = candidate.Score
+		if candidate.Source == domain.SuggestionSourcePopular {
+			age := now.Sub(candidate.LastUsedTime)
+			if age < 0 {
+				age = 0
+			}
+			raw[i] = math.Log1p(float64(candidate.UseCount)) * math.Pow(0.5, float64(age)/float64(popularityHalfLife))
+		}
+		if raw[i] > maxBySource[candidate.Source] {
+			maxBySource[candidate.Source] = raw[i]
+		}
+	}
+
+	merged := make(map[string]*domain.SearchSuggestion)
+	contributions := make(map[string]float64)
+	order := make([]string, 0, len(candidates))
+	for i, candidate := range candidates {
+		key := domain.NormalizeQuery(candidate.Text)
+		if key == "" {
+			continue
+		}
+
+		score := 0.0
+		if maxScore := maxBySource[candidate.Source]; maxScore > 0 {
+			score = raw[i] / maxScore * sourceWeight(candidate.Source)
+		}
+
+		existing, ok := merged[key]
+		if !ok {
+			suggestion := candidate
+			suggestion.Score = score
+			merged[key] = &suggestion
+			contributions[key] = score
+			order = append(order, key)
+			continue
+		}
+		existing.Score += score
+		if candidate.Source == domain.SuggestionSourcePopular {
+			existing.UseCount = candidate.UseCount
+			existing.LastUsedTime = candidate.LastUsedTime
+		}
+		// The suggestion is attributed to the source contributing the most to its score
+		if score > contributions[key] {
+			existing.Source = candidate.Source
+			existing.Text = candidate.Text
+			contributions[key] = score
+		}
+	}
+
+	ranked := make([]domain.SearchSuggestion, 0, len(order))
+	for _, key := range order {
+		ranked = append(ranked, *merged[key])
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// applyFeedback scales the score of each suggestion by its click-through boost and re-sorts them
+func applyFeedback(suggestions []domain.SearchSuggestion, stats map[string]domain.SuggestionStats) {
+	for i := range suggestions {
+		stat, ok := stats[domain.NormalizeQuery(suggestions[i].Text)]
+		if !ok {
+			continue
+		}
+		suggestions[i].Score *= math.Min(math.Max(stat.CTRBoost(), minCTRBoost), maxCTRBoost)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+}
+
+// sourceWeight returns the blending weight of a suggestion source
+func sourceWeight(source domain.SuggestionSource) float64 {
+	switch source {
+	case domain.SuggestionSourcePopular, domain.SuggestionSourceHistory:
+		return popularWeight
+	case domain.SuggestionSourceAutocomplete:
+		return autocompleteWeight
+	}
+	return autocompleteWeight / 2
+}
+
+// WithSuggestionCompleter sets the completer used to suggest queries from indexed documents
+func WithSuggestionCompleter(completer outgoing.SuggestionCompleter) SearchServiceOption {
+	return func(s *searchService) {
+		s.completer = completer
+	}
+}
+
+// WithQueryHistory sets the repository used to record queries and suggest popular ones
+func WithQueryHistory(queryHistory outgoing.QueryHistoryRepository) SearchServiceOption {
+	return func(s *searchService) {
+		s.queryHistory = queryHistory
+	}
+}
+
+// WithSuggestionFeedback sets the repository used to track suggestion impressions and selections
+func WithSuggestionFeedback(feedbackRepo outgoing.SuggestionFeedbackRepository) SearchServiceOption {
+	return func(s *searchService) {
+		s.feedbackRepo = feedbackRepo
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/incoming"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+// textAnalysisService implements the incoming.TextAnalysisService interface
+type textAnalysisService struct {
+	indexRepo outgoing.IndexRepository
+	analyzer  outgoing.TextAnalyzer
+}
+
+// NewTextAnalysisService creates a new text analysis service with the provided dependencies
+func NewTextAnalysisService(indexRepo outgoing.IndexRepository, analyzer outgoing.TextAnalyzer) incoming.TextAnalysisService {
+	return &textAnalysisService{
+		indexRepo: indexRepo,
+		analyzer:  analyzer,
+	}
+}
+
+// UpdateSynonyms replaces the synonym rules of an index. The new rules apply to searches
+// right away, without reindexing the documents.
+func (t textAnalysisService) UpdateSynonyms(ctx context.Context, indexID string, rules []domain.SynonymRule) error {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid synonym rule %q: %w", rule.String(), err)
+		}
+	}
+	return t.update(ctx, indexID, func(settings *domain.IndexSettings) {
+		settings.Synonyms = rules
+	})
+}
+
+// UpdateStopwords replaces the stopwords of an index. The new stopwords apply to searches
+// right away, without reindexing the documents.
+func (t textAnalysisService) UpdateStopwords(ctx context.Context, indexID string, stopwords []string) error {
+	if err := domain.ValidateStopwords(stopwords); err != nil {
+		return err
+	}
+	return t.update(ctx, indexID, func(settings *domain.IndexSettings) {
+		settings.Stopwords = stopwords
+	})
+}
+
+// Analyze returns the search terms the index produces from text, to check its synonyms and stopwords
+func (t textAnalysisService) Analyze(ctx context.Context, indexID string, text string) ([]domain.AnalyzedToken, error) {
+	if text == "" {
+		return nil, errors.New("text cannot be empty")
+	}
+	index, err := t.getIndex(ctx, indexID)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := t.analyzer.Analyze(ctx, index, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze text: %w", err)
+	}
+	return tokens, nil
+}
+
+// update changes the analysis settings of an index, saves them and applies them to searches
+func (t textAnalysisService) update(ctx context.Context, indexID string, change func(settings *domain.IndexSettings)) error {
+	index, err := t.getIndex(ctx, indexID)
+	if err != nil {
+		return err
+	}
+	change(&index.Settings)
+	if err := t.indexRepo.Update(ctx, index); err != nil {
+		return fmt.Errorf("failed to save index settings: %w", err)
+	}
+	if err := t.analyzer.ApplyAnalysis(ctx, index); err != nil {
+		return fmt.Errorf("failed to apply index analysis: %w", err)
+	}
+	return nil
+}
+
+// getIndex returns an existing index
+func (t textAnalysisService) getIndex(ctx context.Context, indexID string) (*domain.Index, error) {
+	if indexID == "" {
+		return nil, errors.New("index ID cannot be empty")
+	}
+	index, err := t.indexRepo.GetByID(ctx, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index: %w", err)
+	}
+	if index == nil {
+		return nil, fmt.Errorf("index with ID %s does not exist", indexID)
+	}
+	return index, nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+)
+
+const (
+	// vectorSearchWindow is the number of candidates retrieved from each ranking of a vector search
+	vectorSearchWindow = 100
+	// rrfRankConstant dampens the weight of top ranks in reciprocal rank fusion
+	rrfRankConstant = 60
+)
+
+// WithEmbedder sets the embedder used to embed the text of semantic and hybrid queries
+func WithEmbedder(embedder outgoing.Embedder) SearchServiceOption {
+	return func(s *searchService) {
+		s.embedder = embedder
+	}
+}
+
+// retrieveByVector fetches a page of a semantic or hybrid query. Semantic queries rank the nearest
+// neighbours of the query embedding; hybrid queries fuse them with the lexical ranking using
+// reciprocal rank fusion. The total is the number of candidates ranked, as similarity has no cut-off.
+func (s searchService) retrieveByVector(ctx context.Context, query *domain.SearchQuery) ([]*domain.Document, int, error) {
+	if s.embedder == nil {
+		return nil, 0, errors.New("vector search requires an embedder")
+	}
+	vector, err := s.embedder.Embed(ctx, query.Query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(vector) == 0 {
+		return []*domain.Document{}, 0, nil
+	}
+
+	vectorQuery := *query
+	vectorQuery.Vector = vector
+	neighbours, err := s.docRepo.SearchByVector(ctx, &vectorQuery, max(vectorSearchWindow, query.Offset()+query.Limit()))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ranked := neighbours
+	if query.Type == domain.HybridSearch {
+		lexicalQuery := *query
+		lexicalQuery.Type = domain.SimpleSearch
+		lexicalQuery.Page = 1
+		lexicalQuery.PageSize = vectorSearchWindow
+		lexical, _, err := s.docRepo.Search(ctx, &lexicalQuery)
+		if err != nil {
+			return nil, 0, err
+		}
+		ranked = fuseRankings(query.Explain, lexical, neighbours)
+	}
+
+	if s.shouldRerank(query) {
+		ranked = s.rerank(ctx, query, ranked)
+	}
+	if s.shouldDiversify(query) {
+		ranked = s.diversifyWindow(ranked)
+	}
+	return paginate(ranked, query), len(ranked), nil
+}
+
+// diversifyWindow diversifies the top of a ranking, leaving the documents beyond the window in place
+func (s searchService) diversifyWindow(documents []*domain.Document) []*domain.Document {
+	if len(documents) <= diversificationWindow {
+		return s.diversifier.Diversify(documents)
+	}
+	head := s.diversifier.Diversify(documents[:diversificationWindow])
+	return append(head, documents[diversificationWindow:]...)
+}
+
+// fuseRankings merges rankings with reciprocal rank fusion: each document scores the sum of
+// 1/(k+rank) over the rankings it appears in. The fused score replaces the document score;
+// explained documents keep the explanation of each ranking below its share.
+func fuseRankings(explain bool, rankings ...[]*domain.Document) []*domain.Document {
+	scores := make(map[string]float64)
+	documents := make(map[string]*domain.Document)
+	details := make(map[string][]*domain.ScoreExplanation)
+	order := make([]string, 0)
+	for i, ranking := range rankings {
+		for rank, doc := range ranking {
+			if _, ok := documents[doc.ID]; !ok {
+				documents[doc.ID] = doc
+				order = append(order, doc.ID)
+			}
+			share := 1 / float64(rrfRankConstant+rank+1)
+			scores[doc.ID] += share
+			if explain {
+				details[doc.ID] = append(details[doc.ID], domain.NewScoreExplanation(share,
+					fmt.Sprintf("ranking %d: 1 / (%d + rank %d), ranked by:", i+1, rrfRankConstant, rank+1), doc.Explanation))
+			}
+		}
+	}
+
+	fused := make([]*domain.Document, 0, len(order))
+	for _, id := range order {
+		doc := documents[id]
+		doc.Score = scores[id]
+		if explain {
+			doc.Explanation = domain.NewScoreExplanation(doc.Score, "reciprocal rank fusion, sum of:", details[id]...)
+		}
+		fused = append(fused, doc)
+	}
+	sort.SliceStable(fused, func(i, j int) bool {
+		return fused[i].Score > fused[j].Score
+	})
+	return fused
+}
@@ -0,0 +1,58 @@
+package relevancedata
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/embedding"
+	"github.com/mohamedshehata15/intelli-index/internal/adapters/outgoing/storage"
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+	"github.com/mohamedshehata15/intelli-index/internal/core/ports/outgoing"
+	"github.com/mohamedshehata15/intelli-index/pkg/config"
+)
+
+// Database is a migrated SQLite database whose documents are embedded on save, so that semantic
+// and hybrid searches work without a running embedding service
+type Database struct {
+	Adapter   *storage.SQLAdapter
+	Documents outgoing.DocumentRepository
+	Embedder  outgoing.Embedder
+}
+
+// OpenDatabase opens and migrates the SQLite database, then seeds it with the documents
+func OpenDatabase(ctx context.Context, path string, documents []*domain.Document) (*Database, error) {
+	adapter, err := storage.NewSQLAdapter(&config.DBConfig{
+		Type:     "sqlite",
+		Database: path,
+		// A single connection keeps an in-memory database alive for the whole run
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+		LogLevel:     "silent",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := adapter.MigrationHandler().RunMigrations(); err != nil {
+		_ = adapter.Close()
+		return nil, err
+	}
+
+	embedder := embedding.NewHashingEmbedder()
+	db := &Database{
+		Adapter:   adapter,
+		Documents: embedding.NewDocumentRepository(adapter.DocumentRepository(), embedder),
+		Embedder:  embedder,
+	}
+	for _, document := range documents {
+		if err := db.Documents.Save(ctx, document); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to seed %s: %w", document.URL, err)
+		}
+	}
+	return db, nil
+}
+
+// Close closes the database
+func (d *Database) Close() {
+	_ = d.Adapter.Close()
+}
@@ -0,0 +1,143 @@
+// Package relevancedata reads the judgment lists and corpora used to evaluate and train ranking
+package relevancedata
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mohamedshehata15/intelli-index/internal/core/domain"
+)
+
+// corpusDocument is a line of a corpus file
+type corpusDocument struct {
+	ID             string    `json:"id"`
+	URL            string    `json:"url"`
+	Title          string    `json:"title"`
+	Content        string    `json:"content"`
+	ContentType    string    `json:"content_type"`
+	Lang           string    `json:"lang"`
+	MetaDesc       string    `json:"meta_desc"`
+	MetaKeywords   []string  `json:"meta_keywords"`
+	ImportanceRank float64   `json:"importance_rank"`
+	LastModified   time.Time `json:"last_modified"`
+	PublishedDate  time.Time `json:"published_date"`
+}
+
+// LoadJudgments reads a judgment file of query, document and grade records. Files ending in .tsv
+// are tab separated, others comma separated. A header row and lines starting with # are skipped.
+// Documents given as URLs are normalized the way stored documents are.
+func LoadJudgments(path string) ([]domain.Judgment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open judgments: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		reader.Comma = '\t'
+	}
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	judgments := make([]domain.Judgment, 0)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read judgments: %w", err)
+		}
+		grade, err := strconv.Atoi(strings.TrimSpace(record[2]))
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("invalid grade %q on record %d", record[2], line)
+		}
+		judgments = append(judgments, domain.Judgment{
+			Query:    record[0],
+			Document: normalizeReference(strings.TrimSpace(record[1])),
+			Grade:    grade,
+		})
+	}
+	return judgments, nil
+}
+
+// normalizeReference normalizes a document reference that is a URL
+func normalizeReference(ref string) string {
+	if !strings.Contains(ref, "://") {
+		return ref
+	}
+	url, err := domain.NewURL(ref)
+	if err != nil {
+		return ref
+	}
+	return url.Normalize()
+}
+
+// LoadCorpus reads a corpus file holding one JSON document per line
+func LoadCorpus(path string) ([]*domain.Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open corpus: %w", err)
+	}
+	defer file.Close()
+
+	documents := make([]*domain.Document, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var entry corpusDocument
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return nil, fmt.Errorf("invalid corpus document on line %d: %w", line, err)
+		}
+		contentType := entry.ContentType
+		if contentType == "" {
+			contentType = "text/html"
+		}
+		document, err := domain.NewDocument(entry.URL, entry.Title, entry.Content, contentType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid corpus document on line %d: %w", line, err)
+		}
+		document.ID = entry.ID
+		document.Lang = entry.Lang
+		document.MetaDesc = entry.MetaDesc
+		if entry.MetaKeywords != nil {
+			document.MetaKeywords = entry.MetaKeywords
+		}
+		document.ImportanceRank = entry.ImportanceRank
+		document.LastModified = entry.LastModified
+		document.PublishedDate = entry.PublishedDate
+		document.ContentLength = len(entry.Content)
+		documents = append(documents, document)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read corpus: %w", err)
+	}
+	return documents, nil
+}
+
+// LoadRankingModel reads a ranking model from a JSON file
+func LoadRankingModel(path string) (*domain.RankingModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ranking model: %w", err)
+	}
+	return domain.ParseRankingModel(data)
+}
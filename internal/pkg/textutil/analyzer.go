@@ -0,0 +1,186 @@
+package textutil
+
+import "strings"
+
+// Analyzer turns text into search terms: it tokenizes the text, drops stopwords and expands
+// synonyms. Synonyms may span several words; the longest rule matching at a position wins.
+type Analyzer struct {
+	stopwords map[string]struct{}
+	synonyms  map[string]*expansion
+	maxWords  int
+}
+
+// expansion lists the phrases a synonym phrase expands to, and whether it still matches itself
+type expansion struct {
+	phrases []string
+	keep    bool
+}
+
+// AnalyzedTerm is a phrase of analyzed text. Terms sharing a position are alternatives:
+// the words of the text and the synonyms expanded from them.
+type AnalyzedTerm struct {
+	Text     string
+	Position int
+	Synonym  bool
+}
+
+// phraseMatch is a run of words of the text along with the synonyms matching it
+type phraseMatch struct {
+	words     []string
+	position  int
+	expansion *expansion
+}
+
+// NewAnalyzer creates an analyzer dropping the given stopwords
+func NewAnalyzer(stopwords []string) *Analyzer {
+	a := &Analyzer{
+		stopwords: make(map[string]struct{}, len(stopwords)),
+		synonyms:  make(map[string]*expansion),
+	}
+	for _, stopword := range stopwords {
+		for _, token := range Tokenize(stopword) {
+			a.stopwords[token] = struct{}{}
+		}
+	}
+	return a
+}
+
+// AddEquivalent makes each phrase match all the others
+func (a *Analyzer) AddEquivalent(phrases []string) {
+	normalized := a.normalizeAll(phrases)
+	for _, phrase := range normalized {
+		exp := a.expansionOf(phrase)
+		exp.keep = true
+		for _, other := range normalized {
+			if other != phrase {
+				exp.phrases = appendUnique(exp.phrases, other)
+			}
+		}
+	}
+}
+
+// AddReplacement rewrites each phrase to the replacements
+func (a *Analyzer) AddReplacement(phrases, replacements []string) {
+	normalized := a.normalizeAll(replacements)
+	for _, phrase := range a.normalizeAll(phrases) {
+		exp := a.expansionOf(phrase)
+		for _, replacement := range normalized {
+			exp.phrases = appendUnique(exp.phrases, replacement)
+		}
+	}
+}
+
+// Analyze returns the words of the text that are not stopwords, followed at each position
+// by the synonyms expanded from the phrase starting there
+func (a *Analyzer) Analyze(text string) []AnalyzedTerm {
+	terms := make([]AnalyzedTerm, 0)
+	for _, match := range a.scan(text) {
+		if match.expansion == nil || match.expansion.keep {
+			for i, word := range match.words {
+				terms = append(terms, AnalyzedTerm{Text: word, Position: match.position + i})
+			}
+		}
+		if match.expansion != nil {
+			for _, phrase := range match.expansion.phrases {
+				terms = append(terms, AnalyzedTerm{Text: phrase, Position: match.position, Synonym: true})
+			}
+		}
+	}
+	return terms
+}
+
+// Groups returns the alternative phrases of each run of the text. A search matches the text
+// when it matches every group by any of its phrases.
+func (a *Analyzer) Groups(text string) [][]string {
+	matches := a.scan(text)
+	groups := make([][]string, 0, len(matches))
+	for _, match := range matches {
+		group := make([]string, 0, 1)
+		if match.expansion == nil || match.expansion.keep {
+			group = append(group, strings.Join(match.words, " "))
+		}
+		if match.expansion != nil {
+			for _, phrase := range match.expansion.phrases {
+				group = appendUnique(group, phrase)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// scan splits the text into runs of words, matching the longest synonym phrase at each word
+func (a *Analyzer) scan(text string) []phraseMatch {
+	type word struct {
+		text     string
+		position int
+	}
+	words := make([]word, 0)
+	for position, token := range Tokenize(text) {
+		if _, stop := a.stopwords[token]; !stop {
+			words = append(words, word{text: token, position: position})
+		}
+	}
+
+	matches := make([]phraseMatch, 0, len(words))
+	for i := 0; i < len(words); {
+		length := 1
+		var exp *expansion
+		for n := min(a.maxWords, len(words)-i); n >= 1; n-- {
+			phrase := make([]string, n)
+			for j := range phrase {
+				phrase[j] = words[i+j].text
+			}
+			if found, ok := a.synonyms[strings.Join(phrase, " ")]; ok {
+				length, exp = n, found
+				break
+			}
+		}
+
+		match := phraseMatch{position: words[i].position, expansion: exp}
+		for _, w := range words[i : i+length] {
+			match.words = append(match.words, w.text)
+		}
+		matches = append(matches, match)
+		i += length
+	}
+	return matches
+}
+
+// expansionOf returns the expansion of a phrase, creating it if needed
+func (a *Analyzer) expansionOf(phrase string) *expansion {
+	exp, ok := a.synonyms[phrase]
+	if !ok {
+		exp = &expansion{}
+		a.synonyms[phrase] = exp
+		a.maxWords = max(a.maxWords, strings.Count(phrase, " ")+1)
+	}
+	return exp
+}
+
+// normalizeAll tokenizes phrases the way text is analyzed, dropping phrases left empty
+func (a *Analyzer) normalizeAll(phrases []string) []string {
+	normalized := make([]string, 0, len(phrases))
+	for _, phrase := range phrases {
+		words := make([]string, 0)
+		for _, token := range Tokenize(phrase) {
+			if _, stop := a.stopwords[token]; !stop {
+				words = append(words, token)
+			}
+		}
+		if len(words) > 0 {
+			normalized = appendUnique(normalized, strings.Join(words, " "))
+		}
+	}
+	return normalized
+}
+
+// appendUnique appends a value unless the slice already holds it
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
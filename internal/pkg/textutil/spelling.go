@@ -0,0 +1,158 @@
+package textutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// minCorrectableLength is the length of the shortest token considered for correction
+	minCorrectableLength = 3
+	// knownTermRatio is how much more frequent a candidate must be than a known term to replace it
+	knownTermRatio = 20
+)
+
+// Vocabulary holds the frequency of every term of a corpus and corrects misspelled terms against it
+type Vocabulary struct {
+	frequencies map[string]int
+}
+
+// NewVocabulary creates an empty vocabulary
+func NewVocabulary() *Vocabulary {
+	return &Vocabulary{frequencies: make(map[string]int)}
+}
+
+// Add increments the frequency of a term
+func (v *Vocabulary) Add(term string, count int) {
+	if term == "" || count <= 0 {
+		return
+	}
+	v.frequencies[strings.ToLower(term)] += count
+}
+
+// AddText increments the frequency of every distinct token of a text once
+func (v *Vocabulary) AddText(text string) {
+	for token := range TokenSet(text, 0) {
+		v.frequencies[token]++
+	}
+}
+
+// Frequency returns the frequency of a term, zero when it is unknown
+func (v *Vocabulary) Frequency(term string) int {
+	return v.frequencies[strings.ToLower(term)]
+}
+
+// Len returns the number of distinct terms in the vocabulary
+func (v *Vocabulary) Len() int {
+	return len(v.frequencies)
+}
+
+// Correct returns the most likely intended term within maxDistance edits of term.
+// Closer candidates win over frequent ones, and frequency breaks ties. A known term is
+// only replaced by a candidate one edit away that is much more frequent.
+func (v *Vocabulary) Correct(term string, maxDistance int) (string, bool) {
+	term = strings.ToLower(term)
+	if len([]rune(term)) < minCorrectableLength || !isWord(term) {
+		return term, false
+	}
+
+	known := v.frequencies[term]
+	if known > 0 {
+		maxDistance = 1
+	}
+
+	best, bestDistance, bestFrequency := "", maxDistance+1, 0
+	termLength := len([]rune(term))
+	for candidate, frequency := range v.frequencies {
+		if candidate == term || abs(len([]rune(candidate))-termLength) > maxDistance {
+			continue
+		}
+		distance := EditDistance(term, candidate, maxDistance)
+		if distance > maxDistance {
+			continue
+		}
+		if distance < bestDistance ||
+			(distance == bestDistance && frequency > bestFrequency) ||
+			(distance == bestDistance && frequency == bestFrequency && candidate < best) {
+			best, bestDistance, bestFrequency = candidate, distance, frequency
+		}
+	}
+
+	if best == "" || (known > 0 && bestFrequency < known*knownTermRatio) {
+		return term, false
+	}
+	return best, true
+}
+
+// CorrectText corrects every token of a whitespace-separated text and reports whether any token changed.
+// The corrected text is lower-cased.
+func (v *Vocabulary) CorrectText(text string, maxDistance int) (string, bool) {
+	fields := strings.Fields(strings.ToLower(text))
+	changed := false
+	for i, field := range fields {
+		if corrected, ok := v.Correct(field, maxDistance); ok {
+			fields[i] = corrected
+			changed = true
+		}
+	}
+	return strings.Join(fields, " "), changed
+}
+
+// EditDistance returns the optimal string alignment distance between a and b, counting
+// insertions, deletions, substitutions and adjacent transpositions. Computation stops
+// early once the distance exceeds maxDistance, in which case maxDistance+1 is returned.
+func EditDistance(a, b string, maxDistance int) int {
+	s, t := []rune(a), []rune(b)
+	if abs(len(s)-len(t)) > maxDistance {
+		return maxDistance + 1
+	}
+
+	previous2 := make([]int, len(t)+1)
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				current[j] = min(current[j], previous2[j-2]+1)
+			}
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > maxDistance {
+			return maxDistance + 1
+		}
+		previous2, previous, current = previous, current, previous2
+	}
+
+	if previous[len(t)] > maxDistance {
+		return maxDistance + 1
+	}
+	return previous[len(t)]
+}
+
+// isWord reports whether a token consists only of letters
+func isWord(token string) bool {
+	for _, r := range token {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,45 @@
+package textutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into lower-cased tokens of letters and digits
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// TokenSet returns the distinct tokens among the first maxTokens tokens of text.
+// A maxTokens value of zero or less means no limit.
+func TokenSet(text string, maxTokens int) map[string]struct{} {
+	tokens := Tokenize(text)
+	if maxTokens > 0 && len(tokens) > maxTokens {
+		tokens = tokens[:maxTokens]
+	}
+	set := make(map[string]struct{}, len(tokens))
+	for _, token := range tokens {
+		set[token] = struct{}{}
+	}
+	return set
+}
+
+// Jaccard returns the Jaccard similarity of two token sets
+func Jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	intersection := 0
+	for token := range a {
+		if _, ok := b[token]; ok {
+			intersection++
+		}
+	}
+	union := len(a) + len(b) - intersection
+	return float64(intersection) / float64(union)
+}
@@ -0,0 +1,101 @@
+package textutil
+
+import (
+	"sort"
+	"strings"
+)
+
+// Completion is a term completed from a prefix together with its weight
+type Completion struct {
+	Term   string
+	Weight float64
+}
+
+// Trie is a prefix tree of weighted terms. It is not safe for concurrent writes.
+type Trie struct {
+	root *trieNode
+	size int
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	term     string
+	weight   float64
+	terminal bool
+}
+
+// NewTrie creates an empty trie
+func NewTrie() *Trie {
+	return &Trie{root: newTrieNode()}
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{children: make(map[rune]*trieNode)}
+}
+
+// Len returns the number of distinct terms in the trie
+func (t *Trie) Len() int {
+	return t.size
+}
+
+// Insert adds a term to the trie. Inserting an existing term adds to its weight.
+// Terms are matched case-insensitively and keep the casing they were first inserted with.
+func (t *Trie) Insert(term string, weight float64) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return
+	}
+
+	node := t.root
+	for _, r := range strings.ToLower(term) {
+		child, ok := node.children[r]
+		if !ok {
+			child = newTrieNode()
+			node.children[r] = child
+		}
+		node = child
+	}
+
+	if !node.terminal {
+		node.terminal = true
+		node.term = term
+		t.size++
+	}
+	node.weight += weight
+}
+
+// Complete returns up to limit terms starting with prefix, ordered by descending weight
+func (t *Trie) Complete(prefix string, limit int) []Completion {
+	node := t.root
+	for _, r := range strings.ToLower(prefix) {
+		child, ok := node.children[r]
+		if !ok {
+			return []Completion{}
+		}
+		node = child
+	}
+
+	completions := make([]Completion, 0)
+	node.collect(&completions)
+	sort.SliceStable(completions, func(i, j int) bool {
+		if completions[i].Weight != completions[j].Weight {
+			return completions[i].Weight > completions[j].Weight
+		}
+		return completions[i].Term < completions[j].Term
+	})
+
+	if limit > 0 && len(completions) > limit {
+		completions = completions[:limit]
+	}
+	return completions
+}
+
+// collect appends the terms of the subtree rooted at the node
+func (n *trieNode) collect(completions *[]Completion) {
+	if n.terminal {
+		*completions = append(*completions, Completion{Term: n.term, Weight: n.weight})
+	}
+	for _, child := range n.children {
+		child.collect(completions)
+	}
+}
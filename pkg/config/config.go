@@ -14,10 +14,11 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig
-	Elastic  ElasticConfig
-	Database DBConfig
-	Crawler  CrawlerConfig
+	Server       ServerConfig
+	Elastic      ElasticConfig
+	Database     DBConfig
+	Crawler      CrawlerConfig
+	Notification NotificationConfig
 }
 
 // ValidationError is returned when configuration validation fails
@@ -108,12 +109,13 @@ func LoadEnvFile(envFile string) (bool, string, error) {
 func Load() (*Config, error) {
 	config := &Config{
 		Server: ServerConfig{
-			Port:            getEnvInt("SERVER_PORT", 8080),
-			ReadTimeout:     getEnvDuration("SERVER_READ_TIMEOUT", 10*time.Second),
-			WriteTimeout:    getEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
-			ShutdownTimeout: getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 10*time.Second),
-			LogLevel:        getEnvStr("SERVER_LOG_LEVEL", "info"),
-			AllowOrigins:    getEnvStringSlice("SERVER_ALLOW_ORIGINS", []string{"*"}),
+			Port:             getEnvInt("SERVER_PORT", 8080),
+			ReadTimeout:      getEnvDuration("SERVER_READ_TIMEOUT", 10*time.Second),
+			WriteTimeout:     getEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			ShutdownTimeout:  getEnvDuration("SERVER_SHUTDOWN_TIMEOUT", 10*time.Second),
+			LogLevel:         getEnvStr("SERVER_LOG_LEVEL", "info"),
+			AllowOrigins:     getEnvStringSlice("SERVER_ALLOW_ORIGINS", []string{"*"}),
+			TrustUserHeaders: getEnvBool("SERVER_TRUST_USER_HEADERS", false),
 		},
 		Elastic: ElasticConfig{
 			URL:              getEnvStr("ELASTICSEARCH_URL", "http://elasticsearch:9200"),
@@ -155,6 +157,17 @@ func Load() (*Config, error) {
 			AllowedDomains:  getEnvStringSlice("CRAWLER_ALLOWED_DOMAINS", []string{}),
 			ExcludedPaths:   getEnvStringSlice("CRAWLER_EXCLUDED_PATHS", []string{}),
 		},
+		Notification: NotificationConfig{
+			WebhookTimeout:  getEnvDuration("NOTIFICATION_WEBHOOK_TIMEOUT", 10*time.Second),
+			SMTPHost:        getEnvStr("NOTIFICATION_SMTP_HOST", ""),
+			SMTPPort:        getEnvInt("NOTIFICATION_SMTP_PORT", 587),
+			SMTPUsername:    getEnvStr("NOTIFICATION_SMTP_USERNAME", ""),
+			SMTPPassword:    getEnvStr("NOTIFICATION_SMTP_PASSWORD", ""),
+			SMTPFrom:        getEnvStr("NOTIFICATION_SMTP_FROM", ""),
+			AlertFilePath:   getEnvStr("NOTIFICATION_ALERT_FILE_PATH", ""),
+			MatchWebhookURL: getEnvStr("NOTIFICATION_MATCH_WEBHOOK_URL", ""),
+			MatchFilePath:   getEnvStr("NOTIFICATION_MATCH_FILE_PATH", ""),
+		},
 	}
 
 	if err := config.Validate(); err != nil {
@@ -175,6 +188,11 @@ func applyEnvironmentOverrides(config *Config) {
 	if val := os.Getenv("SERVER_LOG_LEVEL"); val != "" {
 		config.Server.LogLevel = val
 	}
+	if val := os.Getenv("SERVER_TRUST_USER_HEADERS"); val != "" {
+		if boolVal, err := strconv.ParseBool(val); err == nil {
+			config.Server.TrustUserHeaders = boolVal
+		}
+	}
 
 	// Elasticsearch overrides
 	if val := os.Getenv("ELASTICSEARCH_URL"); val != "" {
@@ -217,6 +235,23 @@ func applyEnvironmentOverrides(config *Config) {
 			config.Crawler.RespectRobotsTx = boolVal
 		}
 	}
+
+	// Notification overrides
+	if val := os.Getenv("NOTIFICATION_SMTP_HOST"); val != "" {
+		config.Notification.SMTPHost = val
+	}
+	if val := os.Getenv("NOTIFICATION_SMTP_PASSWORD"); val != "" {
+		config.Notification.SMTPPassword = val
+	}
+	if val := os.Getenv("NOTIFICATION_ALERT_FILE_PATH"); val != "" {
+		config.Notification.AlertFilePath = val
+	}
+	if val := os.Getenv("NOTIFICATION_MATCH_WEBHOOK_URL"); val != "" {
+		config.Notification.MatchWebhookURL = val
+	}
+	if val := os.Getenv("NOTIFICATION_MATCH_FILE_PATH"); val != "" {
+		config.Notification.MatchFilePath = val
+	}
 }
 
 func getEnvStr(key, fallback string) string {
@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+// NotificationConfig contains the settings of the notifiers delivering saved search alerts and
+// of the subscribers receiving standing query matches. Email alerts are available when an SMTP
+// host is set, file alerts when a file path is set. Matches are posted to the match webhook
+// and appended to the match file when those are set.
+type NotificationConfig struct {
+	WebhookTimeout  time.Duration `mapstructure:"webhook_timeout" yaml:"webhook_timeout" default:"10s"`
+	SMTPHost        string        `mapstructure:"smtp_host" yaml:"smtp_host" default:""`
+	SMTPPort        int           `mapstructure:"smtp_port" yaml:"smtp_port" default:"587"`
+	SMTPUsername    string        `mapstructure:"smtp_username" yaml:"smtp_username" default:""`
+	SMTPPassword    string        `mapstructure:"smtp_password" yaml:"smtp_password" default:""`
+	SMTPFrom        string        `mapstructure:"smtp_from" yaml:"smtp_from" default:""`
+	AlertFilePath   string        `mapstructure:"alert_file_path" yaml:"alert_file_path" default:""`
+	MatchWebhookURL string        `mapstructure:"match_webhook_url" yaml:"match_webhook_url" default:""`
+	MatchFilePath   string        `mapstructure:"match_file_path" yaml:"match_file_path" default:""`
+}
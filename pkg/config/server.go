@@ -10,6 +10,8 @@ type ServerConfig struct {
 	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout" yaml:"shutdown_timeout" default:"5s"`
 	LogLevel        string        `mapstructure:"log_level" yaml:"log_level" default:"info"`
 	AllowOrigins    []string      `mapstructure:"allow_origins" yaml:"allow_origins" default:"*"`
+	// TrustUserHeaders authenticates requests with the identity headers of a reverse proxy
+	TrustUserHeaders bool `mapstructure:"trust_user_headers" yaml:"trust_user_headers" default:"false"`
 }
 
 // Validate checks if the server configuration is valid